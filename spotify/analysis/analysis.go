@@ -0,0 +1,165 @@
+// Package analysis derives beat grids and tempo maps from a spotify.AudioAnalysis
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Beat is a beat of the grid, times are in seconds
+type Beat struct {
+	Start      float32
+	Duration   float32
+	Confidence float32
+
+	// Bar is the index of the bar the beat falls in, or -1 if no bar covers it
+	Bar int
+
+	// Downbeat is set when the beat is the first in its bar
+	Downbeat bool
+}
+
+// Grid is the beat grid of a track
+type Grid struct {
+	Beats []Beat
+	Bars  []*spotify.Interval
+}
+
+// TempoPoint is the tempo of the track from a time on
+type TempoPoint struct {
+	Time       float32
+	Tempo      float32
+	Confidence float32
+}
+
+// downbeatTolerance is how far in seconds a beat may start from its bar and still be its downbeat
+const downbeatTolerance = 0.05
+
+// NewGrid builds the beat grid of the analysis, assigning every beat to its bar and marking the downbeats
+// A beat is a downbeat when it starts within downbeatTolerance of a bar, even slightly before it.
+func NewGrid(a *spotify.AudioAnalysis) *Grid {
+	g := &Grid{Bars: a.Bars, Beats: make([]Beat, 0, len(a.Beats))}
+	for _, b := range a.Beats {
+		bar, _ := a.BarAt(b.Start)
+		beat := Beat{Start: b.Start, Duration: b.Duration, Confidence: b.Confidence, Bar: bar}
+
+		if n := nearestStart(a.Bars, b.Start); n >= 0 && abs(b.Start-a.Bars[n].Start) <= downbeatTolerance {
+			beat.Bar, beat.Downbeat = n, true
+		}
+		g.Beats = append(g.Beats, beat)
+	}
+	return g
+}
+
+// Downbeats returns the beats that start a bar
+func (g *Grid) Downbeats() []Beat {
+	var downbeats []Beat
+	for _, b := range g.Beats {
+		if b.Downbeat {
+			downbeats = append(downbeats, b)
+		}
+	}
+	return downbeats
+}
+
+// QuantizeBeat returns the start of the beat nearest to the time in seconds
+// ok is false if the grid has no beats.
+func (g *Grid) QuantizeBeat(t float32) (start float32, ok bool) {
+	starts := make([]float32, len(g.Beats))
+	for i, b := range g.Beats {
+		starts[i] = b.Start
+	}
+
+	i := nearest(starts, t)
+	if i < 0 {
+		return t, false
+	}
+	return starts[i], true
+}
+
+// QuantizeBar returns the start of the bar nearest to the time in seconds
+// ok is false if the grid has no bars.
+func (g *Grid) QuantizeBar(t float32) (start float32, ok bool) {
+	i := nearestStart(g.Bars, t)
+	if i < 0 {
+		return t, false
+	}
+	return g.Bars[i].Start, true
+}
+
+// TempoCurve returns the tempo over time from the sections of the analysis
+// Sections with a tempo confidence below minConfidence take the tempo of the point before them,
+// or the track tempo for the first one. Consecutive sections with the same tempo are merged.
+func TempoCurve(a *spotify.AudioAnalysis, minConfidence float32) []TempoPoint {
+	var curve []TempoPoint
+	var last TempoPoint
+	if a.Track != nil {
+		last = TempoPoint{Tempo: a.Track.Tempo, Confidence: a.Track.TempoConfidence}
+	}
+
+	for _, s := range a.Sections {
+		p := TempoPoint{Time: s.Start, Tempo: s.Tempo, Confidence: s.TempoConfidence}
+		if s.TempoConfidence < minConfidence {
+			p.Tempo, p.Confidence = last.Tempo, last.Confidence
+		}
+
+		if len(curve) > 0 && curve[len(curve)-1].Tempo == p.Tempo {
+			continue
+		}
+		curve = append(curve, p)
+		last = p
+	}
+	return curve
+}
+
+// TempoAt returns the tempo of the curve at the time in seconds, or 0 if the curve is empty
+func TempoAt(curve []TempoPoint, t float32) float32 {
+	i := sort.Search(len(curve), func(i int) bool { return curve[i].Time > t }) - 1
+	if i < 0 {
+		if len(curve) == 0 {
+			return 0
+		}
+		i = 0
+	}
+	return curve[i].Tempo
+}
+
+// ConfidentSegments returns the segments with at least minConfidence
+func ConfidentSegments(a *spotify.AudioAnalysis, minConfidence float32) []*spotify.Segment {
+	var segments []*spotify.Segment
+	for _, s := range a.Segments {
+		if s.Confidence >= minConfidence {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+func nearestStart(intervals []*spotify.Interval, t float32) int {
+	starts := make([]float32, len(intervals))
+	for i, iv := range intervals {
+		starts[i] = iv.Start
+	}
+	return nearest(starts, t)
+}
+
+// nearest returns the index of the value in the sorted values nearest to t, or -1 if there are none
+func nearest(values []float32, t float32) int {
+	if len(values) == 0 {
+		return -1
+	}
+
+	i := sort.Search(len(values), func(i int) bool { return values[i] >= t })
+	if i == len(values) {
+		return i - 1
+	}
+
+	if i > 0 && t-values[i-1] <= values[i]-t {
+		return i - 1
+	}
+	return i
+}
+
+func abs(f float32) float32 { return float32(math.Abs(float64(f))) }
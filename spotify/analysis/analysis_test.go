@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// loadAnalysis reads the fixture, 17 beats every 0.5s from 0, four 2s bars from 0.52
+// and sections at 120, 120, 126 (low confidence) and 132 bpm
+func loadAnalysis(t *testing.T) *spotify.AudioAnalysis {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "audio_analysis.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &spotify.AudioAnalysis{}
+	if err := json.Unmarshal(data, a); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestNewGrid(t *testing.T) {
+	g := NewGrid(loadAnalysis(t))
+	if len(g.Beats) != 17 || len(g.Bars) != 4 {
+		t.Fatalf("got %d beats and %d bars", len(g.Beats), len(g.Bars))
+	}
+
+	tests := []struct {
+		beat     int
+		bar      int
+		downbeat bool
+	}{
+		{0, -1, false}, // pickup before the first bar
+		{1, 0, true},   // slightly before the bar, within the tolerance
+		{2, 0, false},
+		{5, 1, true}, // BarAt says bar 0, the downbeat moves it to bar 1
+		{6, 1, false},
+		{16, 3, false},
+	}
+	for _, tt := range tests {
+		b := g.Beats[tt.beat]
+		if b.Bar != tt.bar || b.Downbeat != tt.downbeat {
+			t.Errorf("beat %d at %v: bar %d downbeat %v, want bar %d downbeat %v", tt.beat, b.Start, b.Bar, b.Downbeat, tt.bar, tt.downbeat)
+		}
+	}
+}
+
+func TestDownbeats(t *testing.T) {
+	var starts []float32
+	for _, b := range NewGrid(loadAnalysis(t)).Downbeats() {
+		starts = append(starts, b.Start)
+	}
+
+	if want := []float32{0.5, 2.5, 4.5, 6.5}; !reflect.DeepEqual(starts, want) {
+		t.Errorf("Downbeats() = %v, want %v", starts, want)
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	g := NewGrid(loadAnalysis(t))
+
+	beats := []struct{ t, want float32 }{
+		{-3, 0},
+		{1.2, 1},
+		{1.25, 1}, // ties go to the earlier beat
+		{1.26, 1.5},
+		{100, 8},
+	}
+	for _, tt := range beats {
+		if got, ok := g.QuantizeBeat(tt.t); !ok || got != tt.want {
+			t.Errorf("QuantizeBeat(%v) = %v, %v, want %v", tt.t, got, ok, tt.want)
+		}
+	}
+
+	bars := []struct{ t, want float32 }{
+		{0, 0.52},
+		{3, 2.52},
+		{3.6, 4.52},
+		{100, 6.52},
+	}
+	for _, tt := range bars {
+		if got, ok := g.QuantizeBar(tt.t); !ok || got != tt.want {
+			t.Errorf("QuantizeBar(%v) = %v, %v, want %v", tt.t, got, ok, tt.want)
+		}
+	}
+
+	empty := NewGrid(&spotify.AudioAnalysis{})
+	if got, ok := empty.QuantizeBeat(1.2); ok || got != 1.2 {
+		t.Errorf("QuantizeBeat on an empty grid = %v, %v", got, ok)
+	}
+	if got, ok := empty.QuantizeBar(1.2); ok || got != 1.2 {
+		t.Errorf("QuantizeBar on an empty grid = %v, %v", got, ok)
+	}
+}
+
+func TestTempoCurve(t *testing.T) {
+	a := loadAnalysis(t)
+
+	tests := []struct {
+		minConfidence float32
+		want          []TempoPoint
+	}{
+		{0, []TempoPoint{{0, 120, 0.9}, {5, 126, 0.2}, {7, 132, 0.7}}},
+		{0.5, []TempoPoint{{0, 120, 0.9}, {7, 132, 0.7}}},
+		{0.95, []TempoPoint{{0, 121, 0.6}}},
+	}
+	for _, tt := range tests {
+		if got := TempoCurve(a, tt.minConfidence); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TempoCurve(%v) = %v, want %v", tt.minConfidence, got, tt.want)
+		}
+	}
+}
+
+func TestTempoAt(t *testing.T) {
+	curve := TempoCurve(loadAnalysis(t), 0)
+
+	tests := []struct{ t, want float32 }{
+		{-1, 120},
+		{4.9, 120},
+		{5, 126},
+		{6.99, 126},
+		{7, 132},
+		{100, 132},
+	}
+	for _, tt := range tests {
+		if got := TempoAt(curve, tt.t); got != tt.want {
+			t.Errorf("TempoAt(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+
+	if got := TempoAt(nil, 1); got != 0 {
+		t.Errorf("TempoAt on an empty curve = %v", got)
+	}
+}
+
+func TestConfidentSegments(t *testing.T) {
+	a := loadAnalysis(t)
+
+	tests := []struct {
+		minConfidence float32
+		want          []float32
+	}{
+		{0, []float32{0, 0.5, 1}},
+		{0.5, []float32{0, 1}},
+		{0.6, []float32{0, 1}},
+		{0.95, nil},
+	}
+	for _, tt := range tests {
+		var starts []float32
+		for _, s := range ConfidentSegments(a, tt.minConfidence) {
+			starts = append(starts, s.Start)
+		}
+		if !reflect.DeepEqual(starts, tt.want) {
+			t.Errorf("ConfidentSegments(%v) = %v, want %v", tt.minConfidence, starts, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package spotify
+
+import "sort"
+
+// End returns the time in seconds the interval ends at
+func (i *Interval) End() float32 { return i.Start + i.Duration }
+
+// Contains checks if the time in seconds falls within the interval
+func (i *Interval) Contains(t float32) bool { return t >= i.Start && t < i.End() }
+
+func (s *Section) End() float32 { return s.Start + s.Duration }
+
+func (s *Segment) End() float32 { return s.Start + s.Duration }
+
+// indexAt returns the index of the last of n intervals sorted by start that starts at or before t,
+// or -1 if t is before the first one or at or after the end of the one found
+func indexAt(n int, t float32, start, end func(i int) float32) int {
+	i := sort.Search(n, func(i int) bool { return start(i) > t }) - 1
+	if i < 0 || t >= end(i) {
+		return -1
+	}
+	return i
+}
+
+func intervalAt(intervals []*Interval, t float32) (int, *Interval) {
+	i := indexAt(len(intervals), t,
+		func(i int) float32 { return intervals[i].Start },
+		func(i int) float32 { return intervals[i].End() })
+	if i < 0 {
+		return -1, nil
+	}
+	return i, intervals[i]
+}
+
+// BarAt returns the index of and the bar at the time in seconds, or -1 and nil if no bar covers it
+func (a *AudioAnalysis) BarAt(t float32) (int, *Interval) { return intervalAt(a.Bars, t) }
+
+// BeatAt returns the index of and the beat at the time in seconds, or -1 and nil if no beat covers it
+func (a *AudioAnalysis) BeatAt(t float32) (int, *Interval) { return intervalAt(a.Beats, t) }
+
+// TatumAt returns the index of and the tatum at the time in seconds, or -1 and nil if no tatum covers it
+func (a *AudioAnalysis) TatumAt(t float32) (int, *Interval) { return intervalAt(a.Tatums, t) }
+
+// SectionAt returns the index of and the section at the time in seconds, or -1 and nil if no section covers it
+func (a *AudioAnalysis) SectionAt(t float32) (int, *Section) {
+	i := indexAt(len(a.Sections), t,
+		func(i int) float32 { return a.Sections[i].Start },
+		func(i int) float32 { return a.Sections[i].End() })
+	if i < 0 {
+		return -1, nil
+	}
+	return i, a.Sections[i]
+}
+
+// SegmentAt returns the index of and the segment at the time in seconds, or -1 and nil if no segment covers it
+func (a *AudioAnalysis) SegmentAt(t float32) (int, *Segment) {
+	i := indexAt(len(a.Segments), t,
+		func(i int) float32 { return a.Segments[i].Start },
+		func(i int) float32 { return a.Segments[i].End() })
+	if i < 0 {
+		return -1, nil
+	}
+	return i, a.Segments[i]
+}
@@ -0,0 +1,142 @@
+package spotify
+
+import (
+	"net/url"
+)
+
+// GetAudiobook gets an audiobook
+// Market: optional ISO 3166-1 alpha-2 country code, audiobooks are only available in some markets
+func (c *Client) GetAudiobook(id ID, market string) (*Audiobook, error) {
+	res, err := c.request("GET", withValues(EndpointGetAudiobook(string(id)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &Audiobook{}
+	err = unmarshal(res, book)
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// GetAudiobooks gets several audiobooks, at most 50
+// Audiobooks that don't exist or aren't available in the market are nil
+func (c *Client) GetAudiobooks(ids []ID, market string) ([]*Audiobook, error) {
+	res, err := c.request("GET", withValues(EndpointGetAudiobooks(idStrings(ids)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Audiobooks []*Audiobook `json:"audiobooks"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Audiobooks, nil
+}
+
+// GetAudiobookChapters gets a page of the chapters of an audiobook
+// The items of the page are Chapters
+func (c *Client) GetAudiobookChapters(id ID, market string, limit, offset int) (*Paging, error) {
+	vals := marketValues(market)
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetAudiobookChapters(string(id)), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetChapter gets an audiobook chapter
+func (c *Client) GetChapter(id ID, market string) (*Chapter, error) {
+	res, err := c.request("GET", withValues(EndpointGetChapter(string(id)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	chapter := &Chapter{}
+	err = unmarshal(res, chapter)
+	if err != nil {
+		return nil, err
+	}
+	return chapter, nil
+}
+
+// GetChapters gets several audiobook chapters, at most 50
+func (c *Client) GetChapters(ids []ID, market string) ([]*Chapter, error) {
+	res, err := c.request("GET", withValues(EndpointGetChapters(idStrings(ids)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Chapters []*Chapter `json:"chapters"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Chapters, nil
+}
+
+// GetSavedAudiobooks gets a page of the audiobooks saved in the user's library
+// The items of the page are SavedAudiobooks
+func (c *Client) GetSavedAudiobooks(limit, offset int) (*Paging, error) {
+	vals := url.Values{}
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetSavedAudiobooks(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (c *Client) SaveAudiobooks(ids []ID) error {
+	res, err := c.request("PUT", EndpointSaveAudiobooks(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+func (c *Client) RemoveSavedAudiobooks(ids []ID) error {
+	res, err := c.request("DELETE", EndpointRemoveSavedAudiobooks(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// HasAudiobooksSaved checks if the audiobooks are saved in the user's library
+func (c *Client) HasAudiobooksSaved(ids []ID) ([]bool, error) {
+	res, err := c.request("GET", EndpointHasAudiobooksSaved(idStrings(ids)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var bools []bool
+	err = unmarshal(res, &bools)
+	if err != nil {
+		return nil, err
+	}
+	return bools, nil
+}
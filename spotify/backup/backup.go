@@ -0,0 +1,122 @@
+// Package backup snapshots a user's library and playlists into a versioned JSON archive
+// and restores it onto another account. Both need a client from spotify.NewWithToken.
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Version is the archive format written by Backup, Read accepts it and older versions
+const Version = 1
+
+// Archive is everything Backup saves
+type Archive struct {
+	Version         int                   `json:"version"`
+	CreatedAt       time.Time             `json:"created_at"`
+	User            *spotify.User         `json:"user"`
+	SavedTracks     []*spotify.SavedTrack `json:"saved_tracks"`
+	SavedAlbums     []*spotify.SavedAlbum `json:"saved_albums"`
+	SavedShows      []*spotify.SavedShow  `json:"saved_shows"`
+	FollowedArtists []*spotify.FullArtist `json:"followed_artists"`
+	Playlists       []*Playlist           `json:"playlists"`
+}
+
+// Playlist is a playlist the user owns or follows with all its items
+type Playlist struct {
+	Playlist *spotify.SimplePlaylist  `json:"playlist"`
+	Owned    bool                     `json:"owned"`
+	Items    []*spotify.PlaylistTrack `json:"items"`
+}
+
+// Progress is told about every step, done of total for the named part
+type Progress func(part string, done, total int)
+
+func report(p Progress, part string, done, total int) {
+	if p != nil {
+		p(part, done, total)
+	}
+}
+
+// Source is what Backup reads the library and playlists from
+type Source interface {
+	spotify.Library
+	spotify.Playlists
+}
+
+// Backup snapshots the profile, saved tracks, albums and shows, followed artists,
+// and every playlist the user owns or follows with its items
+func Backup(c Source, progress Progress) (*Archive, error) {
+	a := &Archive{Version: Version, CreatedAt: time.Now().UTC()}
+
+	var err error
+	if a.User, err = c.GetCurrentUser(); err != nil {
+		return nil, err
+	}
+	report(progress, "profile", 1, 1)
+
+	if a.SavedTracks, err = c.AllSavedTracks(""); err != nil {
+		return nil, err
+	}
+	report(progress, "saved tracks", len(a.SavedTracks), len(a.SavedTracks))
+
+	if a.SavedAlbums, err = c.AllSavedAlbums(""); err != nil {
+		return nil, err
+	}
+	report(progress, "saved albums", len(a.SavedAlbums), len(a.SavedAlbums))
+
+	if a.SavedShows, err = c.AllSavedShows(); err != nil {
+		return nil, err
+	}
+	report(progress, "saved shows", len(a.SavedShows), len(a.SavedShows))
+
+	if a.FollowedArtists, err = c.AllFollowedArtists(); err != nil {
+		return nil, err
+	}
+	report(progress, "followed artists", len(a.FollowedArtists), len(a.FollowedArtists))
+
+	playlists, err := c.AllMyPlaylists()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, pl := range playlists {
+		owner := a.User.ID
+		if pl.Owner != nil {
+			owner = pl.Owner.ID
+		}
+
+		items, err := c.AllPlaylistTracks(owner, pl.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Backing up playlist %s: %v", pl.Name, err)
+		}
+
+		a.Playlists = append(a.Playlists, &Playlist{Playlist: pl, Owned: owner == a.User.ID, Items: items})
+		report(progress, "playlists", i+1, len(playlists))
+	}
+	return a, nil
+}
+
+// Write writes the archive as indented JSON
+func (a *Archive) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// Read reads an archive, archives of a newer version than this package knows are rejected
+func Read(r io.Reader) (*Archive, error) {
+	a := &Archive{}
+	if err := json.NewDecoder(r).Decode(a); err != nil {
+		return nil, err
+	}
+
+	if a.Version < 1 || a.Version > Version {
+		return nil, fmt.Errorf("Unsupported archive version %d, this package reads up to %d", a.Version, Version)
+	}
+	return a, nil
+}
@@ -0,0 +1,438 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Missing is an item of the archive that couldn't be restored
+// Context is the name of the playlist it was in, if any.
+type Missing struct {
+	Kind    spotify.Kind `json:"kind"`
+	ID      spotify.ID   `json:"id"`
+	Name    string       `json:"name"`
+	Context string       `json:"context,omitempty"`
+	Reason  string       `json:"reason"`
+}
+
+// State is the progress of a restore, pass it back in Options to resume after a failure
+// It's plain JSON so it can be persisted from the Checkpoint callback.
+// Archive is the fingerprint of the archive being restored and User the account it's restored to,
+// a state is only resumed with the same ones.
+type State struct {
+	Archive   string                    `json:"archive"`
+	User      spotify.ID                `json:"user"`
+	Done      map[string]bool           `json:"done"`
+	Playlists map[spotify.ID]spotify.ID `json:"playlists"`
+	Missing   []Missing                 `json:"missing"`
+}
+
+// Options configure Restore
+type Options struct {
+	Progress Progress
+
+	// State resumes an earlier restore of the same archive, nil starts over
+	State *State
+
+	// Checkpoint is called with the state after every step that changed the account
+	Checkpoint func(*State) error
+}
+
+// Report is the outcome of a restore
+type Report struct {
+	Missing []Missing
+	State   *State
+}
+
+// Target is what Restore writes the library and playlists to, it looks the items up in the catalog first
+type Target interface {
+	spotify.Catalog
+	spotify.Library
+	spotify.Playlists
+}
+
+type restorer struct {
+	c     Target
+	a     *Archive
+	opts  Options
+	state *State
+	user  spotify.ID
+
+	// pending are the missing items of the running step, they are only kept when it's done
+	pending []Missing
+}
+
+// Restore replays the archive onto the account of the client
+// Saved items are restored oldest first so the library keeps its order, owned playlists are recreated with
+// their items and followed playlists are followed again. Items that no longer exist or can't be added,
+// like local files, are reported instead of failing the restore.
+func Restore(c Target, a *Archive, opts *Options) (*Report, error) {
+	r := &restorer{c: c, a: a}
+	if opts != nil {
+		r.opts = *opts
+	}
+
+	fp, err := fingerprint(a)
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := c.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	r.user = me.ID
+
+	r.state = r.opts.State
+	if r.state == nil {
+		r.state = &State{}
+	}
+
+	// a state that has started resumes nothing but its own restore, it would skip or repeat the wrong steps
+	if len(r.state.Done) > 0 || len(r.state.Playlists) > 0 || len(r.state.Missing) > 0 {
+		if r.state.Archive != fp {
+			return nil, fmt.Errorf("Can't resume the restore: the state is from another archive")
+		}
+		if r.state.User != r.user {
+			return nil, fmt.Errorf("Can't resume the restore: the state is from a restore to %s, not %s", r.state.User, r.user)
+		}
+	}
+	r.state.Archive, r.state.User = fp, r.user
+
+	if r.state.Done == nil {
+		r.state.Done = make(map[string]bool)
+	}
+
+	if r.state.Playlists == nil {
+		r.state.Playlists = make(map[spotify.ID]spotify.ID)
+	}
+
+	steps := []func() error{r.tracks, r.albums, r.shows, r.artists, r.playlists}
+	for _, step := range steps {
+		if err = step(); err != nil {
+			return &Report{Missing: r.state.Missing, State: r.state}, err
+		}
+	}
+	return &Report{Missing: r.state.Missing, State: r.state}, nil
+}
+
+// fingerprint is the SHA-256 of the archive's JSON
+func fingerprint(a *Archive) (string, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// step runs fn once for the key, skipping it when a resumed state has it done
+// The items fn reports missing are recorded with the step, so a failed step that is run again doesn't repeat them.
+func (r *restorer) step(key string, fn func() error) error {
+	if r.state.Done[key] {
+		return nil
+	}
+
+	r.pending = nil
+	if err := fn(); err != nil {
+		return err
+	}
+
+	r.state.Missing = append(r.state.Missing, r.pending...)
+	r.pending = nil
+	r.state.Done[key] = true
+	if r.opts.Checkpoint != nil {
+		return r.opts.Checkpoint(r.state)
+	}
+	return nil
+}
+
+func (r *restorer) missing(kind spotify.Kind, id spotify.ID, name, context, reason string) {
+	r.pending = append(r.pending, Missing{Kind: kind, ID: id, Name: name, Context: context, Reason: reason})
+}
+
+// chunks calls fn with the ranges of n items in chunks of size
+func chunks(n, size int, fn func(i, lo, hi int) error) error {
+	for i, lo := 0, 0; lo < n; i, lo = i+1, lo+size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+
+		if err := fn(i, lo, hi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *restorer) tracks() error {
+	// the archive is newest first, save the oldest first
+	var ids []spotify.ID
+	names := make(map[spotify.ID]string)
+	for i := len(r.a.SavedTracks) - 1; i >= 0; i-- {
+		if t := r.a.SavedTracks[i].Track; t != nil {
+			ids = append(ids, t.ID)
+			names[t.ID] = t.Name
+		}
+	}
+
+	return chunks(len(ids), 50, func(i, lo, hi int) error {
+		return r.step(fmt.Sprintf("tracks:%d", i), func() error {
+			found, err := r.c.GetTracks(ids[lo:hi], "")
+			if err != nil {
+				return err
+			}
+
+			var keep []spotify.ID
+			for j, t := range found {
+				if t == nil {
+					r.missing(spotify.KindTrack, ids[lo+j], names[ids[lo+j]], "", "no longer exists")
+					continue
+				}
+				keep = append(keep, ids[lo+j])
+			}
+
+			report(r.opts.Progress, "saved tracks", hi, len(ids))
+			if len(keep) == 0 {
+				return nil
+			}
+			return r.c.SaveTracks(keep)
+		})
+	})
+}
+
+func (r *restorer) albums() error {
+	var ids []spotify.ID
+	names := make(map[spotify.ID]string)
+	for i := len(r.a.SavedAlbums) - 1; i >= 0; i-- {
+		if a := r.a.SavedAlbums[i].Album; a != nil {
+			ids = append(ids, a.ID)
+			names[a.ID] = a.Name
+		}
+	}
+
+	return chunks(len(ids), 20, func(i, lo, hi int) error {
+		return r.step(fmt.Sprintf("albums:%d", i), func() error {
+			found, err := r.c.GetAlbums(ids[lo:hi])
+			if err != nil {
+				return err
+			}
+
+			var keep []spotify.ID
+			for j, a := range found {
+				if a == nil {
+					r.missing(spotify.KindAlbum, ids[lo+j], names[ids[lo+j]], "", "no longer exists")
+					continue
+				}
+				keep = append(keep, ids[lo+j])
+			}
+
+			report(r.opts.Progress, "saved albums", hi, len(ids))
+			if len(keep) == 0 {
+				return nil
+			}
+			return r.c.SaveAlbums(keep)
+		})
+	})
+}
+
+func (r *restorer) shows() error {
+	var ids []spotify.ID
+	names := make(map[spotify.ID]string)
+	for i := len(r.a.SavedShows) - 1; i >= 0; i-- {
+		if s := r.a.SavedShows[i].Show; s != nil {
+			ids = append(ids, s.ID)
+			names[s.ID] = s.Name
+		}
+	}
+
+	return chunks(len(ids), 50, func(i, lo, hi int) error {
+		return r.step(fmt.Sprintf("shows:%d", i), func() error {
+			found, err := r.c.GetShows(ids[lo:hi], "")
+			if err != nil {
+				return err
+			}
+
+			var keep []spotify.ID
+			for j, s := range found {
+				if s == nil {
+					r.missing(spotify.KindShow, ids[lo+j], names[ids[lo+j]], "", "no longer exists")
+					continue
+				}
+				keep = append(keep, ids[lo+j])
+			}
+
+			report(r.opts.Progress, "saved shows", hi, len(ids))
+			if len(keep) == 0 {
+				return nil
+			}
+			return r.c.SaveShows(keep)
+		})
+	})
+}
+
+func (r *restorer) artists() error {
+	var ids []spotify.ID
+	names := make(map[spotify.ID]string)
+	for _, a := range r.a.FollowedArtists {
+		ids = append(ids, a.ID)
+		names[a.ID] = a.Name
+	}
+
+	return chunks(len(ids), 50, func(i, lo, hi int) error {
+		return r.step(fmt.Sprintf("artists:%d", i), func() error {
+			found, err := r.c.GetArtists(ids[lo:hi])
+			if err != nil {
+				return err
+			}
+
+			var keep []spotify.ID
+			for j, a := range found {
+				if a == nil {
+					r.missing(spotify.KindArtist, ids[lo+j], names[ids[lo+j]], "", "no longer exists")
+					continue
+				}
+				keep = append(keep, ids[lo+j])
+			}
+
+			report(r.opts.Progress, "followed artists", hi, len(ids))
+			if len(keep) == 0 {
+				return nil
+			}
+			return r.c.FollowArtists(keep)
+		})
+	})
+}
+
+func (r *restorer) playlists() error {
+	for n, p := range r.a.Playlists {
+		var err error
+		if p.Owned {
+			err = r.ownedPlaylist(p)
+		} else {
+			err = r.followedPlaylist(p)
+		}
+
+		if err != nil {
+			return err
+		}
+		report(r.opts.Progress, "playlists", n+1, len(r.a.Playlists))
+	}
+	return nil
+}
+
+func (r *restorer) followedPlaylist(p *Playlist) error {
+	pl := p.Playlist
+	return r.step("follow:"+string(pl.ID), func() error {
+		owner := spotify.ID("")
+		if pl.Owner != nil {
+			owner = pl.Owner.ID
+		}
+
+		err := r.c.UserFollowPlaylist(owner, pl.ID)
+		if e, ok := err.(*spotify.SpotifyError); ok && e.Status == 404 {
+			r.missing(spotify.KindPlaylist, pl.ID, pl.Name, "", "no longer exists")
+			return nil
+		}
+		return err
+	})
+}
+
+func (r *restorer) ownedPlaylist(p *Playlist) error {
+	pl := p.Playlist
+	err := r.step("create:"+string(pl.ID), func() error {
+		created, err := r.c.CreatePlaylist(r.user, pl.Name, pl.Description, pl.Public)
+		if err != nil {
+			return err
+		}
+		r.state.Playlists[pl.ID] = created.ID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	target := r.state.Playlists[pl.ID]
+	return chunks(len(p.Items), spotify.MaxPlaylistItems, func(i, lo, hi int) error {
+		return r.step(fmt.Sprintf("items:%s:%d", pl.ID, i), func() error {
+			uris, err := r.available(p.Items[lo:hi], lo, pl.Name)
+			if err != nil || len(uris) == 0 {
+				return err
+			}
+
+			_, err = r.c.AddTracksToPlaylist(r.user, target, uris, -1)
+			return err
+		})
+	})
+}
+
+// available returns the URIs of the playlist items that still exist and can be added, reporting the rest
+// offset is the position of the first item in the playlist.
+func (r *restorer) available(items []*spotify.PlaylistTrack, offset int, context string) ([]spotify.URI, error) {
+	var tracks, episodes []spotify.ID
+	for _, item := range items {
+		switch {
+		case item.Track == nil:
+		case item.Track.Track != nil && !item.Track.Track.IsLocal:
+			tracks = append(tracks, item.Track.Track.ID)
+		case item.Track.Episode != nil:
+			episodes = append(episodes, item.Track.Episode.ID)
+		}
+	}
+
+	exists := make(map[spotify.ID]bool)
+	err := chunks(len(tracks), 50, func(_, lo, hi int) error {
+		found, err := r.c.GetTracks(tracks[lo:hi], "")
+		for j, t := range found {
+			exists[tracks[lo+j]] = t != nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = chunks(len(episodes), 50, func(_, lo, hi int) error {
+		found, err := r.c.GetEpisodes(episodes[lo:hi], "")
+		for j, e := range found {
+			exists[episodes[lo+j]] = e != nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var uris []spotify.URI
+	for i, item := range items {
+		switch {
+		case item.Track == nil || item.Track.Track == nil && item.Track.Episode == nil:
+			// the API sends items that are no longer available without the track, so there's nothing to name
+			r.missing("", "", "", context, fmt.Sprintf("unavailable item at position %d", offset+i))
+		case item.Track.Track != nil && item.Track.Track.IsLocal:
+			t := item.Track.Track
+			r.missing(spotify.KindTrack, t.ID, t.Name, context, "local files can't be added")
+		case item.Track.Track != nil:
+			t := item.Track.Track
+			if !exists[t.ID] {
+				r.missing(spotify.KindTrack, t.ID, t.Name, context, "no longer exists")
+				continue
+			}
+			uris = append(uris, t.URI)
+		case item.Track.Episode != nil:
+			e := item.Track.Episode
+			if !exists[e.ID] {
+				r.missing(spotify.KindEpisode, e.ID, e.Name, context, "no longer exists")
+				continue
+			}
+			uris = append(uris, e.URI)
+		}
+	}
+	return uris, nil
+}
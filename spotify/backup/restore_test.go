@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/spotifytest"
+)
+
+// failSave fails the first request saving tracks, after the lookups of its step went through
+type failSave struct {
+	base   http.RoundTripper
+	failed bool
+}
+
+func (t *failSave) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == "PUT" && req.URL.Path == "/v1/me/tracks" && !t.failed {
+		t.failed = true
+		return nil, http.ErrHandlerTimeout
+	}
+	return t.base.RoundTrip(req)
+}
+
+func TestRestoreResumeKeepsMissingOnce(t *testing.T) {
+	d := spotifytest.NewDataset("restorer")
+	artist := d.AddArtist("Miles Davis", "jazz")
+	d.AddAlbum(artist, "Kind of Blue", "So What")
+	s := spotifytest.NewServer(d)
+	defer s.Close()
+
+	gone := &spotify.FullTrack{}
+	gone.ID, gone.Name = "0000000000000000000000", "Gone"
+	a := &Archive{
+		Version: Version,
+		SavedTracks: []*spotify.SavedTrack{
+			{Track: d.Tracks[0]},
+			{Track: gone},
+		},
+	}
+
+	c := s.Client()
+	c.HTTPClient.Transport = &failSave{base: c.HTTPClient.Transport}
+	report, err := Restore(c, a, nil)
+	if err == nil {
+		t.Fatal("expected the save to fail")
+	}
+	if len(report.Missing) != 0 {
+		t.Errorf("failed step recorded %v", report.Missing)
+	}
+
+	report, err = Restore(c, a, &Options{State: report.State})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].ID != gone.ID {
+		t.Errorf("missing = %v, want only %s", report.Missing, gone.ID)
+	}
+	if saved := s.SavedTracks(); len(saved) != 1 || saved[0] != d.Tracks[0].ID {
+		t.Errorf("saved tracks = %v", saved)
+	}
+}
+
+func TestRestoreReportsUnavailableItems(t *testing.T) {
+	d := spotifytest.NewDataset("restorer")
+	artist := d.AddArtist("Miles Davis", "jazz")
+	d.AddAlbum(artist, "Kind of Blue", "So What", "Freddie Freeloader")
+	s := spotifytest.NewServer(d)
+	defer s.Close()
+
+	pl := &spotify.SimplePlaylist{ID: "37i9dQZF1DXbITWG1ZJKYt", Name: "Mix"}
+	a := &Archive{
+		Version: Version,
+		Playlists: []*Playlist{{
+			Playlist: pl,
+			Owned:    true,
+			Items: []*spotify.PlaylistTrack{
+				{Track: &spotify.PlaylistItem{Track: d.Tracks[0]}},
+				{},
+				{Track: &spotify.PlaylistItem{}},
+				{Track: &spotify.PlaylistItem{Track: d.Tracks[1]}},
+			},
+		}},
+	}
+
+	report, err := Restore(s.Client(), a, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Missing{
+		{Context: "Mix", Reason: "unavailable item at position 1"},
+		{Context: "Mix", Reason: "unavailable item at position 2"},
+	}
+	if !reflect.DeepEqual(report.Missing, want) {
+		t.Errorf("missing = %+v, want %+v", report.Missing, want)
+	}
+
+	items, _ := s.PlaylistTracks(report.State.Playlists[pl.ID])
+	if !reflect.DeepEqual(items, []spotify.ID{d.Tracks[0].ID, d.Tracks[1].ID}) {
+		t.Errorf("restored items = %v", items)
+	}
+}
+
+func TestRestoreRejectsAnotherState(t *testing.T) {
+	d := spotifytest.NewDataset("restorer")
+	artist := d.AddArtist("Miles Davis", "jazz")
+	d.AddAlbum(artist, "Kind of Blue", "So What", "Freddie Freeloader")
+	s := spotifytest.NewServer(d)
+	defer s.Close()
+
+	archive := func(tracks ...*spotify.FullTrack) *Archive {
+		a := &Archive{Version: Version}
+		for _, t := range tracks {
+			a.SavedTracks = append(a.SavedTracks, &spotify.SavedTrack{Track: t})
+		}
+		return a
+	}
+
+	c := s.Client()
+	c.HTTPClient.Transport = &failSave{base: c.HTTPClient.Transport}
+	first := archive(d.Tracks[0])
+	report, err := Restore(c, first, nil)
+	if err == nil {
+		t.Fatal("expected the save to fail")
+	}
+	report.State.Done["tracks:1"] = true
+
+	tests := []struct {
+		name    string
+		archive *Archive
+		user    spotify.ID
+		wantErr bool
+	}{
+		{"another archive", archive(d.Tracks[1]), "restorer", true},
+		{"another account", first, "someone", true},
+		{"an unstarted state", archive(d.Tracks[1]), "", false},
+		{"the same", first, "restorer", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &State{Archive: report.State.Archive, User: tt.user, Done: map[string]bool{}}
+			if tt.user != "" {
+				for k, v := range report.State.Done {
+					state.Done[k] = v
+				}
+			}
+
+			_, err := Restore(c, tt.archive, &Options{State: state})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
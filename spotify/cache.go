@@ -0,0 +1,161 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache stores the responses of GET requests for a Client
+// Implementations must be safe for concurrent use, see NewMemoryCache and NewDiskCache.
+type Cache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, r *CachedResponse)
+	Delete(key string)
+}
+
+// CachedResponse is a successful response kept in a Cache
+type CachedResponse struct {
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+	ETag    string      `json:"etag"`
+	Expires time.Time   `json:"expires"`
+}
+
+// Fresh reports whether the response can be used without asking the API
+func (r *CachedResponse) Fresh() bool {
+	return time.Now().Before(r.Expires)
+}
+
+func (r *CachedResponse) size() int64 {
+	n := int64(len(r.Body) + len(r.ETag))
+	for k, vs := range r.Header {
+		n += int64(len(k))
+		for _, v := range vs {
+			n += int64(len(v))
+		}
+	}
+	return n
+}
+
+func (r *CachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// cacheControl is the part of a Cache-Control header the client cares about
+type cacheControl struct {
+	noStore bool
+	noCache bool
+	maxAge  time.Duration
+}
+
+func parseCacheControl(h string) cacheControl {
+	var cc cacheControl
+	for _, part := range strings.Split(h, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case part == "no-store":
+			cc.noStore = true
+		case part == "no-cache":
+			cc.noCache = true
+		case strings.HasPrefix(part, "max-age="):
+			if n, err := strconv.Atoi(strings.TrimPrefix(part, "max-age=")); err == nil && n > 0 {
+				cc.maxAge = time.Duration(n) * time.Second
+			}
+		}
+	}
+	return cc
+}
+
+// expires returns when a response with the header stops being fresh
+func expires(h http.Header) time.Time {
+	cc := parseCacheControl(h.Get("Cache-Control"))
+	if cc.noCache {
+		return time.Time{}
+	}
+	return time.Now().Add(cc.maxAge)
+}
+
+// cacheKey keys a URL in the cache
+// Responses to user tokens can be private, so they are keyed by a hash of the token as well.
+func (c *Client) cacheKey(url string) string {
+	if !c.userToken {
+		return url
+	}
+
+	sum := sha256.Sum256([]byte(c.auth.AccessToken))
+	return hex.EncodeToString(sum[:8]) + " " + url
+}
+
+// cachedRequest makes a GET request through the cache
+// Fresh responses are served without a request, stale ones with an ETag are revalidated with If-None-Match
+// and served again on a 304. Successful responses are stored unless they are marked no-store or can't be
+// revalidated and have no max-age.
+func (c *Client) cachedRequest(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	key := c.cacheKey(url)
+	cached, ok := c.Cache.Get(key)
+	if ok && cached.Fresh() {
+		return cached.response(req), nil
+	}
+
+	if ok && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotModified && ok {
+		res.Body.Close()
+		cached.Expires = expires(res.Header)
+		c.Cache.Set(key, cached)
+		return cached.response(req), nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return res, nil
+	}
+
+	cc := parseCacheControl(res.Header.Get("Cache-Control"))
+	etag := res.Header.Get("ETag")
+	if cc.noStore || (etag == "" && (cc.maxAge == 0 || cc.noCache)) {
+		if ok {
+			c.Cache.Delete(key)
+		}
+		return res, nil
+	}
+
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &CachedResponse{Header: res.Header, Body: body, ETag: etag, Expires: expires(res.Header)}
+	c.Cache.Set(key, entry)
+	return entry.response(req), nil
+}
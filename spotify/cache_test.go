@@ -0,0 +1,284 @@
+package spotify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// toServer sends the requests for the API to a test server
+type toServer struct {
+	target *url.URL
+}
+
+func (t *toServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host, req.Host = t.target.Scheme, t.target.Host, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// apiServer serves the handler in place of the API and counts the requests it gets
+// The returned client has a user token, so it doesn't need the accounts service.
+func apiServer(t *testing.T, handler http.HandlerFunc) (*Client, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	c := NewWithToken("token")
+	c.HTTPClient = &http.Client{Transport: &toServer{target}}
+	return c, &requests
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	// entries are 10 bytes unless a size is given and the cache holds 30
+	tests := []struct {
+		name  string
+		steps []string
+		want  []string
+	}{
+		{"under the limit", []string{"set a", "set b", "set c"}, []string{"a", "b", "c"}},
+		{"least recently set goes first", []string{"set a", "set b", "set c", "set d"}, []string{"b", "c", "d"}},
+		{"get makes it recent", []string{"set a", "set b", "set c", "get a", "set d"}, []string{"a", "c", "d"}},
+		{"set again makes it recent", []string{"set a", "set b", "set c", "set a", "set d", "set e"}, []string{"a", "d", "e"}},
+		{"deleted frees its space", []string{"set a", "set b", "set c", "delete b", "set d"}, []string{"a", "c", "d"}},
+		{"too large isn't stored", []string{"set a", "set b 31"}, []string{"a"}},
+		{"too large removes the old entry", []string{"set a", "set b", "set a 31"}, []string{"b"}},
+		{"larger evicts more", []string{"set a", "set b", "set c", "set d 20"}, []string{"c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemoryCache(30)
+			keys := map[string]bool{}
+			for _, step := range tt.steps {
+				f := strings.Fields(step)
+				keys[f[1]] = true
+				switch f[0] {
+				case "set":
+					size := 10
+					if len(f) == 3 {
+						size, _ = strconv.Atoi(f[2])
+					}
+					m.Set(f[1], &CachedResponse{Body: make([]byte, size)})
+				case "get":
+					m.Get(f[1])
+				case "delete":
+					m.Delete(f[1])
+				}
+			}
+
+			var got []string
+			for k := range keys {
+				if m.entries[k] != nil {
+					got = append(got, k)
+				}
+			}
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v cached, want %v", got, tt.want)
+			}
+			var size int64
+			for _, k := range got {
+				size += int64(len(m.entries[k].Value.(*memoryEntry).resp.Body))
+			}
+			if m.Len() != len(tt.want) || m.size != size {
+				t.Errorf("got %d entries of %d bytes, want %d of %d", m.Len(), m.size, len(tt.want), size)
+			}
+		})
+	}
+}
+
+func TestCachedRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		etag         string
+		requests     int32
+		revalidated  int32
+	}{
+		{"fresh is served from the cache", "max-age=60", "", 1, 0},
+		{"stale with an etag is revalidated", "no-cache", `"v1"`, 3, 2},
+		{"expired max-age is revalidated", "max-age=1", `"v1"`, 2, 1},
+		{"stale without an etag isn't stored", "no-cache", "", 3, 0},
+		{"no-store isn't stored", "no-store, max-age=60", `"v1"`, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var revalidated, served int32
+			c, requests := apiServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", tt.cacheControl)
+				if tt.etag != "" {
+					w.Header().Set("ETag", tt.etag)
+				}
+
+				if inm := r.Header.Get("If-None-Match"); inm != "" {
+					if inm != tt.etag {
+						t.Errorf("revalidated with %s, want %s", inm, tt.etag)
+					}
+					atomic.AddInt32(&revalidated, 1)
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				// later responses differ, a 304 must serve the first one again
+				fmt.Fprintf(w, `{"id":"a","name":"response %d"}`, atomic.AddInt32(&served, 1))
+			})
+			c.Cache = NewMemoryCache(1 << 20)
+
+			for i := 0; i < 3; i++ {
+				if i == 2 && tt.cacheControl == "max-age=1" {
+					time.Sleep(1100 * time.Millisecond)
+				}
+
+				track, err := c.GetTrack("a", "")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want := "response 1"
+				if tt.revalidated == 0 && tt.requests > 1 {
+					want = fmt.Sprintf("response %d", i+1)
+				}
+				if track.Name != want {
+					t.Errorf("call %d got %q, want %q", i, track.Name, want)
+				}
+			}
+
+			if n := atomic.LoadInt32(requests); n != tt.requests || revalidated != tt.revalidated {
+				t.Errorf("got %d requests and %d revalidations, want %d and %d", n, revalidated, tt.requests, tt.revalidated)
+			}
+		})
+	}
+}
+
+func TestCacheIsPerToken(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		fmt.Fprintf(w, `{"id":"a","name":%q}`, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	}))
+	defer srv.Close()
+
+	target, _ := url.Parse(srv.URL)
+	cache := NewMemoryCache(1 << 20)
+	client := func(token string) *Client {
+		c := NewWithToken(token)
+		c.HTTPClient = &http.Client{Transport: &toServer{target}}
+		c.Cache = cache
+		return c
+	}
+
+	for _, call := range []struct {
+		token    string
+		requests int32
+	}{
+		{"alice", 1},
+		{"bob", 2},
+		{"alice", 2},
+		{"bob", 2},
+	} {
+		track, err := client(call.token).GetTrack("a", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if track.Name != call.token {
+			t.Errorf("%s got the response for %s", call.token, track.Name)
+		}
+		if n := atomic.LoadInt32(&requests); n != call.requests {
+			t.Errorf("%s: %d requests made, want %d", call.token, n, call.requests)
+		}
+	}
+}
+
+func TestDiskCachePersists(t *testing.T) {
+	c, requests := apiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"id":"a","name":"On Disk"}`)
+	})
+
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		// a new cache on the same directory, like the next run of a program
+		cache, err := NewDiskCache(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Cache = cache
+
+		track, err := c.GetTrack("a", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if track.Name != "On Disk" {
+			t.Errorf("got %q", track.Name)
+		}
+	}
+
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("%d requests made, want 1", n)
+	}
+
+	cache, _ := NewDiskCache(dir)
+	key := c.cacheKey(withValues(EndpointGetTrack("a"), marketValues("")))
+	r, ok := cache.Get(key)
+	if !ok || r.ETag != `"v1"` || !r.Fresh() {
+		t.Fatalf("got %+v, %v for %s", r, ok, key)
+	}
+
+	cache.Delete(key)
+	if _, ok = cache.Get(key); ok {
+		t.Error("deleted entry is still cached")
+	}
+}
+
+func TestListTTL(t *testing.T) {
+	c, requests := apiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"markets":["SE","US"]}`)
+	})
+	c.ListTTL = 50 * time.Millisecond
+
+	tests := []struct {
+		wait     time.Duration
+		requests int32
+	}{
+		{0, 1},
+		{0, 1},
+		{60 * time.Millisecond, 2},
+		{0, 2},
+	}
+
+	for i, tt := range tests {
+		time.Sleep(tt.wait)
+		markets, err := c.AvailableMarkets()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(markets, []string{"SE", "US"}) {
+			t.Errorf("call %d got %v", i, markets)
+		}
+		if n := atomic.LoadInt32(requests); n != tt.requests {
+			t.Errorf("call %d: %d requests made, want %d", i, n, tt.requests)
+		}
+
+		// the caller's copy doesn't change the cache
+		markets[0] = "XX"
+	}
+}
@@ -0,0 +1,152 @@
+package spotify
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryCache is an in-memory Cache that evicts the least recently used responses
+// when the size of the stored headers and bodies goes over its limit
+type MemoryCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key  string
+	resp *CachedResponse
+	size int64
+}
+
+// NewMemoryCache makes a MemoryCache holding up to maxBytes of responses
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{maxBytes: maxBytes, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (m *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	m.order.MoveToFront(el)
+	r := *el.Value.(*memoryEntry).resp
+	return &r, true
+}
+
+func (m *MemoryCache) Set(key string, r *CachedResponse) {
+	size := r.size()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(key)
+	if size > m.maxBytes {
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryEntry{key: key, resp: r, size: size})
+	m.size += size
+
+	for m.size > m.maxBytes {
+		m.remove(m.order.Back().Value.(*memoryEntry).key)
+	}
+}
+
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(key)
+}
+
+func (m *MemoryCache) remove(key string) {
+	el, ok := m.entries[key]
+	if !ok {
+		return
+	}
+
+	m.size -= el.Value.(*memoryEntry).size
+	m.order.Remove(el)
+	delete(m.entries, key)
+}
+
+// Len is the number of cached responses
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// DiskCache is a Cache keeping every response as a JSON file in a directory
+// It has no size limit, remove the directory to clear it.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache makes a DiskCache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (d *DiskCache) Get(key string) (*CachedResponse, bool) {
+	b, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	r := &CachedResponse{}
+	if err = json.Unmarshal(b, r); err != nil {
+		return nil, false
+	}
+	return r, true
+}
+
+// Set writes the response to a temporary file first so readers never see a partial entry
+func (d *DiskCache) Set(key string, r *CachedResponse) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+
+	f, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+
+	if err = os.Rename(f.Name(), d.path(key)); err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+func (d *DiskCache) Delete(key string) {
+	os.Remove(d.path(key))
+}
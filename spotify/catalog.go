@@ -0,0 +1,195 @@
+package spotify
+
+// The catalog lookups of the package level functions, made with the client's token
+
+// GetAlbum gets an album
+// With a BatchWindow concurrent calls are merged into GetAlbums
+func (c *Client) GetAlbum(id ID) (*FullAlbum, error) {
+	if c.batching(id) {
+		return c.batchedAlbum(id)
+	}
+
+	res, err := c.request("GET", EndpointGetAlbum(string(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	album := &FullAlbum{}
+	err = unmarshal(res, album)
+	if err != nil {
+		return nil, err
+	}
+	return album, nil
+}
+
+// GetAlbums gets several albums, at most 20
+// Albums that don't exist are nil
+func (c *Client) GetAlbums(ids []ID) ([]*FullAlbum, error) {
+	res, err := c.request("GET", EndpointGetAlbums(idStrings(ids)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Albums []*FullAlbum `json:"albums"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Albums, nil
+}
+
+// GetAlbumTracks gets a page of the tracks of an album
+// The items of the page are SimpleTracks
+func (c *Client) GetAlbumTracks(id ID, limit, offset int) (*Paging, error) {
+	vals := marketValues("")
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetAlbumTracks(string(id)), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetArtist gets an artist
+// With a BatchWindow concurrent calls are merged into GetArtists
+func (c *Client) GetArtist(id ID) (*FullArtist, error) {
+	if c.batching(id) {
+		return c.batchedArtist(id)
+	}
+
+	res, err := c.request("GET", EndpointGetArtist(string(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	artist := &FullArtist{}
+	err = unmarshal(res, artist)
+	if err != nil {
+		return nil, err
+	}
+	return artist, nil
+}
+
+// GetArtists gets several artists, at most 50
+// Artists that don't exist are nil
+func (c *Client) GetArtists(ids []ID) ([]*FullArtist, error) {
+	res, err := c.request("GET", EndpointGetArtists(idStrings(ids)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Artists []*FullArtist `json:"artists"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Artists, nil
+}
+
+// GetArtistAlbums gets a page of the albums of an artist
+// The items of the page are SimpleAlbums
+func (c *Client) GetArtistAlbums(id ID, limit, offset int) (*Paging, error) {
+	vals := marketValues("")
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetArtistAlbums(string(id)), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetArtistTopTracks gets the top tracks of an artist in a market, ISO 3166-1 alpha-2 country code
+func (c *Client) GetArtistTopTracks(id ID, market string) ([]*FullTrack, error) {
+	res, err := c.request("GET", withValues(EndpointGetArtistTopTracks(string(id)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Tracks []*FullTrack `json:"tracks"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Tracks, nil
+}
+
+// GetRelatedArtists gets the artists similar to an artist
+func (c *Client) GetRelatedArtists(id ID) ([]*FullArtist, error) {
+	res, err := c.request("GET", EndpointGetRelatedArtists(string(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Artists []*FullArtist `json:"artists"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Artists, nil
+}
+
+// GetTrack gets a track
+// With a BatchWindow concurrent calls for the same market are merged into GetTracks
+// Market: optional ISO 3166-1 alpha-2 country code, the track is relinked to a version playable in it
+func (c *Client) GetTrack(id ID, market string) (*FullTrack, error) {
+	if c.batching(id) {
+		return c.batchedTrack(id, market)
+	}
+
+	res, err := c.request("GET", withValues(EndpointGetTrack(string(id)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	track := &FullTrack{}
+	err = unmarshal(res, track)
+	if err != nil {
+		return nil, err
+	}
+	return track, nil
+}
+
+// GetTracks gets several tracks, at most 50
+// Tracks that don't exist are nil
+func (c *Client) GetTracks(ids []ID, market string) ([]*FullTrack, error) {
+	res, err := c.request("GET", withValues(EndpointGetTracks(idStrings(ids)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Tracks []*FullTrack `json:"tracks"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Tracks, nil
+}
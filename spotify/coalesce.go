@@ -0,0 +1,243 @@
+package spotify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultCoalesceTimeout limits a shared request when the client sets no CoalesceTimeout
+const defaultCoalesceTimeout = 30 * time.Second
+
+// flight is a GET request shared by every caller asking for the same URL while it runs
+type flight struct {
+	done   chan struct{}
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// flightGroup tracks the in-flight shared requests of a client
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// sharedRequest makes a GET request, or waits for the identical one already running and shares its response
+// The request runs detached from the context of the caller that started it, so one caller giving up
+// doesn't fail the others, but within CoalesceTimeout. Every caller still returns when its own context is done.
+func (c *Client) sharedRequest(ctx context.Context, url string) (*http.Response, error) {
+	g := c.flights
+	key := c.cacheKey(url)
+
+	g.mu.Lock()
+	f, ok := g.flights[key]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		g.flights[key] = f
+		timeout := c.CoalesceTimeout
+		if timeout <= 0 {
+			timeout = defaultCoalesceTimeout
+		}
+
+		go func() {
+			shared, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+			defer cancel()
+			f.status, f.header, f.body, f.err = c.fetchShared(shared, url)
+
+			g.mu.Lock()
+			delete(g.flights, key)
+			g.mu.Unlock()
+			close(f.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-f.done:
+	}
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(f.status),
+		StatusCode:    f.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(f.body)),
+		ContentLength: int64(len(f.body)),
+	}, nil
+}
+
+func (c *Client) fetchShared(ctx context.Context, url string) (int, http.Header, []byte, error) {
+	var res *http.Response
+	var err error
+	if c.Cache != nil {
+		res, err = c.cachedRequest(ctx, url)
+	} else {
+		var req *http.Request
+		req, err = http.NewRequest("GET", url, nil)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		res, err = c.do(req.WithContext(ctx))
+	}
+
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return res.StatusCode, res.Header, body, nil
+}
+
+// batchResult is what a single lookup waiting in a batch gets back
+type batchResult struct {
+	v   interface{}
+	err error
+}
+
+// batch collects the single ID lookups of one kind until the window passes or it is full
+type batch struct {
+	ids     []ID
+	waiting map[ID][]chan batchResult
+	timer   *time.Timer
+}
+
+// batchGroup merges single ID lookups into calls of the several IDs endpoints
+type batchGroup struct {
+	mu      sync.Mutex
+	batches map[string]*batch
+}
+
+// batching checks if the lookup of the ID goes through a batch
+// A malformed ID would make the API reject the whole batch, so it's looked up on its own.
+func (c *Client) batching(id ID) bool {
+	return c.BatchWindow > 0 && c.batches != nil && id.Valid()
+}
+
+// batched looks up one ID through the batch of the key
+// The batch is flushed with fetch when it has max IDs or BatchWindow after its first ID arrived,
+// fetch returns the results in the order of the IDs with nil for the ones that don't exist.
+func (c *Client) batched(key string, id ID, max int, fetch func([]ID) ([]interface{}, error)) (interface{}, error) {
+	g := c.batches
+	ch := make(chan batchResult, 1)
+
+	g.mu.Lock()
+	b, ok := g.batches[key]
+	if !ok {
+		b = &batch{waiting: make(map[ID][]chan batchResult)}
+		g.batches[key] = b
+		b.timer = time.AfterFunc(c.BatchWindow, func() {
+			g.mu.Lock()
+			if g.batches[key] == b {
+				delete(g.batches, key)
+			}
+			g.mu.Unlock()
+			c.flushBatch(b, fetch)
+		})
+	}
+
+	if _, ok = b.waiting[id]; !ok {
+		b.ids = append(b.ids, id)
+	}
+	b.waiting[id] = append(b.waiting[id], ch)
+
+	// a full batch is flushed right away, unless the timer beat us to it
+	flush := false
+	if len(b.ids) >= max {
+		delete(g.batches, key)
+		flush = b.timer.Stop()
+	}
+	g.mu.Unlock()
+
+	if flush {
+		go c.flushBatch(b, fetch)
+	}
+
+	r := <-ch
+	return r.v, r.err
+}
+
+func (c *Client) flushBatch(b *batch, fetch func([]ID) ([]interface{}, error)) {
+	results, err := fetch(b.ids)
+	for i, id := range b.ids {
+		r := batchResult{err: err}
+		if err == nil {
+			if i < len(results) && results[i] != nil {
+				r.v = results[i]
+			} else {
+				r.err = &SpotifyError{Status: http.StatusNotFound, Message: "Non existing id: '" + string(id) + "'"}
+			}
+		}
+
+		for _, ch := range b.waiting[id] {
+			ch <- r
+		}
+	}
+}
+
+func (c *Client) batchedAlbum(id ID) (*FullAlbum, error) {
+	v, err := c.batched("albums", id, 20, func(ids []ID) ([]interface{}, error) {
+		albums, err := c.GetAlbums(ids)
+		results := make([]interface{}, len(albums))
+		for i, a := range albums {
+			if a != nil {
+				results[i] = a
+			}
+		}
+		return results, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*FullAlbum), nil
+}
+
+func (c *Client) batchedArtist(id ID) (*FullArtist, error) {
+	v, err := c.batched("artists", id, 50, func(ids []ID) ([]interface{}, error) {
+		artists, err := c.GetArtists(ids)
+		results := make([]interface{}, len(artists))
+		for i, a := range artists {
+			if a != nil {
+				results[i] = a
+			}
+		}
+		return results, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*FullArtist), nil
+}
+
+func (c *Client) batchedTrack(id ID, market string) (*FullTrack, error) {
+	v, err := c.batched("tracks:"+market, id, 50, func(ids []ID) ([]interface{}, error) {
+		tracks, err := c.GetTracks(ids, market)
+		results := make([]interface{}, len(tracks))
+		for i, t := range tracks {
+			if t != nil {
+				results[i] = t
+			}
+		}
+		return results, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*FullTrack), nil
+}
@@ -0,0 +1,355 @@
+package spotify_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/spotifytest"
+)
+
+func TestBatchSkipsMalformedIDs(t *testing.T) {
+	d := spotifytest.Seed()
+	s := spotifytest.NewServer(d)
+	defer s.Close()
+
+	c := s.Client()
+	c.BatchWindow = 20 * time.Millisecond
+
+	ids := []spotify.ID{d.Tracks[0].ID, "not-an-id", d.Tracks[1].ID}
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id spotify.ID) {
+			defer wg.Done()
+			_, errs[i] = c.GetTrack(id, "")
+		}(i, id)
+	}
+	wg.Wait()
+
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("valid IDs failed: %v, %v", errs[0], errs[2])
+	}
+	if e, ok := errs[1].(*spotify.SpotifyError); !ok || e.Status != 400 {
+		t.Errorf("malformed ID error = %v, want a 400", errs[1])
+	}
+}
+
+// toServer sends the requests for the API to a test server
+type toServer struct {
+	target *url.URL
+}
+
+func (t *toServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host, req.Host = t.target.Scheme, t.target.Host, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// countingServer serves the handler in place of the API and keeps the paths and queries it's asked for
+func countingServer(t *testing.T, handler http.HandlerFunc) (*spotify.Client, func() []string) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.RequestURI())
+		mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	c := spotify.NewWithToken("token")
+	c.HTTPClient = &http.Client{Transport: &toServer{target}}
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requests...)
+	}
+}
+
+// concurrently runs fn n times at once and waits for them
+func concurrently(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// id is a valid ID ending in the number
+func id(n int) spotify.ID {
+	return spotify.ID(fmt.Sprintf("%022d", n))
+}
+
+func TestCoalesceIdenticalGets(t *testing.T) {
+	release := make(chan struct{})
+	c, requests := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprintf(w, `{"id":%q,"name":"So What"}`, strings.TrimPrefix(r.URL.Path, "/v1/tracks/"))
+	})
+	c.Coalesce = true
+
+	ids := []spotify.ID{id(1), id(1), id(1), id(2), id(1), id(2)}
+	tracks := make([]*spotify.FullTrack, len(ids))
+	errs := make([]error, len(ids))
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+	concurrently(len(ids), func(i int) {
+		tracks[i], errs[i] = c.GetTrack(ids[i], "")
+	})
+
+	for i := range ids {
+		if errs[i] != nil || tracks[i].ID != ids[i] || tracks[i].Name != "So What" {
+			t.Errorf("call %d got %+v, %v", i, tracks[i], errs[i])
+		}
+	}
+
+	// every caller gets a copy of its own
+	tracks[0].Name = "changed"
+	if tracks[1].Name != "So What" {
+		t.Error("the callers share the decoded track")
+	}
+
+	got := requests()
+	sort.Strings(got)
+	want := []string{"/v1/tracks/" + string(id(1)), "/v1/tracks/" + string(id(2))}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+
+	// once it's done the next call makes its own request
+	if _, err := c.GetTrack(id(1), ""); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(requests()); n != 3 {
+		t.Errorf("%d requests after the shared ones, want 3", n)
+	}
+}
+
+func TestCoalesceCallerCancel(t *testing.T) {
+	release := make(chan struct{})
+	c, requests := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprint(w, `{"tracks":{"items":[],"total":0}}`)
+	})
+	c.Coalesce = true
+
+	search := func(ctx context.Context) error {
+		_, err := c.Search(ctx, "so what", []spotify.SearchType{spotify.SearchTypeTrack}, nil)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make([]error, 2)
+	concurrently(2, func(i int) {
+		if i == 0 {
+			errs[0] = search(ctx)
+			return
+		}
+
+		// joins the flight the canceled caller started
+		time.Sleep(20 * time.Millisecond)
+		time.AfterFunc(20*time.Millisecond, cancel)
+		time.AfterFunc(40*time.Millisecond, func() { close(release) })
+		errs[1] = search(context.Background())
+	})
+
+	if errs[0] != context.Canceled || errs[1] != nil {
+		t.Errorf("got %v for the canceled caller and %v for the other, want only the first canceled", errs[0], errs[1])
+	}
+	if n := len(requests()); n != 1 {
+		t.Errorf("%d requests made, want 1", n)
+	}
+}
+
+func TestCoalesceTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	c, _ := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	c.Coalesce = true
+	c.CoalesceTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	errs := make([]error, 3)
+	concurrently(len(errs), func(i int) {
+		_, errs[i] = c.GetTrack(id(1), "")
+	})
+
+	for i, err := range errs {
+		if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+			t.Errorf("call %d got %v, want the deadline exceeded", i, err)
+		}
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("the shared request took %v", d)
+	}
+}
+
+func TestCoalesceErrorReachesEveryWaiter(t *testing.T) {
+	release := make(chan struct{})
+	c, requests := countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":{"status":429,"message":"API rate limit exceeded"}}`)
+	})
+	c.Coalesce = true
+
+	errs := make([]error, 5)
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+	concurrently(len(errs), func(i int) {
+		_, errs[i] = c.GetArtist(id(1))
+	})
+
+	for i, err := range errs {
+		if e, ok := err.(*spotify.SpotifyError); !ok || e.Status != http.StatusTooManyRequests || e.Message != "API rate limit exceeded" {
+			t.Errorf("call %d got %v, want the 429", i, err)
+		}
+	}
+	if n := len(requests()); n != 1 {
+		t.Errorf("%d requests made, want 1", n)
+	}
+}
+
+// idsServer answers the several IDs endpoints with an object for every ID that doesn't end in 9
+func idsServer(t *testing.T) (*spotify.Client, func() []string) {
+	return countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		kind := strings.TrimPrefix(r.URL.Path, "/v1/")
+		var objects []string
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			if strings.HasSuffix(id, "9") {
+				objects = append(objects, "null")
+			} else {
+				objects = append(objects, fmt.Sprintf(`{"id":%q}`, id))
+			}
+		}
+		fmt.Fprintf(w, `{%q:[%s]}`, kind, strings.Join(objects, ","))
+	})
+}
+
+func TestBatchMergesLookups(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []int
+		get      func(c *spotify.Client, id spotify.ID) (spotify.ID, error)
+		requests []string
+	}{
+		{"albums", []int{1, 2, 3, 2}, func(c *spotify.Client, id spotify.ID) (spotify.ID, error) {
+			a, err := c.GetAlbum(id)
+			if err != nil {
+				return "", err
+			}
+			return a.ID, nil
+		}, []string{"/v1/albums?ids=" + string(id(1)) + "," + string(id(2)) + "," + string(id(3))}},
+		{"tracks", []int{4, 5}, func(c *spotify.Client, id spotify.ID) (spotify.ID, error) {
+			t, err := c.GetTrack(id, "SE")
+			if err != nil {
+				return "", err
+			}
+			return t.ID, nil
+		}, []string{"/v1/tracks?ids=" + string(id(4)) + "," + string(id(5)) + "&market=SE"}},
+		{"full albums are split", []int{1, 2, 3, 4, 5, 6, 7, 8, 10, 11, 12, 13, 14, 15, 16, 17, 18, 20, 21, 22, 23}, func(c *spotify.Client, id spotify.ID) (spotify.ID, error) {
+			a, err := c.GetAlbum(id)
+			if err != nil {
+				return "", err
+			}
+			return a.ID, nil
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, requests := idsServer(t)
+			c.BatchWindow = 50 * time.Millisecond
+
+			got := make([]spotify.ID, len(tt.ids))
+			errs := make([]error, len(tt.ids))
+			concurrently(len(tt.ids), func(i int) {
+				got[i], errs[i] = tt.get(c, id(tt.ids[i]))
+			})
+
+			for i, n := range tt.ids {
+				if errs[i] != nil || got[i] != id(n) {
+					t.Errorf("lookup of %s got %s, %v", id(n), got[i], errs[i])
+				}
+			}
+
+			made := requests()
+			if tt.requests == nil {
+				// more than 20 albums take two requests, the first one as soon as it's full
+				if len(made) != 2 {
+					t.Errorf("requests = %v, want 2", made)
+				}
+				return
+			}
+
+			// the order of the IDs in the batch is the order the calls arrived in
+			for i, r := range made {
+				u, _ := url.Parse(r)
+				ids := strings.Split(u.Query().Get("ids"), ",")
+				sort.Strings(ids)
+				q := u.Query()
+				q.Set("ids", strings.Join(ids, ","))
+				made[i] = u.Path + "?" + strings.Replace(q.Encode(), "%2C", ",", -1)
+			}
+			if strings.Join(made, " ") != strings.Join(tt.requests, " ") {
+				t.Errorf("requests = %v, want %v", made, tt.requests)
+			}
+		})
+	}
+}
+
+func TestBatchMissingAndErrors(t *testing.T) {
+	c, requests := idsServer(t)
+	c.BatchWindow = 50 * time.Millisecond
+
+	ids := []spotify.ID{id(1), id(9), id(2)}
+	errs := make([]error, len(ids))
+	concurrently(len(ids), func(i int) {
+		_, errs[i] = c.GetArtist(ids[i])
+	})
+
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("existing artists failed: %v, %v", errs[0], errs[2])
+	}
+	if e, ok := errs[1].(*spotify.SpotifyError); !ok || e.Status != http.StatusNotFound {
+		t.Errorf("missing artist got %v, want a 404", errs[1])
+	}
+	if n := len(requests()); n != 1 {
+		t.Errorf("%d requests made, want 1", n)
+	}
+
+	// a failed batch fails every lookup in it
+	c, requests = countingServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	c.BatchWindow = 50 * time.Millisecond
+
+	concurrently(len(ids), func(i int) {
+		_, errs[i] = c.GetTrack(ids[i], "")
+	})
+	for i, err := range errs {
+		if e, ok := err.(*spotify.SpotifyError); !ok || e.Status != http.StatusBadGateway {
+			t.Errorf("lookup %d got %v, want the 502", i, err)
+		}
+	}
+	if n := len(requests()); n != 1 {
+		t.Errorf("%d requests made, want 1", n)
+	}
+}
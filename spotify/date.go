@@ -0,0 +1,247 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DatePrecision is how precise a release date is
+type DatePrecision string
+
+const (
+	PrecisionYear  DatePrecision = "year"
+	PrecisionMonth DatePrecision = "month"
+	PrecisionDay   DatePrecision = "day"
+)
+
+// layout returns the format the API uses for dates of the precision
+func (p DatePrecision) layout() string {
+	switch p {
+	case PrecisionYear:
+		return "2006"
+	case PrecisionMonth:
+		return "2006-01"
+	}
+	return "2006-01-02"
+}
+
+// rank orders the precisions from least to most precise
+func (p DatePrecision) rank() int {
+	switch p {
+	case PrecisionYear:
+		return 1
+	case PrecisionMonth:
+		return 2
+	case PrecisionDay:
+		return 3
+	}
+	return 0
+}
+
+// ReleaseDate is the release date of an album, episode or chapter
+// The API sends the date as "1981", "1981-12" or "1981-12-15" next to a release_date_precision,
+// the album, episode and chapter apply that precision and the format of the date is only a fallback.
+// A date the API sends that can't be parsed, like "0000" for an unknown one, decodes as a zero date
+// that keeps the string, see Raw.
+type ReleaseDate struct {
+	t         time.Time
+	precision DatePrecision
+
+	// raw is the release_date as it was sent when it couldn't be parsed
+	raw string
+}
+
+// ParseReleaseDate parses a release date with the given precision
+// If the precision is empty it's inferred from the format of the date,
+// a date more precise than the precision is truncated to it.
+func ParseReleaseDate(date string, precision DatePrecision) (ReleaseDate, error) {
+	if date == "" {
+		return ReleaseDate{}, nil
+	}
+
+	if precision != "" && precision.rank() == 0 {
+		return ReleaseDate{}, fmt.Errorf("Invalid release date precision: %s", precision)
+	}
+
+	format := PrecisionDay
+	switch len(date) {
+	case 4:
+		format = PrecisionYear
+	case 7:
+		format = PrecisionMonth
+	}
+
+	if precision == "" {
+		precision = format
+	}
+
+	t, err := time.Parse(format.layout(), date)
+	if err != nil || t.Year() == 0 || precision.rank() > format.rank() {
+		return ReleaseDate{}, fmt.Errorf("Invalid release date %q with precision %s", date, precision)
+	}
+
+	switch precision {
+	case PrecisionYear:
+		t = time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	case PrecisionMonth:
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return ReleaseDate{t: t, precision: precision}, nil
+}
+
+// Time returns the start of the period the date covers, e.g: January 1st for a year, in UTC
+func (d ReleaseDate) Time() time.Time { return d.t }
+
+func (d ReleaseDate) Year() int { return d.t.Year() }
+
+// Month returns the month, or 0 if the precision is a year
+func (d ReleaseDate) Month() time.Month {
+	if d.precision.rank() < PrecisionMonth.rank() {
+		return 0
+	}
+	return d.t.Month()
+}
+
+// Day returns the day of the month, or 0 if the precision is a year or month
+func (d ReleaseDate) Day() int {
+	if d.precision != PrecisionDay {
+		return 0
+	}
+	return d.t.Day()
+}
+
+func (d ReleaseDate) Precision() DatePrecision { return d.precision }
+
+func (d ReleaseDate) IsZero() bool { return d.precision == "" }
+
+// Compare returns -1, 0 or 1 if d is before, equal to or after o
+// Dates are ordered by the start of their period, a less precise date comes first when they start together,
+// so 1981 < 1981-01 < 1981-01-01 < 1981-01-02 < 1981-02.
+func (d ReleaseDate) Compare(o ReleaseDate) int {
+	switch {
+	case d.t.Before(o.t):
+		return -1
+	case d.t.After(o.t):
+		return 1
+	case d.precision.rank() < o.precision.rank():
+		return -1
+	case d.precision.rank() > o.precision.rank():
+		return 1
+	}
+	return 0
+}
+
+func (d ReleaseDate) Before(o ReleaseDate) bool { return d.Compare(o) < 0 }
+func (d ReleaseDate) After(o ReleaseDate) bool  { return d.Compare(o) > 0 }
+func (d ReleaseDate) Equal(o ReleaseDate) bool  { return d.Compare(o) == 0 }
+
+// String formats the date the way the API does for its precision
+func (d ReleaseDate) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.t.Format(d.precision.layout())
+}
+
+// Raw returns the date as the API sent it if it couldn't be parsed, otherwise it's the same as String
+func (d ReleaseDate) Raw() string {
+	if d.IsZero() {
+		return d.raw
+	}
+	return d.String()
+}
+
+// UnmarshalJSON never fails on the date itself, one that can't be parsed is kept as Raw
+func (d *ReleaseDate) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*d = releaseDate(s, "")
+	return nil
+}
+
+// MarshalJSON writes the date as it was sent if it couldn't be parsed, so it round trips
+func (d ReleaseDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Raw())
+}
+
+// releaseDate parses the raw release_date of a model with its release_date_precision
+// A date or precision that can't be parsed gives a zero date that keeps the raw string.
+func releaseDate(raw *string, precision DatePrecision) ReleaseDate {
+	if raw == nil {
+		return ReleaseDate{}
+	}
+
+	d, err := ParseReleaseDate(*raw, precision)
+	if err != nil {
+		return ReleaseDate{raw: *raw}
+	}
+	return d
+}
+
+// The models with a release date shadow release_date with the raw string,
+// so it's parsed once the precision next to it is known.
+
+func (a *SimpleAlbum) UnmarshalJSON(data []byte) error {
+	type simpleAlbum SimpleAlbum
+	aux := struct {
+		*simpleAlbum
+		ReleaseDate *string `json:"release_date"`
+	}{simpleAlbum: (*simpleAlbum)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.ReleaseDate = releaseDate(aux.ReleaseDate, a.ReleaseDatePrecision)
+	return nil
+}
+
+// UnmarshalJSON is needed even though FullAlbum embeds SimpleAlbum,
+// the promoted SimpleAlbum.UnmarshalJSON would leave the rest of the fields empty
+func (a *FullAlbum) UnmarshalJSON(data []byte) error {
+	type fullAlbum FullAlbum
+	aux := struct {
+		*fullAlbum
+		ReleaseDate *string `json:"release_date"`
+
+		// hides SimpleAlbum.UnmarshalJSON, which fullAlbum still promotes
+		UnmarshalJSON struct{} `json:"-"`
+	}{fullAlbum: (*fullAlbum)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.ReleaseDate = releaseDate(aux.ReleaseDate, a.ReleaseDatePrecision)
+	return nil
+}
+
+func (e *Episode) UnmarshalJSON(data []byte) error {
+	type episode Episode
+	aux := struct {
+		*episode
+		ReleaseDate *string `json:"release_date"`
+	}{episode: (*episode)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	e.ReleaseDate = releaseDate(aux.ReleaseDate, e.ReleaseDatePrecision)
+	return nil
+}
+
+func (c *Chapter) UnmarshalJSON(data []byte) error {
+	type chapter Chapter
+	aux := struct {
+		*chapter
+		ReleaseDate *string `json:"release_date"`
+	}{chapter: (*chapter)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.ReleaseDate = releaseDate(aux.ReleaseDate, c.ReleaseDatePrecision)
+	return nil
+}
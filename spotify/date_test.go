@@ -0,0 +1,151 @@
+package spotify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseReleaseDate(t *testing.T) {
+	tests := []struct {
+		date      string
+		precision DatePrecision
+		want      string
+		wantErr   bool
+	}{
+		{"1981", "", "1981", false},
+		{"1981-12", "", "1981-12", false},
+		{"1981-12-15", "", "1981-12-15", false},
+		{"1981-12-15", PrecisionYear, "1981", false},
+		{"1981-12-15", PrecisionMonth, "1981-12", false},
+		{"1981-01-01", PrecisionYear, "1981", false},
+		{"1981", PrecisionDay, "", true},
+		{"1981-12", "week", "", true},
+		{"81-12", "", "", true},
+		{"", PrecisionDay, "", false},
+		{"0000", "", "", true},
+		{"0000-00-00", "", "", true},
+	}
+
+	for _, tt := range tests {
+		d, err := ParseReleaseDate(tt.date, tt.precision)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseReleaseDate(%q, %q) error = %v", tt.date, tt.precision, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("ParseReleaseDate(%q, %q) = %q, want %q", tt.date, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseDatePrecisionField(t *testing.T) {
+	const data = `{"name": "x", "release_date": "1959-01-01", "release_date_precision": "year"}`
+
+	var album FullAlbum
+	if err := json.Unmarshal([]byte(data), &album); err != nil {
+		t.Fatal(err)
+	}
+	if album.Name != "x" || album.ReleaseDate.Precision() != PrecisionYear || album.ReleaseDate.Month() != 0 {
+		t.Errorf("album = %q %s", album.Name, album.ReleaseDate)
+	}
+
+	var episode Episode
+	if err := json.Unmarshal([]byte(data), &episode); err != nil {
+		t.Fatal(err)
+	}
+	if episode.ReleaseDate.String() != "1959" {
+		t.Errorf("episode release date = %s", episode.ReleaseDate)
+	}
+
+	var chapter Chapter
+	if err := json.Unmarshal([]byte(`{"release_date": "1959-08"}`), &chapter); err != nil {
+		t.Fatal(err)
+	}
+	if chapter.ReleaseDate.Precision() != PrecisionMonth {
+		t.Errorf("chapter falls back to the format, got %s", chapter.ReleaseDate.Precision())
+	}
+}
+
+func TestMalformedReleaseDate(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		raw  string
+	}{
+		{"unknown year", `{"release_date": "0000", "release_date_precision": "year"}`, "0000"},
+		{"unknown day", `{"release_date": "0000-00-00", "release_date_precision": "day"}`, "0000-00-00"},
+		{"empty", `{"release_date": "", "release_date_precision": "day"}`, ""},
+		{"null", `{"release_date": null}`, ""},
+		{"missing", `{}`, ""},
+		{"less precise than its precision", `{"release_date": "1959", "release_date_precision": "day"}`, "1959"},
+		{"unknown precision", `{"release_date": "1959-08-17", "release_date_precision": "week"}`, "1959-08-17"},
+		{"not a date", `{"release_date": "soon"}`, "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var simple SimpleAlbum
+			var full FullAlbum
+			var episode Episode
+			var chapter Chapter
+			for _, v := range []interface{}{&simple, &full, &episode, &chapter} {
+				if err := json.Unmarshal([]byte(tt.data), v); err != nil {
+					t.Fatalf("%T: %v", v, err)
+				}
+			}
+
+			for _, d := range []ReleaseDate{simple.ReleaseDate, full.ReleaseDate, episode.ReleaseDate, chapter.ReleaseDate} {
+				if !d.IsZero() || d.String() != "" || d.Raw() != tt.raw {
+					t.Errorf("got %q with raw %q, want a zero date with raw %q", d, d.Raw(), tt.raw)
+				}
+			}
+
+			// it's written back as it was sent
+			out, err := json.Marshal(simple.ReleaseDate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var back ReleaseDate
+			if err = json.Unmarshal(out, &back); err != nil || back.Raw() != tt.raw {
+				t.Errorf("round trip gave %s with raw %q, %v", out, back.Raw(), err)
+			}
+		})
+	}
+}
+
+func TestFullAlbumUnmarshal(t *testing.T) {
+	const data = `{
+		"id": "1weenld61qoidwYuZ1GESA",
+		"name": "Kind Of Blue",
+		"album_type": "album",
+		"release_date": "1959-08-17",
+		"release_date_precision": "day",
+		"copyrights": [{"text": "(P) 1959 Columbia Records", "type": "P"}],
+		"external_ids": {"upc": "886445195805"},
+		"genres": ["jazz"],
+		"label": "Columbia/Legacy",
+		"popularity": 74,
+		"tracks": {"href": "https://api.spotify.com/v1/albums/1weenld61qoidwYuZ1GESA/tracks", "items": [], "total": 6}
+	}`
+
+	var a FullAlbum
+	if err := json.Unmarshal([]byte(data), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.ID != "1weenld61qoidwYuZ1GESA" || a.Name != "Kind Of Blue" || a.AlbumType != "album" {
+		t.Errorf("album part = %q %q %q", a.ID, a.Name, a.AlbumType)
+	}
+	if a.ReleaseDate.String() != "1959-08-17" || a.ReleaseDate.Precision() != PrecisionDay {
+		t.Errorf("release date = %s with precision %s", a.ReleaseDate, a.ReleaseDate.Precision())
+	}
+	if len(a.Copyrights) != 1 || a.Copyrights[0].Type != "P" || a.ExternalIDs.UPC() != "886445195805" {
+		t.Errorf("copyrights = %v, external ids = %v", a.Copyrights, a.ExternalIDs)
+	}
+	if len(a.Genres) != 1 || a.Label != "Columbia/Legacy" || a.Popularity != 74 {
+		t.Errorf("genres = %v, label = %q, popularity = %d", a.Genres, a.Label, a.Popularity)
+	}
+	if a.Tracks == nil || a.Tracks.Total != 6 {
+		t.Errorf("tracks = %+v", a.Tracks)
+	}
+}
@@ -0,0 +1,152 @@
+package dedupe
+
+import (
+	"sort"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Options configure a scan
+type Options struct {
+	Strategies []Strategy
+	Keeper     Keeper
+
+	// Market the tracks are fetched for, defaults to "from_token" for the user's country
+	// Only tracks relinked for a market have LinkedFrom, without it Relinked never matches.
+	Market string
+}
+
+func (o *Options) orDefault() Options {
+	var d Options
+	if o != nil {
+		d = *o
+	}
+
+	if d.Market == "" {
+		d.Market = "from_token"
+	}
+	return d
+}
+
+// PlaylistResult are the duplicates of a playlist and the snapshot they were found in
+type PlaylistResult struct {
+	SnapshotID string
+	Groups     []Group
+}
+
+// ScanPlaylist finds the duplicates in a playlist
+func ScanPlaylist(c spotify.Playlists, uid, pid spotify.ID, opts *Options) (*PlaylistResult, error) {
+	o := opts.orDefault()
+
+	pl, err := c.GetPlaylist(uid, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := c.AllPlaylistTracksForMarket(uid, pid, o.Market)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, len(tracks))
+	for i, pt := range tracks {
+		items[i] = Item{Position: i, AddedAt: pt.AddedAt}
+		if pt.Track != nil {
+			items[i].Track = pt.Track.Track
+		}
+	}
+	return &PlaylistResult{SnapshotID: pl.SnapshotID, Groups: Find(items, o.Strategies, o.Keeper)}, nil
+}
+
+// CleanPlaylist removes the duplicates of a scan by their positions, so the kept copy of an exact repeat stays
+// The removal is made against the snapshot of the scan and fails if the playlist changed since.
+// The snapshot ID of the cleaned playlist is returned.
+func CleanPlaylist(c spotify.Playlists, uid, pid spotify.ID, res *PlaylistResult) (string, error) {
+	var remove []Item
+	for _, g := range res.Groups {
+		remove = append(remove, g.Remove...)
+	}
+
+	// from the end, so the positions of the next request are still those of the scan
+	sort.Slice(remove, func(i, j int) bool { return remove[i].Position > remove[j].Position })
+
+	snapshotID := res.SnapshotID
+	for len(remove) > 0 {
+		n := len(remove)
+		if n > spotify.MaxPlaylistItems {
+			n = spotify.MaxPlaylistItems
+		}
+
+		tracks := make([]spotify.TrackPositions, n)
+		for i, item := range remove[:n] {
+			uri, _ := stored(item.Track)
+			tracks[i] = spotify.TrackPositions{URI: uri, Positions: []int{item.Position}}
+		}
+
+		snap, err := c.RemovePlaylistTracks(uid, pid, tracks, snapshotID)
+		if err != nil {
+			return snapshotID, err
+		}
+		snapshotID = snap
+		remove = remove[n:]
+	}
+	return snapshotID, nil
+}
+
+// ScanLibrary finds the duplicates among the user's saved tracks
+// A track can only be saved once, so SameID never matches here.
+func ScanLibrary(c spotify.Library, opts *Options) ([]Group, error) {
+	o := opts.orDefault()
+
+	saved, err := c.AllSavedTracks(o.Market)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, len(saved))
+	for i, st := range saved {
+		items[i] = Item{Position: i, AddedAt: st.AddedAt, Track: st.Track}
+	}
+	return Find(items, o.Strategies, o.Keeper), nil
+}
+
+// CleanLibrary removes the duplicates of a library scan from the saved tracks
+func CleanLibrary(c spotify.Library, groups []Group) error {
+	keep := make(map[spotify.ID]bool)
+	for _, g := range groups {
+		_, id := stored(g.Keep.Track)
+		keep[id] = true
+	}
+
+	var ids []spotify.ID
+	for _, g := range groups {
+		for _, item := range g.Remove {
+			// a copy saved under the same ID as the kept one can't be removed without removing both
+			if _, id := stored(item.Track); !keep[id] {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > 50 {
+			n = 50
+		}
+
+		if err := c.RemoveSavedTracks(ids[:n]); err != nil {
+			return err
+		}
+		ids = ids[n:]
+	}
+	return nil
+}
+
+// stored returns the URI and ID a track is stored under in the playlist or library
+// A track relinked for the market is stored as the one it was linked from, the API doesn't know the relinked one there.
+func stored(t *spotify.FullTrack) (spotify.URI, spotify.ID) {
+	if t.LinkedFrom != nil && t.LinkedFrom.URI != "" {
+		return t.LinkedFrom.URI, t.LinkedFrom.ID
+	}
+	return t.URI, t.ID
+}
@@ -0,0 +1,132 @@
+package dedupe
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/mock"
+)
+
+func TestCleanPlaylist(t *testing.T) {
+	tests := []struct {
+		name   string
+		tracks []*spotify.FullTrack // nil is an unavailable item
+		want   [][]spotify.TrackPositions
+	}{
+		{"repeat", []*spotify.FullTrack{track("a", ""), track("b", ""), track("a", "")},
+			[][]spotify.TrackPositions{{{URI: "spotify:track:a", Positions: []int{2}}}}},
+		{"relinked repeat removed as it is in the playlist", []*spotify.FullTrack{relinked("x", "b"), track("a", ""), relinked("x", "b")},
+			[][]spotify.TrackPositions{{{URI: "spotify:track:b", Positions: []int{2}}}}},
+		{"relinked and the track it plays as", []*spotify.FullTrack{relinked("x", "b"), track("x", "")},
+			[][]spotify.TrackPositions{{{URI: "spotify:track:x", Positions: []int{1}}}}},
+		{"local files and unavailable items stay", []*spotify.FullTrack{local("song"), nil, track("a", ""), local("song"), nil, track("a", "")},
+			[][]spotify.TrackPositions{{{URI: "spotify:track:a", Positions: []int{5}}}}},
+		{"from the end", []*spotify.FullTrack{track("a", ""), track("b", ""), track("a", ""), track("b", "")},
+			[][]spotify.TrackPositions{{
+				{URI: "spotify:track:b", Positions: []int{3}},
+				{URI: "spotify:track:a", Positions: []int{2}},
+			}}},
+		{"no duplicates", []*spotify.FullTrack{track("a", ""), local("song")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]spotify.TrackPositions
+			c := &mock.Playlists{
+				GetPlaylistFunc: func(uid, pid spotify.ID) (*spotify.FullPlaylist, error) {
+					pl := &spotify.FullPlaylist{}
+					pl.SnapshotID = "scanned"
+					return pl, nil
+				},
+				AllPlaylistTracksForMarketFunc: func(uid, pid spotify.ID, market string) ([]*spotify.PlaylistTrack, error) {
+					if market != "from_token" {
+						t.Errorf("scanned for market %q, want from_token", market)
+					}
+
+					items := make([]*spotify.PlaylistTrack, len(tt.tracks))
+					for i, track := range tt.tracks {
+						items[i] = &spotify.PlaylistTrack{}
+						if track != nil {
+							items[i].IsLocal = track.IsLocal
+							items[i].Track = &spotify.PlaylistItem{Track: track}
+						}
+					}
+					return items, nil
+				},
+				RemovePlaylistTracksFunc: func(uid, pid spotify.ID, tracks []spotify.TrackPositions, snapshotID string) (string, error) {
+					if snapshotID != "scanned" {
+						t.Errorf("removed from snapshot %q, want the scanned one", snapshotID)
+					}
+					got = append(got, tracks)
+					return "cleaned", nil
+				},
+			}
+
+			res, err := ScanPlaylist(c, "user", "playlist", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshotID, err := CleanPlaylist(c, "user", "playlist", res)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removed %v, want %v", got, tt.want)
+			}
+			if tt.want != nil && snapshotID != "cleaned" {
+				t.Errorf("got snapshot %q, want the cleaned one", snapshotID)
+			}
+		})
+	}
+}
+
+func TestCleanLibrary(t *testing.T) {
+	tests := []struct {
+		name   string
+		tracks []*spotify.FullTrack
+		want   [][]spotify.ID
+	}{
+		{"isrc", []*spotify.FullTrack{track("a", "USABC1234567"), track("b", ""), track("c", "USABC1234567")},
+			[][]spotify.ID{{"c"}}},
+		{"relinked removed by its saved ID", []*spotify.FullTrack{track("x", ""), relinked("x", "b")},
+			[][]spotify.ID{{"b"}}},
+		{"relinked kept by its saved ID", []*spotify.FullTrack{relinked("x", "b"), track("x", "")},
+			[][]spotify.ID{{"x"}}},
+		{"local files stay", []*spotify.FullTrack{local("song"), local("song")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]spotify.ID
+			c := &mock.Library{
+				AllSavedTracksFunc: func(market string) ([]*spotify.SavedTrack, error) {
+					saved := make([]*spotify.SavedTrack, len(tt.tracks))
+					for i, track := range tt.tracks {
+						saved[i] = &spotify.SavedTrack{Track: track}
+					}
+					return saved, nil
+				},
+				RemoveSavedTracksFunc: func(ids []spotify.ID) error {
+					got = append(got, ids)
+					return nil
+				},
+			}
+
+			groups, err := ScanLibrary(c, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = CleanLibrary(c, groups); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package dedupe finds and removes duplicate tracks in playlists and the saved tracks library
+package dedupe
+
+import (
+	"sort"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Strategy is a way two tracks can be duplicates
+type Strategy string
+
+const (
+	// SameID are repeats of the exact same track
+	SameID Strategy = "id"
+
+	// Relinked are tracks that Spotify relinked to another version of the same track, see LinkedFrom
+	Relinked Strategy = "relinked"
+
+	// SameISRC are the same recording released on different albums
+	SameISRC Strategy = "isrc"
+)
+
+// DefaultStrategies are all the strategies
+var DefaultStrategies = []Strategy{SameID, Relinked, SameISRC}
+
+// Keeper chooses which of a group of duplicates to keep
+type Keeper string
+
+const (
+	// KeepOldest keeps the one that was added first
+	KeepOldest Keeper = "oldest"
+
+	// KeepPopular keeps the one with the highest popularity
+	KeepPopular Keeper = "popular"
+
+	// KeepOriginal keeps the one on an album over singles and compilations, the earliest released
+	KeepOriginal Keeper = "original"
+)
+
+// Item is a track at a position in a playlist or the library
+type Item struct {
+	Position int
+	AddedAt  string
+	Track    *spotify.FullTrack
+}
+
+// Group is a set of duplicates, Keep is the one that stays
+type Group struct {
+	Keep   Item
+	Remove []Item
+}
+
+// Find groups the duplicates among the items
+// Two items are in the same group if any of the strategies says they're duplicates, also through other items.
+// Items without a track, like episodes and unavailable tracks, are never duplicates. Groups are in the order of
+// their first item.
+func Find(items []Item, strategies []Strategy, keeper Keeper) []Group {
+	if len(strategies) == 0 {
+		strategies = DefaultStrategies
+	}
+
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	owner := make(map[string]int)
+	for i, item := range items {
+		for _, key := range keys(item.Track, strategies) {
+			if j, ok := owner[key]; ok {
+				parent[find(i)] = find(j)
+			} else {
+				owner[key] = i
+			}
+		}
+	}
+
+	members := make(map[int][]Item)
+	var roots []int
+	for i, item := range items {
+		if item.Track == nil {
+			continue
+		}
+
+		r := find(i)
+		if _, ok := members[r]; !ok {
+			roots = append(roots, r)
+		}
+		members[r] = append(members[r], item)
+	}
+
+	var groups []Group
+	for _, r := range roots {
+		m := members[r]
+		if len(m) < 2 {
+			continue
+		}
+
+		sort.SliceStable(m, func(i, j int) bool { return better(m[i], m[j], keeper) })
+		groups = append(groups, Group{Keep: m[0], Remove: m[1:]})
+	}
+	return groups
+}
+
+func keys(t *spotify.FullTrack, strategies []Strategy) []string {
+	if t == nil || t.IsLocal {
+		return nil
+	}
+
+	var keys []string
+	for _, s := range strategies {
+		switch s {
+		case SameID:
+			if t.ID != "" {
+				keys = append(keys, "id:"+string(t.ID))
+			}
+		case Relinked:
+			if t.LinkedFrom != nil && t.LinkedFrom.ID != "" {
+				keys = append(keys, "id:"+string(t.LinkedFrom.ID))
+				if t.ID != "" {
+					keys = append(keys, "id:"+string(t.ID))
+				}
+			}
+		case SameISRC:
+			if isrc := spotify.NormalizeISRC(t.ExternalIDs.ISRC()); isrc != "" {
+				keys = append(keys, "isrc:"+isrc)
+			}
+		}
+	}
+	return keys
+}
+
+// albumRank orders album types from most to least original
+var albumRank = map[string]int{"album": 0, "single": 1, "compilation": 2}
+
+// better checks if a should be kept over b, ties go to the earlier position
+func better(a, b Item, keeper Keeper) bool {
+	switch keeper {
+	case KeepPopular:
+		if a.Track.Popularity != b.Track.Popularity {
+			return a.Track.Popularity > b.Track.Popularity
+		}
+	case KeepOriginal:
+		ra, rb := rank(a), rank(b)
+		if ra != rb {
+			return ra < rb
+		}
+
+		if a.Track.Album != nil && b.Track.Album != nil {
+			if c := a.Track.Album.ReleaseDate.Compare(b.Track.Album.ReleaseDate); c != 0 {
+				return c < 0
+			}
+		}
+	default:
+		// the API uses ISO 8601 in UTC, so the strings sort chronologically
+		if a.AddedAt != b.AddedAt && a.AddedAt != "" && b.AddedAt != "" {
+			return a.AddedAt < b.AddedAt
+		}
+	}
+	return a.Position < b.Position
+}
+
+func rank(i Item) int {
+	if i.Track.Album == nil {
+		return len(albumRank)
+	}
+
+	if r, ok := albumRank[i.Track.Album.AlbumType]; ok {
+		return r
+	}
+	return len(albumRank)
+}
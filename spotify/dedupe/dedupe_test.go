@@ -0,0 +1,157 @@
+package dedupe
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// track is a catalog track, isrc may be empty
+func track(id, isrc string) *spotify.FullTrack {
+	t := &spotify.FullTrack{}
+	t.ID = spotify.ID(id)
+	t.URI = spotify.URI("spotify:track:" + id)
+	if isrc != "" {
+		t.ExternalIDs = &spotify.ExternalIDs{IDs: map[string]string{"isrc": isrc}}
+	}
+	return t
+}
+
+// relinked is track id, played in place of the track from in the market
+func relinked(id, from string) *spotify.FullTrack {
+	t := track(id, "")
+	t.LinkedFrom = &spotify.LinkedTrack{ID: spotify.ID(from), URI: spotify.URI("spotify:track:" + from)}
+	return t
+}
+
+// local is a local file, they have no ID
+func local(name string) *spotify.FullTrack {
+	t := &spotify.FullTrack{}
+	t.URI = spotify.URI("spotify:local:::" + name + ":180")
+	t.IsLocal = true
+	return t
+}
+
+// positions are the positions of the groups, the kept one first
+func positions(groups []Group) [][]int {
+	var got [][]int
+	for _, g := range groups {
+		p := []int{g.Keep.Position}
+		for _, item := range g.Remove {
+			p = append(p, item.Position)
+		}
+		got = append(got, p)
+	}
+	return got
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name       string
+		tracks     []*spotify.FullTrack
+		strategies []Strategy
+		want       [][]int
+	}{
+		{"repeat", []*spotify.FullTrack{track("a", ""), track("b", ""), track("a", "")}, nil, [][]int{{0, 2}}},
+		{"no duplicates", []*spotify.FullTrack{track("a", ""), track("b", "")}, nil, nil},
+		{"relinked", []*spotify.FullTrack{track("a", ""), relinked("x", "a")}, nil, [][]int{{0, 1}}},
+		{"relinked not a strategy", []*spotify.FullTrack{track("a", ""), relinked("x", "a")}, []Strategy{SameID, SameISRC}, nil},
+		{"relinked to the same track", []*spotify.FullTrack{relinked("x", "a"), relinked("x", "b")}, []Strategy{Relinked}, [][]int{{0, 1}}},
+		{"isrc", []*spotify.FullTrack{track("a", "USABC1234567"), track("b", "us-abc-12-34567")}, nil, [][]int{{0, 1}}},
+		{"isrc not a strategy", []*spotify.FullTrack{track("a", "USABC1234567"), track("b", "USABC1234567")}, []Strategy{SameID}, nil},
+		{"through another item", []*spotify.FullTrack{track("a", "USABC1234567"), track("b", ""), relinked("c", "b"), track("c", "USABC1234567")}, nil, [][]int{{0, 1, 2, 3}}},
+		{"local files", []*spotify.FullTrack{local("song"), local("song"), track("a", "")}, nil, nil},
+		{"unavailable", []*spotify.FullTrack{nil, track("a", ""), nil}, nil, nil},
+		{"groups in order", []*spotify.FullTrack{track("b", ""), track("a", ""), track("a", ""), track("b", "")}, nil, [][]int{{0, 3}, {1, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]Item, len(tt.tracks))
+			for i, track := range tt.tracks {
+				items[i] = Item{Position: i, Track: track}
+			}
+
+			if got := positions(Find(items, tt.strategies, KeepOldest)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got groups %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepers(t *testing.T) {
+	date := func(s string) spotify.ReleaseDate {
+		d, err := spotify.ParseReleaseDate(s, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	version := func(id, addedAt string, popularity int, albumType, released string) Item {
+		tr := track(id, "USABC1234567")
+		tr.Popularity = popularity
+		if albumType != "" {
+			tr.Album = &spotify.SimpleAlbum{AlbumType: albumType, ReleaseDate: date(released)}
+		}
+		return Item{AddedAt: addedAt, Track: tr}
+	}
+
+	tests := []struct {
+		name   string
+		items  []Item
+		keeper Keeper
+		want   []int
+	}{
+		{"oldest", []Item{
+			version("a", "2020-02-01T00:00:00Z", 10, "compilation", "2001"),
+			version("b", "2020-01-01T00:00:00Z", 50, "single", "1999"),
+			version("c", "2020-03-01T00:00:00Z", 30, "album", "2000"),
+		}, KeepOldest, []int{1, 0, 2}},
+		{"default is oldest", []Item{
+			version("a", "2020-02-01T00:00:00Z", 10, "", ""),
+			version("b", "2020-01-01T00:00:00Z", 50, "", ""),
+		}, "", []int{1, 0}},
+		{"oldest without dates", []Item{
+			version("a", "", 10, "", ""),
+			version("b", "2020-01-01T00:00:00Z", 50, "", ""),
+		}, KeepOldest, []int{0, 1}},
+		{"popular", []Item{
+			version("a", "2020-02-01T00:00:00Z", 10, "", ""),
+			version("b", "2020-01-01T00:00:00Z", 30, "", ""),
+			version("c", "2020-03-01T00:00:00Z", 50, "", ""),
+		}, KeepPopular, []int{2, 1, 0}},
+		{"popular tie", []Item{
+			version("a", "2020-02-01T00:00:00Z", 30, "", ""),
+			version("b", "2020-01-01T00:00:00Z", 30, "", ""),
+		}, KeepPopular, []int{0, 1}},
+		{"original album type", []Item{
+			version("a", "", 0, "compilation", "1990"),
+			version("b", "", 0, "single", "1995"),
+			version("c", "", 0, "album", "2000"),
+		}, KeepOriginal, []int{2, 1, 0}},
+		{"original release date", []Item{
+			version("a", "", 0, "album", "2000-05"),
+			version("b", "", 0, "album", "1998-05-01"),
+			version("c", "", 0, "album", "2000-01-20"),
+		}, KeepOriginal, []int{1, 2, 0}},
+		{"original without album", []Item{
+			version("a", "", 0, "", ""),
+			version("b", "", 0, "compilation", "2010"),
+		}, KeepOriginal, []int{1, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.items {
+				tt.items[i].Position = i
+			}
+
+			got := positions(Find(tt.items, []Strategy{SameISRC}, tt.keeper))
+			if len(got) != 1 || !reflect.DeepEqual(got[0], tt.want) {
+				t.Errorf("got groups %v, want [%v]", got, tt.want)
+			}
+		})
+	}
+}
@@ -30,7 +30,7 @@ func EndpointSearch(query, typ string) string { return base + "search?q=" + quer
 
 // ==================== ARTISTS ====================
 
-func EndpointGetArtist(id string) string            { return base + "artsts/" + id }
+func EndpointGetArtist(id string) string            { return base + "artists/" + id }
 func EndpointGetArtists(ids []string) string        { return base + "artists?ids=" + strings.Join(ids, ",") }
 func EndpointGetArtistAlbums(id string) string      { return EndpointGetArtist(id) + "/albums" }
 func EndpointGetArtistTopTracks(id string) string   { return EndpointGetArtist(id) + "/top-tracks" }
@@ -39,6 +39,26 @@ func EndpointGetTopArtistOrTrack(typ string) string { return EndpointMe() + "/to
 
 // ==================== END ARTISTS ====================
 
+// ==================== AUDIOBOOKS ====================
+
+func EndpointGetAudiobook(id string) string { return base + "audiobooks/" + id }
+func EndpointGetAudiobooks(ids []string) string {
+	return base + "audiobooks?ids=" + strings.Join(ids, ",")
+}
+func EndpointGetAudiobookChapters(id string) string { return EndpointGetAudiobook(id) + "/chapters" }
+func EndpointSaveAudiobooks(ids []string) string {
+	return EndpointMe() + "/audiobooks?ids=" + strings.Join(ids, ",")
+}
+func EndpointGetSavedAudiobooks() string                { return EndpointMe() + "/audiobooks" }
+func EndpointRemoveSavedAudiobooks(ids []string) string { return EndpointSaveAudiobooks(ids) }
+func EndpointHasAudiobooksSaved(ids []string) string {
+	return EndpointGetSavedAudiobooks() + "/contains?ids=" + strings.Join(ids, ",")
+}
+func EndpointGetChapter(id string) string     { return base + "chapters/" + id }
+func EndpointGetChapters(ids []string) string { return base + "chapters?ids=" + strings.Join(ids, ",") }
+
+// ==================== END AUDIOBOOKS ====================
+
 // ==================== BROWSE ====================
 
 func EndpointBrowseFeaturedPlaylists() string       { return base + "browse/featured-playlists" }
@@ -49,9 +69,16 @@ func EndpointGetCategoryPlaylists(id string) string { return EndpointGetCategory
 func EndpointGetRecommendations(args ...string) string {
 	return base + "recommendations?" + strings.Join(args, "&")
 }
+func EndpointGetAvailableGenreSeeds() string { return base + "recommendations/available-genre-seeds" }
 
 // ==================== END BROWSE ====================
 
+// ==================== MARKETS ====================
+
+func EndpointGetMarkets() string { return base + "markets" }
+
+// ==================== END MARKETS ====================
+
 // ==================== FOLLOW ====================
 
 func EndpointGetFollowedArtists() string { return EndpointMe() + "/following" }
@@ -95,6 +122,7 @@ func EndpointGetUserPlaylist(uid, pid string) string {
 func EndpointGetPlaylistTracks(uid, pid string) string {
 	return EndpointGetUserPlaylist(uid, pid) + "/tracks"
 }
+func EndpointGetMyPlaylists() string                       { return EndpointMe() + "/playlists" }
 func EndpointCreatePlaylist(uid string) string             { return EndpointGetUser(uid) + "/playlists" }
 func EndpointChangePlaylistDetails(uid, pid string) string { return EndpointGetUserPlaylist(uid, pid) }
 func EndpointAddTracksToPlaylist(uid, pid string) string   { return EndpointGetPlaylistTracks(uid, pid) }
@@ -110,6 +138,57 @@ func EndpointReplaceTracksInPlaylist(uid, pid string) string {
 
 // ==================== END PLAYLISTS ====================
 
+// ==================== PLAYER ====================
+
+func EndpointGetPlaybackState() string    { return EndpointMe() + "/player" }
+func EndpointTransferPlayback() string    { return EndpointGetPlaybackState() }
+func EndpointGetCurrentlyPlaying() string { return EndpointGetPlaybackState() + "/currently-playing" }
+func EndpointGetDevices() string          { return EndpointGetPlaybackState() + "/devices" }
+func EndpointPlay() string                { return EndpointGetPlaybackState() + "/play" }
+func EndpointPause() string               { return EndpointGetPlaybackState() + "/pause" }
+func EndpointNext() string                { return EndpointGetPlaybackState() + "/next" }
+func EndpointPrevious() string            { return EndpointGetPlaybackState() + "/previous" }
+func EndpointSeek() string                { return EndpointGetPlaybackState() + "/seek" }
+func EndpointSetRepeat() string           { return EndpointGetPlaybackState() + "/repeat" }
+func EndpointSetVolume() string           { return EndpointGetPlaybackState() + "/volume" }
+func EndpointSetShuffle() string          { return EndpointGetPlaybackState() + "/shuffle" }
+func EndpointGetQueue() string            { return EndpointGetPlaybackState() + "/queue" }
+func EndpointAddToQueue() string          { return EndpointGetQueue() }
+func EndpointGetRecentlyPlayed() string   { return EndpointGetPlaybackState() + "/recently-played" }
+
+// ==================== END PLAYER ====================
+
+// ==================== SHOWS ====================
+
+func EndpointGetShow(id string) string         { return base + "shows/" + id }
+func EndpointGetShows(ids []string) string     { return base + "shows?ids=" + strings.Join(ids, ",") }
+func EndpointGetShowEpisodes(id string) string { return EndpointGetShow(id) + "/episodes" }
+func EndpointSaveShows(ids []string) string {
+	return EndpointMe() + "/shows?ids=" + strings.Join(ids, ",")
+}
+func EndpointGetSavedShows() string                { return EndpointMe() + "/shows" }
+func EndpointRemoveSavedShows(ids []string) string { return EndpointSaveShows(ids) }
+func EndpointHasShowsSaved(ids []string) string {
+	return EndpointGetSavedShows() + "/contains?ids=" + strings.Join(ids, ",")
+}
+
+// ==================== END SHOWS ====================
+
+// ==================== EPISODES ====================
+
+func EndpointGetEpisode(id string) string     { return base + "episodes/" + id }
+func EndpointGetEpisodes(ids []string) string { return base + "episodes?ids=" + strings.Join(ids, ",") }
+func EndpointSaveEpisodes(ids []string) string {
+	return EndpointMe() + "/episodes?ids=" + strings.Join(ids, ",")
+}
+func EndpointGetSavedEpisodes() string                { return EndpointMe() + "/episodes" }
+func EndpointRemoveSavedEpisodes(ids []string) string { return EndpointSaveEpisodes(ids) }
+func EndpointHasEpisodesSaved(ids []string) string {
+	return EndpointGetSavedEpisodes() + "/contains?ids=" + strings.Join(ids, ",")
+}
+
+// ==================== END EPISODES ====================
+
 // ==================== TRACKS ====================
 
 func EndpointGetAudioAnalysis(sid string) string { return base + "audio-analysis/" + sid }
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Column is a CSV column, the value is used as its header
+type Column string
+
+const (
+	ColumnURI      Column = "uri"
+	ColumnTitle    Column = "title"
+	ColumnArtists  Column = "artists"
+	ColumnAlbum    Column = "album"
+	ColumnISRC     Column = "isrc"
+	ColumnDuration Column = "duration_ms"
+	ColumnAddedAt  Column = "added_at"
+	ColumnAddedBy  Column = "added_by"
+)
+
+// DefaultColumns are all the columns
+var DefaultColumns = []Column{ColumnURI, ColumnTitle, ColumnArtists, ColumnAlbum, ColumnISRC, ColumnDuration, ColumnAddedAt, ColumnAddedBy}
+
+func (c Column) value(e Entry) string {
+	switch c {
+	case ColumnURI:
+		return string(e.URI)
+	case ColumnTitle:
+		return e.Title
+	case ColumnArtists:
+		return joinArtists(e.Artists)
+	case ColumnAlbum:
+		return e.Album
+	case ColumnISRC:
+		return e.ISRC
+	case ColumnDuration:
+		if e.DurationMs == 0 {
+			return ""
+		}
+		return strconv.Itoa(e.DurationMs)
+	case ColumnAddedAt:
+		return e.AddedAt
+	case ColumnAddedBy:
+		return e.AddedBy
+	}
+	return ""
+}
+
+func (c Column) set(e *Entry, v string) error {
+	switch c {
+	case ColumnURI:
+		e.URI = spotify.URI(v)
+	case ColumnTitle:
+		e.Title = v
+	case ColumnArtists:
+		e.Artists = splitArtists(v)
+	case ColumnAlbum:
+		e.Album = v
+	case ColumnISRC:
+		e.ISRC = v
+	case ColumnDuration:
+		if v == "" {
+			return nil
+		}
+
+		ms, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("Invalid %s: %q", c, v)
+		}
+		e.DurationMs = ms
+	case ColumnAddedAt:
+		e.AddedAt = v
+	case ColumnAddedBy:
+		e.AddedBy = v
+	}
+	return nil
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []Column
+}
+
+func newCSVWriter(w io.Writer, opts *Options) (Writer, error) {
+	c := &csvWriter{w: csv.NewWriter(w), columns: opts.Columns}
+	if len(c.columns) == 0 {
+		c.columns = DefaultColumns
+	}
+
+	header := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		header[i] = string(col)
+	}
+	return c, c.w.Write(header)
+}
+
+func (c *csvWriter) Write(e Entry) error {
+	row := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		row[i] = col.value(e)
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// readCSV reads a CSV with a header row, unknown columns are ignored
+// Headers match the columns in any case and with surrounding spaces, a header without any known column
+// is an error since it's likely not a playlist.
+func readCSV(r io.Reader) (*Document, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return &Document{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, len(header))
+	known := false
+	for i, h := range header {
+		if i == 0 {
+			// spreadsheet programs start UTF-8 files with a byte order mark
+			h = strings.TrimPrefix(h, "\ufeff")
+		}
+
+		col := Column(strings.ToLower(strings.TrimSpace(h)))
+		for _, c := range DefaultColumns {
+			if col == c {
+				columns[i], known = c, true
+			}
+		}
+	}
+
+	if !known {
+		return nil, fmt.Errorf("No known columns in the CSV header: %s", strings.Join(header, ", "))
+	}
+
+	doc := &Document{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return doc, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		var e Entry
+		for i, v := range row {
+			if i >= len(columns) {
+				break
+			}
+
+			if err = columns[i].set(&e, v); err != nil {
+				return nil, err
+			}
+		}
+		doc.Entries = append(doc.Entries, e)
+	}
+}
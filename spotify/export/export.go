@@ -0,0 +1,187 @@
+// Package export writes playlists to M3U8, XSPF, JSPF and CSV files and reads them back
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+type Format string
+
+const (
+	M3U8 Format = "m3u8"
+	XSPF Format = "xspf"
+	JSPF Format = "jspf"
+	CSV  Format = "csv"
+)
+
+// artistSeparator joins the artists of an entry, commas are too common in artist names
+const artistSeparator = "; "
+
+// Entry is a playlist item in a format neutral shape
+type Entry struct {
+	URI        spotify.URI
+	Title      string
+	Artists    []string
+	Album      string
+	ISRC       string
+	DurationMs int
+	AddedAt    string
+	AddedBy    string
+}
+
+// Document is a whole exported playlist
+type Document struct {
+	Title   string
+	Creator string
+	Entries []Entry
+}
+
+// NewEntry converts a playlist item, local files and episodes are kept with what they have
+func NewEntry(pt *spotify.PlaylistTrack) Entry {
+	e := Entry{AddedAt: pt.AddedAt}
+	if pt.AddedBy != nil {
+		e.AddedBy = string(pt.AddedBy.ID)
+	}
+
+	if pt.Track == nil {
+		return e
+	}
+
+	if t := pt.Track.Track; t != nil {
+		e.URI, e.Title, e.DurationMs = t.URI, t.Name, t.DurationMs
+		e.ISRC = t.ExternalIDs.ISRC()
+		for _, a := range t.Artists {
+			e.Artists = append(e.Artists, a.Name)
+		}
+
+		if t.Album != nil {
+			e.Album = t.Album.Name
+		}
+	} else if ep := pt.Track.Episode; ep != nil {
+		e.URI, e.Title, e.DurationMs = ep.URI, ep.Name, ep.DurationMs
+		if ep.Show != nil {
+			e.Artists = []string{ep.Show.Publisher}
+			e.Album = ep.Show.Name
+		}
+	}
+	return e
+}
+
+func joinArtists(artists []string) string { return strings.Join(artists, artistSeparator) }
+
+func splitArtists(s string) []string {
+	var artists []string
+	for _, a := range strings.Split(s, strings.TrimSpace(artistSeparator)) {
+		if a = strings.TrimSpace(a); a != "" {
+			artists = append(artists, a)
+		}
+	}
+	return artists
+}
+
+// Writer writes the entries of a playlist as they come in
+// Close writes what the format needs after the last entry, it doesn't close the underlying writer.
+type Writer interface {
+	Write(e Entry) error
+	Close() error
+}
+
+// Options configure the output
+// Columns are the CSV columns, DefaultColumns if empty. The other formats always write every field they can hold.
+type Options struct {
+	Title   string
+	Creator string
+	Columns []Column
+}
+
+// NewWriter returns a Writer for the format, the header is written right away
+func NewWriter(w io.Writer, format Format, opts *Options) (Writer, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	switch format {
+	case M3U8:
+		return newM3UWriter(w, opts)
+	case XSPF:
+		return newXSPFWriter(w, opts)
+	case JSPF:
+		return newJSPFWriter(w, opts)
+	case CSV:
+		return newCSVWriter(w, opts)
+	}
+	return nil, fmt.Errorf("Unknown export format: %s", format)
+}
+
+// Playlist streams a whole playlist into the format, one page at a time
+// The title and creator default to the playlist's name and owner.
+func Playlist(c spotify.Playlists, uid, pid spotify.ID, w io.Writer, format Format, opts *Options) error {
+	o := Options{}
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Title == "" || o.Creator == "" {
+		pl, err := c.GetPlaylist(uid, pid)
+		if err != nil {
+			return err
+		}
+
+		if o.Title == "" {
+			o.Title = pl.Name
+		}
+
+		if o.Creator == "" && pl.Owner != nil {
+			o.Creator = pl.Owner.DisplayName
+		}
+	}
+
+	ew, err := NewWriter(w, format, &o)
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; ; {
+		page, err := c.GetPlaylistTracks(uid, pid, spotify.MaxPlaylistItems, offset)
+		if err != nil {
+			return err
+		}
+
+		var items []*spotify.PlaylistTrack
+		if err = json.Unmarshal(page.Items, &items); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err = ew.Write(NewEntry(item)); err != nil {
+				return err
+			}
+		}
+
+		offset += len(items)
+		if page.Next == "" || len(items) == 0 {
+			break
+		}
+	}
+	return ew.Close()
+}
+
+// Read reads a playlist written in the format back
+func Read(r io.Reader, format Format) (*Document, error) {
+	switch format {
+	case M3U8:
+		return readM3U(r)
+	case XSPF:
+		return readXSPF(r)
+	case JSPF:
+		return readJSPF(r)
+	case CSV:
+		return readCSV(r)
+	}
+	return nil, fmt.Errorf("Unknown export format: %s", format)
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var entries = []Entry{
+	{
+		URI:        "spotify:track:4uLU6hMCjMI75M1A2tKUQC",
+		Title:      "Never Gonna Give You Up",
+		Artists:    []string{"Rick Astley"},
+		Album:      "Whenever You Need Somebody",
+		ISRC:       "GBARL9300135",
+		DurationMs: 213000,
+		AddedAt:    "2020-01-02T03:04:05Z",
+		AddedBy:    "someone",
+	},
+	{
+		URI:        "spotify:track:0eGsygTp906u18L0Oimnem",
+		Title:      `Title with "quotes", commas & <markup>`,
+		Artists:    []string{"First, Artist", "Second Artist"},
+		Album:      "Album",
+		DurationMs: 61000,
+	},
+	{
+		URI:        "spotify:episode:512ojhOuo1ktJprKbVcKyQ",
+		Title:      "An Episode",
+		Artists:    []string{"Publisher"},
+		Album:      "The Show",
+		DurationMs: 3600000,
+	},
+	{
+		URI:        "spotify:local:Artist:Album:Local+Song:180",
+		Title:      "Local Song",
+		Artists:    []string{"Artist"},
+		DurationMs: 180000,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		format Format
+		// kept is what of the entry the format holds
+		kept func(e Entry) Entry
+		// document is if the title and creator are kept
+		title, creator bool
+	}{
+		{M3U8, func(e Entry) Entry {
+			e.AddedAt, e.AddedBy = "", ""
+			return e
+		}, true, false},
+		{XSPF, func(e Entry) Entry { return e }, true, true},
+		{JSPF, func(e Entry) Entry { return e }, true, true},
+		{CSV, func(e Entry) Entry { return e }, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, tt.format, &Options{Title: "Mix & Match", Creator: "Some User"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, e := range entries {
+				if err = w.Write(e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err := Read(&buf, tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.title && doc.Title != "Mix & Match" {
+				t.Errorf("got title %q", doc.Title)
+			}
+			if tt.creator && doc.Creator != "Some User" {
+				t.Errorf("got creator %q", doc.Creator)
+			}
+
+			if len(doc.Entries) != len(entries) {
+				t.Fatalf("got %d entries, want %d", len(doc.Entries), len(entries))
+			}
+			for i, e := range entries {
+				if want := tt.kept(e); !reflect.DeepEqual(doc.Entries[i], want) {
+					t.Errorf("entry %d is\n%+v, want\n%+v", i, doc.Entries[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadCSVHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		want []Entry
+		err  string
+	}{
+		{"case and spaces", "\ufeff URI ,Title,  ISRC\nspotify:track:a,A,USABC1234567\n",
+			[]Entry{{URI: "spotify:track:a", Title: "A", ISRC: "USABC1234567"}}, ""},
+		{"unknown columns ignored", "uri,rating\nspotify:track:a,5\n", []Entry{{URI: "spotify:track:a"}}, ""},
+		{"subset of the columns", "title,artists\nA,X; Y\n", []Entry{{Title: "A", Artists: []string{"X", "Y"}}}, ""},
+		{"no known columns", "track,artist\nA,X\n", nil, "No known columns"},
+		{"bad duration", "uri,duration_ms\nspotify:track:a,long\n", nil, "Invalid duration_ms"},
+		{"empty", "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Read(strings.NewReader(tt.csv), CSV)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(doc.Entries, tt.want) {
+				t.Errorf("got %+v, want %+v", doc.Entries, tt.want)
+			}
+		})
+	}
+}
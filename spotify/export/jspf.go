@@ -0,0 +1,93 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSPF is XSPF in JSON, meta elements are objects with the rel as the only key
+
+type jspfTrack struct {
+	Location   []string            `json:"location,omitempty"`
+	Identifier []string            `json:"identifier,omitempty"`
+	Title      string              `json:"title,omitempty"`
+	Creator    string              `json:"creator,omitempty"`
+	Album      string              `json:"album,omitempty"`
+	Duration   int                 `json:"duration,omitempty"`
+	Meta       []map[string]string `json:"meta,omitempty"`
+}
+
+type jspfDocument struct {
+	Playlist struct {
+		Title   string      `json:"title"`
+		Creator string      `json:"creator"`
+		Track   []jspfTrack `json:"track"`
+	} `json:"playlist"`
+}
+
+type jspfWriter struct {
+	w     io.Writer
+	first bool
+}
+
+func newJSPFWriter(w io.Writer, opts *Options) (Writer, error) {
+	title, err := json.Marshal(opts.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	creator, err := json.Marshal(opts.Creator)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = io.WriteString(w, `{"playlist":{"title":`+string(title)+`,"creator":`+string(creator)+`,"track":[`)
+	return &jspfWriter{w: w, first: true}, err
+}
+
+func (j *jspfWriter) Write(e Entry) error {
+	x := toXSPF(e)
+	t := jspfTrack{Location: x.Location, Identifier: x.Identifier, Title: x.Title, Creator: x.Creator, Album: x.Album, Duration: x.Duration}
+	for _, m := range x.Meta {
+		t.Meta = append(t.Meta, map[string]string{m.Rel: m.Value})
+	}
+
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	if !j.first {
+		b = append([]byte(",\n"), b...)
+	} else {
+		b = append([]byte("\n"), b...)
+	}
+	j.first = false
+
+	_, err = j.w.Write(b)
+	return err
+}
+
+func (j *jspfWriter) Close() error {
+	_, err := io.WriteString(j.w, "\n]}}\n")
+	return err
+}
+
+func readJSPF(r io.Reader) (*Document, error) {
+	var d jspfDocument
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Title: d.Playlist.Title, Creator: d.Playlist.Creator}
+	for _, t := range d.Playlist.Track {
+		x := xspfTrack{Location: t.Location, Identifier: t.Identifier, Title: t.Title, Creator: t.Creator, Album: t.Album, Duration: t.Duration}
+		for _, m := range t.Meta {
+			for rel, v := range m {
+				x.Meta = append(x.Meta, xspfMeta{rel, v})
+			}
+		}
+		doc.Entries = append(doc.Entries, fromXSPF(x))
+	}
+	return doc, nil
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// M3U8 entries are written as
+//   #EXTINF:<seconds>,<artists> - <title>
+//   #EXTALB:<album>
+//   #EXTISRC:<isrc>
+//   spotify:track:<id>
+// EXTISRC isn't a standard directive, players skip it like any other comment.
+// The added fields don't fit the format and are left out.
+
+type m3uWriter struct {
+	w io.Writer
+}
+
+func newM3UWriter(w io.Writer, opts *Options) (Writer, error) {
+	header := "#EXTM3U\n"
+	if opts.Title != "" {
+		header += "#PLAYLIST:" + oneLine(opts.Title) + "\n"
+	}
+
+	_, err := io.WriteString(w, header)
+	return &m3uWriter{w: w}, err
+}
+
+func (m *m3uWriter) Write(e Entry) error {
+	secs := -1
+	if e.DurationMs > 0 {
+		secs = (e.DurationMs + 500) / 1000
+	}
+
+	info := oneLine(e.Title)
+	if len(e.Artists) > 0 {
+		info = oneLine(joinArtists(e.Artists)) + " - " + info
+	}
+
+	s := fmt.Sprintf("#EXTINF:%d,%s\n", secs, info)
+	if e.Album != "" {
+		s += "#EXTALB:" + oneLine(e.Album) + "\n"
+	}
+
+	if e.ISRC != "" {
+		s += "#EXTISRC:" + oneLine(e.ISRC) + "\n"
+	}
+	s += string(e.URI) + "\n"
+
+	_, err := io.WriteString(m.w, s)
+	return err
+}
+
+func (m *m3uWriter) Close() error { return nil }
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func readM3U(r io.Reader) (*Document, error) {
+	doc := &Document{}
+	var cur Entry
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || line == "#EXTM3U":
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			doc.Title = strings.TrimPrefix(line, "#PLAYLIST:")
+		case strings.HasPrefix(line, "#EXTINF:"):
+			if cur.Title != "" {
+				// the previous entry had no location
+				doc.Entries = append(doc.Entries, cur)
+				cur = Entry{}
+			}
+
+			info := strings.TrimPrefix(line, "#EXTINF:")
+			parts := strings.SplitN(info, ",", 2)
+			if secs, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil && secs > 0 {
+				cur.DurationMs = secs * 1000
+			}
+
+			if len(parts) == 2 {
+				if i := strings.Index(parts[1], " - "); i >= 0 {
+					cur.Artists = splitArtists(parts[1][:i])
+					cur.Title = parts[1][i+3:]
+				} else {
+					cur.Title = parts[1]
+				}
+			}
+		case strings.HasPrefix(line, "#EXTALB:"):
+			cur.Album = strings.TrimPrefix(line, "#EXTALB:")
+		case strings.HasPrefix(line, "#EXTISRC:"):
+			cur.ISRC = strings.TrimPrefix(line, "#EXTISRC:")
+		case strings.HasPrefix(line, "#"):
+		default:
+			cur.URI = spotify.URI(line)
+			if u, err := spotify.ParseURI(line); err == nil {
+				cur.URI = u
+			}
+			doc.Entries = append(doc.Entries, cur)
+			cur = Entry{}
+		}
+	}
+	if cur.Title != "" {
+		doc.Entries = append(doc.Entries, cur)
+	}
+	return doc, sc.Err()
+}
@@ -0,0 +1,136 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// The fields XSPF has no element for are kept in meta elements with these rels
+const (
+	relAddedAt = "https://open.spotify.com/xspf/added_at"
+	relAddedBy = "https://open.spotify.com/xspf/added_by"
+	isrcPrefix = "urn:isrc:"
+)
+
+type xspfMeta struct {
+	Rel   string `xml:"rel,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xspfTrack struct {
+	XMLName    xml.Name   `xml:"track"`
+	Location   []string   `xml:"location,omitempty"`
+	Identifier []string   `xml:"identifier,omitempty"`
+	Title      string     `xml:"title,omitempty"`
+	Creator    string     `xml:"creator,omitempty"`
+	Album      string     `xml:"album,omitempty"`
+	Duration   int        `xml:"duration,omitempty"`
+	Meta       []xspfMeta `xml:"meta,omitempty"`
+}
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Title   string      `xml:"title"`
+	Creator string      `xml:"creator"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+func toXSPF(e Entry) xspfTrack {
+	t := xspfTrack{Title: e.Title, Creator: joinArtists(e.Artists), Album: e.Album, Duration: e.DurationMs}
+	if e.URI != "" {
+		t.Location = []string{string(e.URI)}
+		t.Identifier = []string{string(e.URI)}
+	}
+
+	if e.ISRC != "" {
+		t.Identifier = append(t.Identifier, isrcPrefix+e.ISRC)
+	}
+
+	if e.AddedAt != "" {
+		t.Meta = append(t.Meta, xspfMeta{relAddedAt, e.AddedAt})
+	}
+
+	if e.AddedBy != "" {
+		t.Meta = append(t.Meta, xspfMeta{relAddedBy, e.AddedBy})
+	}
+	return t
+}
+
+func fromXSPF(t xspfTrack) Entry {
+	e := Entry{Title: t.Title, Artists: splitArtists(t.Creator), Album: t.Album, DurationMs: t.Duration}
+	var other string
+	for _, id := range append(t.Identifier, t.Location...) {
+		if strings.HasPrefix(id, isrcPrefix) {
+			e.ISRC = strings.TrimPrefix(id, isrcPrefix)
+		} else if u, err := spotify.ParseURI(id); err == nil {
+			if e.URI == "" {
+				e.URI = u
+			}
+		} else if other == "" {
+			other = id
+		}
+	}
+
+	// local files and locations from other players are kept as they are, like M3U8 does
+	if e.URI == "" {
+		e.URI = spotify.URI(other)
+	}
+
+	for _, m := range t.Meta {
+		switch m.Rel {
+		case relAddedAt:
+			e.AddedAt = m.Value
+		case relAddedBy:
+			e.AddedBy = m.Value
+		}
+	}
+	return e
+}
+
+type xspfWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newXSPFWriter(w io.Writer, opts *Options) (Writer, error) {
+	var header bytes.Buffer
+	header.WriteString(xml.Header + `<playlist version="1" xmlns="http://xspf.org/ns/0/">` + "\n  <title>")
+	xml.EscapeText(&header, []byte(opts.Title))
+	header.WriteString("</title>\n  <creator>")
+	xml.EscapeText(&header, []byte(opts.Creator))
+	header.WriteString("</creator>\n  <trackList>\n")
+
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return nil, err
+	}
+
+	x := &xspfWriter{w: w, enc: xml.NewEncoder(w)}
+	x.enc.Indent("    ", "  ")
+	return x, nil
+}
+
+func (x *xspfWriter) Write(e Entry) error {
+	return x.enc.Encode(toXSPF(e))
+}
+
+func (x *xspfWriter) Close() error {
+	_, err := io.WriteString(x.w, "\n  </trackList>\n</playlist>\n")
+	return err
+}
+
+func readXSPF(r io.Reader) (*Document, error) {
+	var pl xspfPlaylist
+	if err := xml.NewDecoder(r).Decode(&pl); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Title: pl.Title, Creator: pl.Creator}
+	for _, t := range pl.Tracks {
+		doc.Entries = append(doc.Entries, fromXSPF(t))
+	}
+	return doc, nil
+}
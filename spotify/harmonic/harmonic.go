@@ -0,0 +1,281 @@
+// Package harmonic converts the keys of spotify.AudioFeatures into DJ notations
+// and ranks tracks by how well they mix with a seed track
+package harmonic
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+const (
+	Minor = 0
+	Major = 1
+)
+
+var pitchNames = [12]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// Key is a musical key as it's encoded in AudioFeatures
+// Pitch is the pitch class of the tonic, 0 = C, 1 = C#, ..., 11 = B. Mode is Minor or Major.
+type Key struct {
+	Pitch int
+	Mode  int
+}
+
+// FromFeatures returns the key of the features, ok is false if no key was detected
+func FromFeatures(f *spotify.AudioFeatures) (k Key, ok bool) {
+	if f == nil || f.Key < 0 || f.Key > 11 {
+		return Key{}, false
+	}
+	return Key{Pitch: f.Key, Mode: f.Mode}, true
+}
+
+// PitchName returns the name of the pitch class, e.g: "C#", or "" if it's out of range
+func PitchName(pitch int) string {
+	if pitch < 0 || pitch > 11 {
+		return ""
+	}
+	return pitchNames[pitch]
+}
+
+// String returns the key in standard notation, e.g: "A" for A major, "F#m" for F# minor
+func (k Key) String() string {
+	if k.Mode == Minor {
+		return PitchName(k.Pitch) + "m"
+	}
+	return PitchName(k.Pitch)
+}
+
+// wheel returns the position on the Camelot wheel, 1-12
+func (k Key) wheel() int {
+	p := k.Pitch
+	if k.Mode == Minor {
+		// a minor key shares its number with its relative major, three semitones up
+		p = (p + 3) % 12
+	}
+	return (7*p+7)%12 + 1
+}
+
+// Camelot returns the key in Camelot notation, e.g: "8B" for C major, "8A" for A minor
+func (k Key) Camelot() string {
+	if k.Mode == Minor {
+		return strconv.Itoa(k.wheel()) + "A"
+	}
+	return strconv.Itoa(k.wheel()) + "B"
+}
+
+// OpenKey returns the key in Open Key notation, e.g: "1d" for C major, "1m" for A minor
+func (k Key) OpenKey() string {
+	n := (k.wheel()+4)%12 + 1
+	if k.Mode == Minor {
+		return strconv.Itoa(n) + "m"
+	}
+	return strconv.Itoa(n) + "d"
+}
+
+// fromWheel returns the key at the position on the Camelot wheel
+func fromWheel(n, mode int) Key {
+	// invert wheel: 7 is its own inverse mod 12
+	p := (7 * (n - 8 + 12)) % 12
+	if mode == Minor {
+		p = (p + 9) % 12
+	}
+	return Key{Pitch: p, Mode: mode}
+}
+
+// ParseCamelot parses a key in Camelot notation, e.g: "8B"
+func ParseCamelot(s string) (Key, error) {
+	return parseWheel(s, "A", "B", 0)
+}
+
+// ParseOpenKey parses a key in Open Key notation, e.g: "1d"
+func ParseOpenKey(s string) (Key, error) {
+	return parseWheel(s, "m", "d", 7)
+}
+
+func parseWheel(s, minor, major string, shift int) (Key, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return Key{}, fmt.Errorf("Invalid key: %q", s)
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 1 || n > 12 {
+		return Key{}, fmt.Errorf("Invalid key: %q", s)
+	}
+	n = (n+shift-1)%12 + 1
+
+	switch strings.ToLower(s[len(s)-1:]) {
+	case strings.ToLower(minor):
+		return fromWheel(n, Minor), nil
+	case strings.ToLower(major):
+		return fromWheel(n, Major), nil
+	}
+	return Key{}, fmt.Errorf("Invalid key: %q", s)
+}
+
+// Distance returns the number of steps between the keys on the Camelot wheel,
+// moving one number or switching between minor and major is one step
+func Distance(a, b Key) int {
+	d := a.wheel() - b.wheel()
+	if d < 0 {
+		d = -d
+	}
+
+	if d > 6 {
+		d = 12 - d
+	}
+
+	if a.Mode != b.Mode {
+		d++
+	}
+	return d
+}
+
+// Compatible returns the keys that mix harmonically with k: itself, one step around the wheel
+// either way, and its relative major or minor
+func (k Key) Compatible() []Key {
+	n := k.wheel()
+	other := Major
+	if k.Mode == Major {
+		other = Minor
+	}
+
+	return []Key{
+		k,
+		fromWheel(n%12+1, k.Mode),
+		fromWheel((n+10)%12+1, k.Mode),
+		fromWheel(n, other),
+	}
+}
+
+// CompatibleWith checks if the keys mix harmonically
+func (k Key) CompatibleWith(o Key) bool { return Distance(k, o) <= 1 }
+
+// BPMDiff returns the relative tempo difference in percent between a track and the seed,
+// counting the track at half and double time too
+func BPMDiff(seed, tempo float32) float32 {
+	if seed <= 0 || tempo <= 0 {
+		return float32(math.Inf(1))
+	}
+
+	best := math.Inf(1)
+	for _, t := range []float64{float64(tempo), float64(tempo) * 2, float64(tempo) / 2} {
+		best = math.Min(best, math.Abs(t-float64(seed))/float64(seed)*100)
+	}
+	return float32(best)
+}
+
+// Options tune the ranking
+// MaxBPMDiff is the largest tempo difference in percent that is still compatible, defaults to 6.
+// MaxKeyDistance is the largest distance on the Camelot wheel that is still compatible, defaults to 1 when nil.
+// Set it with spotify.Int, spotify.Int(0) only allows the same key.
+type Options struct {
+	MaxBPMDiff     float32
+	MaxKeyDistance *int
+}
+
+// Match is a candidate ranked against the seed
+type Match struct {
+	Features    *spotify.AudioFeatures
+	Key         Key
+	KeyDistance int
+	BPMDiff     float32
+
+	// HasKey is false when no key was detected for the candidate, Key is then unset and KeyDistance 7
+	HasKey bool
+
+	// Compatible is set when both the key and the tempo are within the limits of the options
+	Compatible bool
+
+	// Score is lower for better matches, the key distance and tempo difference are weighed by their limits
+	Score float32
+}
+
+// Rank ranks the candidates by how well they mix with the seed, best first
+// Candidates without a detected key are ranked after all candidates with one.
+func Rank(seed *spotify.AudioFeatures, candidates []*spotify.AudioFeatures, opts *Options) ([]*Match, error) {
+	seedKey, ok := FromFeatures(seed)
+	if !ok {
+		return nil, fmt.Errorf("Seed track has no detected key")
+	}
+
+	maxBPMDiff, maxKeyDistance := float32(6), 1
+	if opts != nil {
+		if opts.MaxBPMDiff > 0 {
+			maxBPMDiff = opts.MaxBPMDiff
+		}
+
+		if opts.MaxKeyDistance != nil {
+			if *opts.MaxKeyDistance < 0 {
+				return nil, fmt.Errorf("Invalid maximum key distance: %d", *opts.MaxKeyDistance)
+			}
+			maxKeyDistance = *opts.MaxKeyDistance
+		}
+	}
+
+	// with only the same key allowed, any distance weighs as if the limit were 1
+	keyWeight := float32(maxKeyDistance)
+	if keyWeight == 0 {
+		keyWeight = 1
+	}
+
+	matches := make([]*Match, 0, len(candidates))
+	for _, f := range candidates {
+		if f == nil {
+			continue
+		}
+
+		m := &Match{Features: f, BPMDiff: BPMDiff(seed.Tempo, f.Tempo)}
+		key, ok := FromFeatures(f)
+		m.HasKey = ok
+		if ok {
+			m.Key = key
+			m.KeyDistance = Distance(seedKey, key)
+		} else {
+			m.KeyDistance = 7
+		}
+
+		m.Compatible = ok && m.KeyDistance <= maxKeyDistance && m.BPMDiff <= maxBPMDiff
+		m.Score = float32(m.KeyDistance)/keyWeight + m.BPMDiff/maxBPMDiff
+		matches = append(matches, m)
+	}
+
+	// a keyless candidate can score better than a keyed one on tempo alone, so it's sorted apart
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].HasKey != matches[j].HasKey {
+			return matches[i].HasKey
+		}
+		return matches[i].Score < matches[j].Score
+	})
+	return matches, nil
+}
+
+// RankTracks fetches the audio features of the seed and the candidates and ranks them with Rank
+func RankTracks(c spotify.Catalog, seed spotify.ID, candidates []spotify.ID, opts *Options) ([]*Match, error) {
+	ids := append([]spotify.ID{seed}, candidates...)
+
+	var feats []*spotify.AudioFeatures
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > 100 {
+			n = 100
+		}
+
+		batch, err := c.GetAudioFeatures(ids[:n])
+		if err != nil {
+			return nil, err
+		}
+		feats = append(feats, batch...)
+		ids = ids[n:]
+	}
+
+	if len(feats) == 0 || feats[0] == nil {
+		return nil, fmt.Errorf("No audio features for seed track %s", seed)
+	}
+	return Rank(feats[0], feats[1:], opts)
+}
@@ -0,0 +1,233 @@
+package harmonic
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// keys are all 24 keys in the three notations
+var keys = []struct {
+	key     Key
+	name    string
+	camelot string
+	openKey string
+}{
+	{Key{0, Major}, "C", "8B", "1d"},
+	{Key{7, Major}, "G", "9B", "2d"},
+	{Key{2, Major}, "D", "10B", "3d"},
+	{Key{9, Major}, "A", "11B", "4d"},
+	{Key{4, Major}, "E", "12B", "5d"},
+	{Key{11, Major}, "B", "1B", "6d"},
+	{Key{6, Major}, "F#", "2B", "7d"},
+	{Key{1, Major}, "C#", "3B", "8d"},
+	{Key{8, Major}, "G#", "4B", "9d"},
+	{Key{3, Major}, "D#", "5B", "10d"},
+	{Key{10, Major}, "A#", "6B", "11d"},
+	{Key{5, Major}, "F", "7B", "12d"},
+	{Key{9, Minor}, "Am", "8A", "1m"},
+	{Key{4, Minor}, "Em", "9A", "2m"},
+	{Key{11, Minor}, "Bm", "10A", "3m"},
+	{Key{6, Minor}, "F#m", "11A", "4m"},
+	{Key{1, Minor}, "C#m", "12A", "5m"},
+	{Key{8, Minor}, "G#m", "1A", "6m"},
+	{Key{3, Minor}, "D#m", "2A", "7m"},
+	{Key{10, Minor}, "A#m", "3A", "8m"},
+	{Key{5, Minor}, "Fm", "4A", "9m"},
+	{Key{0, Minor}, "Cm", "5A", "10m"},
+	{Key{7, Minor}, "Gm", "6A", "11m"},
+	{Key{2, Minor}, "Dm", "7A", "12m"},
+}
+
+func TestNotations(t *testing.T) {
+	for _, tt := range keys {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.String(); got != tt.name {
+				t.Errorf("String() = %s", got)
+			}
+			if got := tt.key.Camelot(); got != tt.camelot {
+				t.Errorf("Camelot() = %s, want %s", got, tt.camelot)
+			}
+			if got := tt.key.OpenKey(); got != tt.openKey {
+				t.Errorf("OpenKey() = %s, want %s", got, tt.openKey)
+			}
+
+			if got, err := ParseCamelot(tt.camelot); err != nil || got != tt.key {
+				t.Errorf("ParseCamelot(%s) = %v, %v", tt.camelot, got, err)
+			}
+			if got, err := ParseOpenKey(tt.openKey); err != nil || got != tt.key {
+				t.Errorf("ParseOpenKey(%s) = %v, %v", tt.openKey, got, err)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{"", "8", "B", "0B", "13A", "8C", "8d", "xB", "-1A"} {
+		if k, err := ParseCamelot(s); err == nil {
+			t.Errorf("ParseCamelot(%q) = %v, want an error", s, k)
+		}
+	}
+
+	for _, s := range []string{"", "1", "0d", "13m", "1A", "1B"} {
+		if k, err := ParseOpenKey(s); err == nil {
+			t.Errorf("ParseOpenKey(%q) = %v, want an error", s, k)
+		}
+	}
+
+	if k, err := ParseCamelot(" 8b "); err != nil || k != (Key{0, Major}) {
+		t.Errorf("ParseCamelot is case sensitive: %v, %v", k, err)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"same", "8B", "8B", 0},
+		{"relative minor", "8B", "8A", 1},
+		{"relative major", "1A", "1B", 1},
+		{"one up", "8B", "9B", 1},
+		{"one down", "8A", "7A", 1},
+		{"up across 12", "12B", "1B", 1},
+		{"down across 1", "1A", "12A", 1},
+		{"two up", "8B", "10B", 2},
+		{"diagonal", "8B", "9A", 2},
+		{"opposite", "8B", "2B", 6},
+		{"opposite and mode", "8B", "2A", 7},
+		{"shorter way round", "2A", "11A", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := ParseCamelot(tt.a)
+			b, _ := ParseCamelot(tt.b)
+			if got := Distance(a, b); got != tt.want {
+				t.Errorf("Distance(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := Distance(b, a); got != tt.want {
+				t.Errorf("Distance(%s, %s) = %d, want %d", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	for _, tt := range keys {
+		t.Run(tt.name, func(t *testing.T) {
+			var want []Key
+			for _, o := range keys {
+				if tt.key.CompatibleWith(o.key) {
+					want = append(want, o.key)
+				}
+			}
+
+			got := tt.key.Compatible()
+			if len(got) != 4 || len(want) != 4 || got[0] != tt.key {
+				t.Fatalf("got %v, compatible keys are %v", got, want)
+			}
+			for _, k := range got {
+				if Distance(tt.key, k) > 1 {
+					t.Errorf("%s isn't compatible with %s", k.Camelot(), tt.camelot)
+				}
+			}
+
+			relative := got[3]
+			if relative.Mode == tt.key.Mode || relative.wheel() != tt.key.wheel() {
+				t.Errorf("got %s as the relative key of %s", relative.Camelot(), tt.camelot)
+			}
+		})
+	}
+}
+
+func TestBPMDiff(t *testing.T) {
+	tests := []struct {
+		seed, tempo float32
+		want        float64
+	}{
+		{120, 120, 0},
+		{120, 126, 5},
+		{120, 114, 5},
+		{120, 60, 0},
+		{120, 243, 1.25},
+		{0, 120, math.Inf(1)},
+		{120, 0, math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		if got := float64(BPMDiff(tt.seed, tt.tempo)); math.Abs(got-tt.want) > 1e-4 && got != tt.want {
+			t.Errorf("BPMDiff(%v, %v) = %v, want %v", tt.seed, tt.tempo, got, tt.want)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	features := func(id string, key, mode int, tempo float32) *spotify.AudioFeatures {
+		return &spotify.AudioFeatures{ID: spotify.ID(id), Key: key, Mode: mode, Tempo: tempo}
+	}
+
+	seed := features("seed", 0, Major, 120)
+	candidates := []*spotify.AudioFeatures{
+		features("keyless", -1, Major, 120),
+		features("two steps", 4, Minor, 120),
+		features("neighbour", 7, Major, 121),
+		nil,
+		features("same key", 0, Major, 126),
+		features("too fast", 0, Major, 140),
+	}
+
+	tests := []struct {
+		name       string
+		opts       *Options
+		order      []string
+		compatible []string
+		wantErr    bool
+	}{
+		{"defaults", nil,
+			[]string{"same key", "neighbour", "two steps", "too fast", "keyless"}, []string{"same key", "neighbour"}, false},
+		{"unset distance is 1", &Options{MaxBPMDiff: 6},
+			[]string{"same key", "neighbour", "two steps", "too fast", "keyless"}, []string{"same key", "neighbour"}, false},
+		{"same key only", &Options{MaxKeyDistance: spotify.Int(0)},
+			[]string{"same key", "neighbour", "two steps", "too fast", "keyless"}, []string{"same key"}, false},
+		{"two steps", &Options{MaxKeyDistance: spotify.Int(2)},
+			[]string{"neighbour", "same key", "two steps", "too fast", "keyless"}, []string{"neighbour", "same key", "two steps"}, false},
+		{"wider tempo", &Options{MaxBPMDiff: 20},
+			[]string{"same key", "too fast", "neighbour", "two steps", "keyless"}, []string{"same key", "too fast", "neighbour"}, false},
+		{"negative distance", &Options{MaxKeyDistance: spotify.Int(-1)}, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := Rank(seed, candidates, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var order, compatible []string
+			for _, m := range matches {
+				order = append(order, string(m.Features.ID))
+				if m.Compatible {
+					compatible = append(compatible, string(m.Features.ID))
+				}
+				if math.IsNaN(float64(m.Score)) || math.IsInf(float64(m.Score), 0) {
+					t.Errorf("%s scored %v", m.Features.ID, m.Score)
+				}
+			}
+
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("ranked %v, want %v", order, tt.order)
+			}
+			if !reflect.DeepEqual(compatible, tt.compatible) {
+				t.Errorf("compatible %v, want %v", compatible, tt.compatible)
+			}
+		})
+	}
+
+	if _, err := Rank(features("seed", -1, Major, 120), candidates, nil); err == nil {
+		t.Error("ranked against a seed without a key")
+	}
+}
@@ -0,0 +1,209 @@
+package spotify
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ID is the base62 identifier of a catalog object, or the username of a user
+type ID string
+
+// URI is a Spotify URI, e.g: "spotify:track:6rqhFgbbKwnb9MLmUQDhG6"
+type URI string
+
+// Kind is the type of object an ID or URI refers to
+type Kind string
+
+const (
+	KindTrack     Kind = "track"
+	KindAlbum     Kind = "album"
+	KindArtist    Kind = "artist"
+	KindPlaylist  Kind = "playlist"
+	KindShow      Kind = "show"
+	KindEpisode   Kind = "episode"
+	KindAudiobook Kind = "audiobook"
+	KindChapter   Kind = "chapter"
+	KindUser      Kind = "user"
+)
+
+// idLength is the length of a base62 catalog ID
+const idLength = 22
+
+func (k Kind) valid() bool {
+	switch k {
+	case KindTrack, KindAlbum, KindArtist, KindPlaylist, KindShow, KindEpisode, KindAudiobook, KindChapter, KindUser:
+		return true
+	}
+	return false
+}
+
+// Valid checks that the ID is 22 base62 characters
+// User IDs are usernames and don't have to be valid.
+func (id ID) Valid() bool {
+	if len(id) != idLength {
+		return false
+	}
+
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// URI returns the URI of the object of the kind with the ID
+func (id ID) URI(kind Kind) URI { return URI("spotify:" + string(kind) + ":" + string(id)) }
+
+func (id ID) String() string { return string(id) }
+
+// Kind returns the kind of the URI, or "" if it isn't of the form spotify:kind:id
+func (u URI) Kind() Kind {
+	kind, _, err := splitURI(string(u))
+	if err != nil {
+		return ""
+	}
+	return kind
+}
+
+// ID returns the ID of the URI, or "" if it isn't of the form spotify:kind:id
+func (u URI) ID() ID {
+	_, id, err := splitURI(string(u))
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+func (u URI) String() string { return string(u) }
+
+// ParseURI parses a Spotify URI or an open.spotify.com link, as copied from the share menu
+// Accepted forms are, e.g:
+// spotify:track:6rqhFgbbKwnb9MLmUQDhG6
+// spotify:user:someone:playlist:37i9dQZF1DXcBWIGoYBM5M
+// https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6?si=abc
+// https://open.spotify.com/intl-de/album/4aawyAB9vmqN3uQ7FjRGTy
+// https://open.spotify.com/embed/playlist/37i9dQZF1DXcBWIGoYBM5M
+//
+// spotify.link short links aren't supported, they only redirect to an open.spotify.com link
+// and have to be followed with an HTTP request first.
+func ParseURI(s string) (URI, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "spotify:") {
+		kind, id, err := splitURI(s)
+		if err != nil {
+			return "", err
+		}
+		return id.URI(kind), nil
+	}
+
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("Invalid Spotify link: %v", err)
+	}
+
+	switch host := strings.ToLower(u.Host); host {
+	case "open.spotify.com", "play.spotify.com":
+	case "spotify.link", "spotify.app.link":
+		return "", fmt.Errorf("Invalid Spotify link: short links aren't supported, open %s and use the link it redirects to", s)
+	default:
+		return "", fmt.Errorf("Invalid Spotify link: unknown host %s", u.Host)
+	}
+
+	var parts []string
+	for _, p := range strings.Split(u.Path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	// the locale and the embed player come before the kind: /intl-de/embed/track/id
+	if len(parts) > 0 && strings.HasPrefix(parts[0], "intl-") {
+		parts = parts[1:]
+	}
+	if len(parts) > 0 && parts[0] == "embed" {
+		parts = parts[1:]
+	}
+
+	// legacy playlist links have the owner in front: /user/someone/playlist/id
+	if len(parts) == 4 && parts[0] == string(KindUser) && parts[2] == string(KindPlaylist) {
+		parts = parts[2:]
+	}
+
+	if len(parts) != 2 {
+		return "", fmt.Errorf("Invalid Spotify link: %s", s)
+	}
+	return newURI(Kind(parts[0]), ID(parts[1]))
+}
+
+// ParseID returns the ID of a URI or link, or the ID itself if it's a valid bare ID
+func ParseID(s string) (ID, error) {
+	s = strings.TrimSpace(s)
+	if id := ID(s); id.Valid() {
+		return id, nil
+	}
+
+	u, err := ParseURI(s)
+	if err != nil {
+		return "", err
+	}
+	return u.ID(), nil
+}
+
+func splitURI(s string) (Kind, ID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 3 || parts[0] != "spotify" {
+		return "", "", fmt.Errorf("Invalid Spotify URI: %s", s)
+	}
+
+	// legacy playlist URIs have the owner in front: spotify:user:someone:playlist:id
+	if len(parts) == 5 && parts[1] == string(KindUser) && parts[3] == string(KindPlaylist) {
+		parts = []string{parts[0], parts[3], parts[4]}
+	}
+
+	if len(parts) != 3 {
+		return "", "", fmt.Errorf("Invalid Spotify URI: %s", s)
+	}
+
+	kind, id := Kind(parts[1]), ID(parts[2])
+	return kind, id, checkKindID(kind, id)
+}
+
+func newURI(kind Kind, id ID) (URI, error) {
+	if err := checkKindID(kind, id); err != nil {
+		return "", err
+	}
+	return id.URI(kind), nil
+}
+
+func checkKindID(kind Kind, id ID) error {
+	if !kind.valid() {
+		return fmt.Errorf("Invalid Spotify URI: unknown kind %q", kind)
+	}
+
+	if kind == KindUser {
+		if id == "" {
+			return fmt.Errorf("Invalid Spotify URI: missing user ID")
+		}
+		return nil
+	}
+
+	if !id.Valid() {
+		return fmt.Errorf("Invalid Spotify URI: %q is not a base62 ID", id)
+	}
+	return nil
+}
+
+// idStrings converts the IDs for the endpoint functions
+func idStrings(ids []ID) []string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = string(id)
+	}
+	return strs
+}
@@ -0,0 +1,113 @@
+package spotify
+
+import "testing"
+
+func TestParseURI(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    URI
+		wantErr bool
+	}{
+		// URIs
+		{"spotify:track:6rqhFgbbKwnb9MLmUQDhG6", "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"  spotify:album:4aawyAB9vmqN3uQ7FjRGTy\n", "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", false},
+		{"spotify:user:someone:playlist:37i9dQZF1DXcBWIGoYBM5M", "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M", false},
+		{"spotify:user:someone", "spotify:user:someone", false},
+		{"spotify:episode:512ojhOuo1ktJprKbVcKyQ", "spotify:episode:512ojhOuo1ktJprKbVcKyQ", false},
+
+		// links
+		{"https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6", "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6?si=a1b2c3d4e5f64a7b", "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"https://open.spotify.com/intl-de/album/4aawyAB9vmqN3uQ7FjRGTy", "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", false},
+		{"https://open.spotify.com/intl-pt/track/6rqhFgbbKwnb9MLmUQDhG6?si=x&context=spotify%3Aplaylist%3A37i9dQZF1DXcBWIGoYBM5M", "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"https://open.spotify.com/embed/playlist/37i9dQZF1DXcBWIGoYBM5M", "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M", false},
+		{"https://open.spotify.com/intl-fr/embed/show/5CfCWKI5pZ28U0uOzXkDHe", "spotify:show:5CfCWKI5pZ28U0uOzXkDHe", false},
+		{"https://open.spotify.com/user/someone/playlist/37i9dQZF1DXcBWIGoYBM5M", "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M", false},
+		{"https://open.spotify.com/user/intl-someone", "spotify:user:intl-someone", false},
+		{"https://open.spotify.com/artist/0kbYTNQb4Pb1rPbbaF0pT4/", "spotify:artist:0kbYTNQb4Pb1rPbbaF0pT4", false},
+		{"http://OPEN.SPOTIFY.COM/track/6rqhFgbbKwnb9MLmUQDhG6#top", "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6", "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"https://play.spotify.com/album/4aawyAB9vmqN3uQ7FjRGTy", "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", false},
+
+		// bad input
+		{"", "", true},
+		{"6rqhFgbbKwnb9MLmUQDhG6", "", true},
+		{"spotify:track", "", true},
+		{"spotify:song:6rqhFgbbKwnb9MLmUQDhG6", "", true},
+		{"spotify:track:6rqhFgbbKwnb9MLmUQDhG", "", true},
+		{"spotify:track:6rqhFgbbKwnb9MLmUQDhG6:extra", "", true},
+		{"spotify:user:", "", true},
+		{"spotify:local:Miles+Davis:Kind+of+Blue:So+What:562", "", true},
+		{"https://open.spotify.com/track/6rqhFgbb-wnb9MLmUQDhG6", "", true},
+		{"https://open.spotify.com/track", "", true},
+		{"https://open.spotify.com/de/track/6rqhFgbbKwnb9MLmUQDhG6", "", true},
+		{"https://example.com/track/6rqhFgbbKwnb9MLmUQDhG6", "", true},
+		{"https://open.spotify.com.example.com/track/6rqhFgbbKwnb9MLmUQDhG6", "", true},
+		{"https://spotify.link/aBcDeFgHiJk", "", true},
+		{"https://spotify.app.link/aBcDeFgHiJk?_p=c91d", "", true},
+		{"https://open.spotify.com/%zz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseURI(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ID
+		wantErr bool
+	}{
+		{"6rqhFgbbKwnb9MLmUQDhG6", "6rqhFgbbKwnb9MLmUQDhG6", false},
+		{" 6rqhFgbbKwnb9MLmUQDhG6 ", "6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"spotify:track:6rqhFgbbKwnb9MLmUQDhG6", "6rqhFgbbKwnb9MLmUQDhG6", false},
+		{"spotify:user:someone:playlist:37i9dQZF1DXcBWIGoYBM5M", "37i9dQZF1DXcBWIGoYBM5M", false},
+		{"https://open.spotify.com/intl-ja/album/4aawyAB9vmqN3uQ7FjRGTy?si=abc", "4aawyAB9vmqN3uQ7FjRGTy", false},
+		{"spotify:user:someone", "someone", false},
+		{"6rqhFgbbKwnb9MLmUQDhG", "", true},
+		{"6rqhFgbbKwnb9MLmUQDhG6x", "", true},
+		{"6rqhFgbbKwnb9MLmUQ_hG6", "", true},
+		{"https://spotify.link/aBcDeFgHiJk", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseID(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURIAccessors(t *testing.T) {
+	tests := []struct {
+		uri  URI
+		kind Kind
+		id   ID
+	}{
+		{"spotify:track:6rqhFgbbKwnb9MLmUQDhG6", KindTrack, "6rqhFgbbKwnb9MLmUQDhG6"},
+		{"spotify:user:someone:playlist:37i9dQZF1DXcBWIGoYBM5M", KindPlaylist, "37i9dQZF1DXcBWIGoYBM5M"},
+		{"spotify:local:::song:180", "", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		if kind, id := tt.uri.Kind(), tt.uri.ID(); kind != tt.kind || id != tt.id {
+			t.Errorf("%q got %q %q, want %q %q", tt.uri, kind, id, tt.kind, tt.id)
+		}
+	}
+}
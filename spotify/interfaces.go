@@ -0,0 +1,140 @@
+package spotify
+
+import "context"
+
+// The interfaces split the methods of Client by area, so code can depend on the smallest one it needs
+// and substitute a fake or a wrapper, see the mock package.
+
+// Catalog looks up albums, artists, tracks, shows, episodes and audiobooks
+type Catalog interface {
+	GetAlbum(id ID) (*FullAlbum, error)
+	GetAlbums(ids []ID) ([]*FullAlbum, error)
+	GetAlbumTracks(id ID, limit, offset int) (*Paging, error)
+	GetArtist(id ID) (*FullArtist, error)
+	GetArtists(ids []ID) ([]*FullArtist, error)
+	GetArtistAlbums(id ID, limit, offset int) (*Paging, error)
+	GetArtistTopTracks(id ID, market string) ([]*FullTrack, error)
+	GetRelatedArtists(id ID) ([]*FullArtist, error)
+	GetTrack(id ID, market string) (*FullTrack, error)
+	GetTracks(ids []ID, market string) ([]*FullTrack, error)
+	GetAudioAnalysis(id ID) (*AudioAnalysis, error)
+	GetAudioFeature(id ID) (*AudioFeatures, error)
+	GetAudioFeatures(ids []ID) ([]*AudioFeatures, error)
+	GetShow(id ID, market string) (*Show, error)
+	GetShows(ids []ID, market string) ([]*Show, error)
+	GetShowEpisodes(id ID, market string, limit, offset int) (*Paging, error)
+	GetEpisode(id ID, market string) (*Episode, error)
+	GetEpisodes(ids []ID, market string) ([]*Episode, error)
+	GetAudiobook(id ID, market string) (*Audiobook, error)
+	GetAudiobooks(ids []ID, market string) ([]*Audiobook, error)
+	GetAudiobookChapters(id ID, market string, limit, offset int) (*Paging, error)
+	GetChapter(id ID, market string) (*Chapter, error)
+	GetChapters(ids []ID, market string) ([]*Chapter, error)
+}
+
+// Searcher searches the catalog
+type Searcher interface {
+	Search(ctx context.Context, query string, types []SearchType, opts *SearchOptions) (*SearchResult, error)
+	SearchTrack(query string, offset int) ([]*FullTrack, error)
+	SearchAlbum(query string, offset int) ([]*SimpleAlbum, error)
+	SearchArtist(query string, offset int) ([]*FullArtist, error)
+	TrackByISRC(ctx context.Context, isrc string) ([]*FullTrack, error)
+	AlbumByUPC(ctx context.Context, upc string) ([]*FullAlbum, error)
+	TracksByISRC(ctx context.Context, isrcs []string) (*ISRCResult, error)
+	AlbumsByUPC(ctx context.Context, upcs []string) (*UPCResult, error)
+}
+
+// Library manages the current user's profile, saved items and followed artists
+type Library interface {
+	GetCurrentUser() (*User, error)
+	GetSavedTracks(market string, limit, offset int) (*Paging, error)
+	AllSavedTracks(market string) ([]*SavedTrack, error)
+	SaveTracks(ids []ID) error
+	RemoveSavedTracks(ids []ID) error
+	HasTracksSaved(ids []ID) ([]bool, error)
+	GetSavedAlbums(market string, limit, offset int) (*Paging, error)
+	AllSavedAlbums(market string) ([]*SavedAlbum, error)
+	SaveAlbums(ids []ID) error
+	RemoveSavedAlbums(ids []ID) error
+	HasAlbumsSaved(ids []ID) ([]bool, error)
+	GetSavedShows(limit, offset int) (*Paging, error)
+	AllSavedShows() ([]*SavedShow, error)
+	SaveShows(ids []ID) error
+	RemoveSavedShows(ids []ID) error
+	HasShowsSaved(ids []ID) ([]bool, error)
+	GetSavedEpisodes(market string, limit, offset int) (*Paging, error)
+	SaveEpisodes(ids []ID) error
+	RemoveSavedEpisodes(ids []ID) error
+	HasEpisodesSaved(ids []ID) ([]bool, error)
+	GetSavedAudiobooks(limit, offset int) (*Paging, error)
+	SaveAudiobooks(ids []ID) error
+	RemoveSavedAudiobooks(ids []ID) error
+	HasAudiobooksSaved(ids []ID) ([]bool, error)
+	GetFollowedArtists(limit int, after string) (*ArtistCursorPage, error)
+	AllFollowedArtists() ([]*FullArtist, error)
+	FollowArtists(ids []ID) error
+	UnfollowArtists(ids []ID) error
+}
+
+// Playlists reads, creates, edits and follows playlists
+type Playlists interface {
+	GetPlaylist(uid, pid ID) (*FullPlaylist, error)
+	CreatePlaylist(uid ID, name, description string, public bool) (*FullPlaylist, error)
+	GetPlaylistTracks(uid, pid ID, limit, offset int) (*Paging, error)
+	GetPlaylistTracksForMarket(uid, pid ID, market string, limit, offset int) (*Paging, error)
+	AllPlaylistTracks(uid, pid ID) ([]*PlaylistTrack, error)
+	AllPlaylistTracksForMarket(uid, pid ID, market string) ([]*PlaylistTrack, error)
+	AddTracksToPlaylist(uid, pid ID, uris []URI, position int) (string, error)
+	ReplacePlaylistTracks(uid, pid ID, uris []URI) (string, error)
+	RemovePlaylistTracks(uid, pid ID, tracks []TrackPositions, snapshotID string) (string, error)
+	ReorderPlaylistTracks(uid, pid ID, rangeStart, insertBefore, rangeLength int, snapshotID string) (string, error)
+	GetMyPlaylists(limit, offset int) (*Paging, error)
+	AllMyPlaylists() ([]*SimplePlaylist, error)
+	UserFollowPlaylist(oid, pid ID) error
+	UserUnfollowPlaylist(oid, pid ID) error
+	UsersFollowsPlaylist(oid, pid ID, uid []ID) ([]bool, error)
+}
+
+// Player reads and controls the playback on the user's devices
+type Player interface {
+	GetPlaybackState(market string) (*PlaybackState, error)
+	GetCurrentlyPlaying(market string) (*CurrentlyPlaying, error)
+	GetDevices() ([]*Device, error)
+	TransferPlayback(deviceID ID, play bool) error
+	Play(deviceID ID, opts *PlayOptions) error
+	Pause(deviceID ID) error
+	Next(deviceID ID) error
+	Previous(deviceID ID) error
+	Seek(deviceID ID, positionMs int) error
+	SetRepeat(deviceID ID, state string) error
+	SetVolume(deviceID ID, percent int) error
+	SetShuffle(deviceID ID, shuffle bool) error
+	GetQueue() (*Queue, error)
+	AddToQueue(deviceID ID, uri URI) error
+	GetRecentlyPlayed(limit int, before string) (*PlayHistoryCursorPage, error)
+}
+
+// Browser gets the featured content, categories and recommendations
+type Browser interface {
+	GetFeaturedPlaylists(locale, country, timestamp string, limit, offset int) (*Paging, error)
+	GetNewReleases(country string, limit, offset int) (*Paging, error)
+	GetCategories(country, locale string, offset, limit int) (*Paging, error)
+	GetCategory(name, country, locale string) (*Category, error)
+	GetCategoryPlaylists(name, country string, limit, offset int) (*Paging, error)
+	GetRecommendations(args ...string) (*Recommendations, error)
+	GetRecommendationsWithOptions(opts *RecommendationOptions) (*Recommendations, error)
+	AvailableMarkets() ([]string, error)
+	AvailableGenreSeeds() ([]string, error)
+}
+
+// API is everything a Client does
+type API interface {
+	Catalog
+	Searcher
+	Library
+	Playlists
+	Player
+	Browser
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,153 @@
+package spotify
+
+import (
+	"encoding/json"
+)
+
+// GetSavedTracks gets a page of the tracks saved in the user's library
+// The items of the page are SavedTracks
+func (c *Client) GetSavedTracks(market string, limit, offset int) (*Paging, error) {
+	vals := marketValues(market)
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetSavedTracks(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// AllSavedTracks gets all the tracks saved in the user's library, following the paging
+func (c *Client) AllSavedTracks(market string) ([]*SavedTrack, error) {
+	var tracks []*SavedTrack
+	for offset := 0; ; {
+		page, err := c.GetSavedTracks(market, 50, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*SavedTrack
+		if err = json.Unmarshal(page.Items, &items); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, items...)
+
+		offset += len(items)
+		if page.Next == "" || len(items) == 0 {
+			return tracks, nil
+		}
+	}
+}
+
+// SaveTracks saves at most 50 tracks in the user's library
+func (c *Client) SaveTracks(ids []ID) error {
+	res, err := c.request("PUT", EndpointSaveTracks(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// RemoveSavedTracks removes at most 50 tracks from the user's library
+func (c *Client) RemoveSavedTracks(ids []ID) error {
+	res, err := c.request("DELETE", EndpointRemoveSavedTracks(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// HasTracksSaved checks if the tracks are saved in the user's library
+func (c *Client) HasTracksSaved(ids []ID) ([]bool, error) {
+	res, err := c.request("GET", EndpointHasTracksSaved(idStrings(ids)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var bools []bool
+	err = unmarshal(res, &bools)
+	if err != nil {
+		return nil, err
+	}
+	return bools, nil
+}
+
+// GetSavedAlbums gets a page of the albums saved in the user's library
+// The items of the page are SavedAlbums
+func (c *Client) GetSavedAlbums(market string, limit, offset int) (*Paging, error) {
+	vals := marketValues(market)
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetSavedAlbums(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// SaveAlbums saves at most 20 albums in the user's library
+func (c *Client) SaveAlbums(ids []ID) error {
+	res, err := c.request("PUT", EndpointSaveAlbums(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// RemoveSavedAlbums removes at most 20 albums from the user's library
+func (c *Client) RemoveSavedAlbums(ids []ID) error {
+	res, err := c.request("DELETE", EndpointDeleteAlbums(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// HasAlbumsSaved checks if the albums are saved in the user's library
+func (c *Client) HasAlbumsSaved(ids []ID) ([]bool, error) {
+	res, err := c.request("GET", EndpointContainsAlbums(idStrings(ids)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var bools []bool
+	err = unmarshal(res, &bools)
+	if err != nil {
+		return nil, err
+	}
+	return bools, nil
+}
+
+// AllSavedAlbums gets all the albums saved in the user's library, following the paging
+func (c *Client) AllSavedAlbums(market string) ([]*SavedAlbum, error) {
+	var albums []*SavedAlbum
+	for offset := 0; ; {
+		page, err := c.GetSavedAlbums(market, 50, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*SavedAlbum
+		if err = json.Unmarshal(page.Items, &items); err != nil {
+			return nil, err
+		}
+		albums = append(albums, items...)
+
+		offset += len(items)
+		if page.Next == "" || len(items) == 0 {
+			return albums, nil
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// lookupWorkers is how many lookups the batch variants run at once
+const lookupWorkers = 4
+
+// NormalizeISRC uppercases an ISRC and strips the hyphens and spaces it's often written with
+func NormalizeISRC(isrc string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, isrc))
+}
+
+// NormalizeUPC strips everything but digits and the leading zeros, so UPC-A and EAN-13 codes compare equal
+func NormalizeUPC(upc string) string {
+	return strings.TrimLeft(upcDigits(upc), "0")
+}
+
+// upcDigits strips everything but digits, keeping the leading zeros
+func upcDigits(upc string) string {
+	return strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+		return r
+	}, upc)
+}
+
+// TrackByISRC looks up the tracks with the ISRC
+// The same recording is often released on several albums, so there can be more than one match.
+// No match is not an error, the result is empty.
+func (c *Client) TrackByISRC(ctx context.Context, isrc string) ([]*FullTrack, error) {
+	isrc = NormalizeISRC(isrc)
+	if isrc == "" {
+		return nil, fmt.Errorf("Missing required parameter: isrc")
+	}
+
+	q := &SearchQuery{ISRC: isrc}
+	res, err := c.Search(ctx, q.String(), []SearchType{SearchTypeTrack}, &SearchOptions{Limit: 50})
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []*FullTrack
+	if res.Tracks == nil {
+		return tracks, nil
+	}
+
+	for _, t := range res.Tracks.Items {
+		// the search is fuzzy on identifiers it doesn't know, so check the match
+		if t != nil && NormalizeISRC(t.ExternalIDs.ISRC()) == isrc {
+			tracks = append(tracks, t)
+		}
+	}
+	return tracks, nil
+}
+
+// AlbumByUPC looks up the albums with the UPC
+// The search only returns simplified albums, so the candidates are fetched in full to check their UPC or EAN.
+// The search uses the digits as given since Spotify matches them exactly, leading zeros only matter when comparing.
+func (c *Client) AlbumByUPC(ctx context.Context, upc string) ([]*FullAlbum, error) {
+	digits := upcDigits(upc)
+	upc = NormalizeUPC(digits)
+	if upc == "" {
+		return nil, fmt.Errorf("Missing required parameter: upc")
+	}
+
+	q := &SearchQuery{UPC: digits}
+	res, err := c.Search(ctx, q.String(), []SearchType{SearchTypeAlbum}, &SearchOptions{Limit: 20})
+	if err != nil {
+		return nil, err
+	}
+
+	var albums []*FullAlbum
+	if res.Albums == nil || len(res.Albums.Items) == 0 {
+		return albums, nil
+	}
+
+	ids := make([]ID, 0, len(res.Albums.Items))
+	for _, a := range res.Albums.Items {
+		if a != nil {
+			ids = append(ids, a.ID)
+		}
+	}
+
+	full, err := c.getAlbums(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range full {
+		if a == nil {
+			continue
+		}
+
+		if NormalizeUPC(a.ExternalIDs.UPC()) == upc || NormalizeUPC(a.ExternalIDs.EAN()) == upc {
+			albums = append(albums, a)
+		}
+	}
+	return albums, nil
+}
+
+// getAlbums gets several full albums, at most 20
+func (c *Client) getAlbums(ctx context.Context, ids []ID) ([]*FullAlbum, error) {
+	res, err := c.requestContext(ctx, "GET", EndpointGetAlbums(idStrings(ids)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Albums []*FullAlbum `json:"albums"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Albums, nil
+}
+
+// ISRCResult is the result of TracksByISRC
+// Matches is keyed by the normalized ISRC, Unmatched and Skipped have the codes as they were given.
+// Skipped are the codes that weren't looked up because the context was canceled.
+type ISRCResult struct {
+	Matches   map[string][]*FullTrack
+	Unmatched []string
+	Skipped   []string
+}
+
+// UPCResult is the result of AlbumsByUPC
+// Matches is keyed by the normalized UPC, Unmatched and Skipped have the codes as they were given.
+// Skipped are the codes that weren't looked up because the context was canceled.
+type UPCResult struct {
+	Matches   map[string][]*FullAlbum
+	Unmatched []string
+	Skipped   []string
+}
+
+// TracksByISRC resolves a list of ISRCs concurrently
+// Lookups that fail are reported as unmatched and the first error is returned with the partial result.
+// When the context is canceled, its error is returned and the codes left are in Skipped.
+func (c *Client) TracksByISRC(ctx context.Context, isrcs []string) (*ISRCResult, error) {
+	result := &ISRCResult{Matches: make(map[string][]*FullTrack)}
+	var mu sync.Mutex
+
+	skipped, err := resolveConcurrently(ctx, isrcs, func(code string) error {
+		tracks, err := c.TrackByISRC(ctx, code)
+		if err != nil && ctx.Err() != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil || len(tracks) == 0 {
+			result.Unmatched = append(result.Unmatched, code)
+			return err
+		}
+
+		result.Matches[NormalizeISRC(code)] = tracks
+		return nil
+	})
+	result.Skipped = skipped
+	return result, err
+}
+
+// AlbumsByUPC resolves a list of UPCs concurrently
+// Lookups that fail are reported as unmatched and the first error is returned with the partial result.
+// When the context is canceled, its error is returned and the codes left are in Skipped.
+func (c *Client) AlbumsByUPC(ctx context.Context, upcs []string) (*UPCResult, error) {
+	result := &UPCResult{Matches: make(map[string][]*FullAlbum)}
+	var mu sync.Mutex
+
+	skipped, err := resolveConcurrently(ctx, upcs, func(code string) error {
+		albums, err := c.AlbumByUPC(ctx, code)
+		if err != nil && ctx.Err() != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil || len(albums) == 0 {
+			result.Unmatched = append(result.Unmatched, code)
+			return err
+		}
+
+		result.Matches[NormalizeUPC(code)] = albums
+		return nil
+	})
+	result.Skipped = skipped
+	return result, err
+}
+
+// resolveConcurrently calls fn for every code with at most lookupWorkers at once and returns the first error
+// Once the context is canceled no more codes are looked up, and lookups that fail then are cut short too.
+// Those codes are returned as skipped, in the order they were given, along with the context's error.
+func resolveConcurrently(ctx context.Context, codes []string, fn func(code string) error) ([]string, error) {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		jobs     = make(chan int)
+		done     = make([]bool, len(codes))
+	)
+
+	for i := 0; i < lookupWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+
+				err := fn(codes[i])
+				if err != nil && ctx.Err() != nil {
+					continue
+				}
+
+				done[i] = true
+				if err != nil {
+					once.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range codes {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var skipped []string
+	for i, code := range codes {
+		if !done[i] {
+			skipped = append(skipped, code)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return skipped, err
+	}
+	return skipped, firstErr
+}
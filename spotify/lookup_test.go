@@ -0,0 +1,110 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// isrcSearch answers a search for an ISRC with a track that has it, except for the ISRCs ending in 0
+func isrcSearch(w http.ResponseWriter, r *http.Request) {
+	isrc := strings.TrimPrefix(r.URL.Query().Get("q"), "isrc:")
+	if strings.HasSuffix(isrc, "0") {
+		fmt.Fprint(w, `{"tracks":{"items":[]}}`)
+		return
+	}
+	fmt.Fprintf(w, `{"tracks":{"items":[{"id":"t","name":"%s","external_ids":{"isrc":"%s"}}]}}`, isrc, isrc)
+}
+
+func TestTracksByISRC(t *testing.T) {
+	c, _ := apiServer(t, isrcSearch)
+
+	res, err := c.TracksByISRC(context.Background(), []string{"us-abc-12-00001", "USABC1200010", "GBAYE0000001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var matched []string
+	for isrc := range res.Matches {
+		matched = append(matched, isrc)
+	}
+	sort.Strings(matched)
+
+	if !reflect.DeepEqual(matched, []string{"GBAYE0000001", "USABC1200001"}) {
+		t.Errorf("matched %v", matched)
+	}
+	if !reflect.DeepEqual(res.Unmatched, []string{"USABC1200010"}) || res.Skipped != nil {
+		t.Errorf("got %v unmatched and %v skipped", res.Unmatched, res.Skipped)
+	}
+}
+
+func TestTracksByISRCCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var searched int32
+	c, _ := apiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&searched, 1) == 3 {
+			cancel()
+		}
+		isrcSearch(w, r)
+	})
+
+	isrcs := make([]string, 40)
+	for i := range isrcs {
+		isrcs[i] = fmt.Sprintf("USABC12%05d", i)
+	}
+
+	res, err := c.TracksByISRC(ctx, isrcs)
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want the context's", err)
+	}
+
+	// every code is in exactly one of the lists
+	seen := make(map[string]int)
+	for isrc := range res.Matches {
+		seen[isrc]++
+	}
+	for _, isrc := range append(res.Unmatched, res.Skipped...) {
+		seen[isrc]++
+	}
+	for _, isrc := range isrcs {
+		if seen[isrc] != 1 {
+			t.Errorf("%s is reported %d times", isrc, seen[isrc])
+		}
+	}
+
+	if len(res.Skipped) < len(isrcs)-lookupWorkers-3 {
+		t.Errorf("only %d of %d skipped after the cancel", len(res.Skipped), len(isrcs))
+	}
+	if !sort.StringsAreSorted(res.Skipped) {
+		t.Errorf("skipped %v aren't in the order given", res.Skipped)
+	}
+}
+
+func TestAlbumsByUPCCanceledBefore(t *testing.T) {
+	c, requests := apiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("looked up %s after the cancel", r.URL)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	upcs := []string{"886445195805", "0075678263927"}
+	res, err := c.AlbumsByUPC(ctx, upcs)
+	if err != context.Canceled {
+		t.Fatalf("got error %v, want the context's", err)
+	}
+
+	if !reflect.DeepEqual(res.Skipped, upcs) || len(res.Matches) != 0 || res.Unmatched != nil {
+		t.Errorf("got %+v, want every code skipped", res)
+	}
+	if n := atomic.LoadInt32(requests); n != 0 {
+		t.Errorf("%d requests made", n)
+	}
+}
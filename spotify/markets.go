@@ -0,0 +1,140 @@
+package spotify
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultListTTL = 24 * time.Hour
+
+// listCache holds the rarely changing lists fetched by AvailableMarkets and AvailableGenreSeeds
+// Its zero value is ready to use, so a Client made without New has one too.
+type listCache struct {
+	mu      sync.Mutex
+	markets listEntry
+	genres  listEntry
+}
+
+// listEntry is a list and when it was fetched
+type listEntry struct {
+	list []string
+	at   time.Time
+
+	// fetching is the running fetch of the list, nil when none runs
+	fetching *listFetch
+}
+
+// listFetch is a fetch of a list that callers arriving while it runs wait for
+type listFetch struct {
+	done chan struct{}
+	list []string
+	err  error
+}
+
+func (c *Client) listTTL() time.Duration {
+	if c.ListTTL <= 0 {
+		return defaultListTTL
+	}
+	return c.ListTTL
+}
+
+// cachedList returns the list from the cache if it is still fresh, otherwise it fetches and stores it
+// The fetch runs without the lock, so the other list and fresh lookups don't wait on it,
+// and callers that need the list while it runs share its result. Callers get a copy so changing
+// it doesn't change the cache.
+func (c *Client) cachedList(l *listEntry, fetch func() ([]string, error)) ([]string, error) {
+	c.lists.mu.Lock()
+	if l.list != nil && time.Since(l.at) < c.listTTL() {
+		list := append([]string(nil), l.list...)
+		c.lists.mu.Unlock()
+		return list, nil
+	}
+
+	f := l.fetching
+	if f == nil {
+		f = &listFetch{done: make(chan struct{})}
+		l.fetching = f
+		c.lists.mu.Unlock()
+
+		f.list, f.err = fetch()
+
+		c.lists.mu.Lock()
+		if f.err == nil {
+			l.list, l.at = f.list, time.Now()
+		}
+		l.fetching = nil
+		close(f.done)
+	}
+	c.lists.mu.Unlock()
+
+	<-f.done
+	if f.err != nil {
+		return nil, f.err
+	}
+	return append([]string(nil), f.list...), nil
+}
+
+// AvailableMarkets gets the ISO 3166-1 alpha-2 country codes of the markets Spotify is available in
+// The list is cached for ListTTL
+func (c *Client) AvailableMarkets() ([]string, error) {
+	return c.cachedList(&c.lists.markets, func() ([]string, error) {
+		res, err := c.request("GET", EndpointGetMarkets(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var temp struct {
+			Markets []string `json:"markets"`
+		}
+
+		err = unmarshal(res, &temp)
+		if err != nil {
+			return nil, err
+		}
+		return temp.Markets, nil
+	})
+}
+
+// AvailableGenreSeeds gets the genres that can be used as seed_genres in GetRecommendations
+// The list is cached for ListTTL
+func (c *Client) AvailableGenreSeeds() ([]string, error) {
+	return c.cachedList(&c.lists.genres, func() ([]string, error) {
+		res, err := c.request("GET", EndpointGetAvailableGenreSeeds(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var temp struct {
+			Genres []string `json:"genres"`
+		}
+
+		err = unmarshal(res, &temp)
+		if err != nil {
+			return nil, err
+		}
+		return temp.Genres, nil
+	})
+}
+
+// validateCountry checks the country against the available markets when ValidateCountry is set
+// An empty country is always valid since it's optional everywhere, the case doesn't matter
+func (c *Client) validateCountry(country string) error {
+	if !c.ValidateCountry || country == "" {
+		return nil
+	}
+
+	markets, err := c.AvailableMarkets()
+	if err != nil {
+		return err
+	}
+
+	code := strings.ToUpper(country)
+	for _, m := range markets {
+		if m == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid country: %s is not an available market", country)
+}
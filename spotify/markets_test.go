@@ -0,0 +1,123 @@
+package spotify
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestListFetchIsShared(t *testing.T) {
+	release := make(chan struct{})
+	var fail int32 = 1
+	c, requests := apiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/recommendations/available-genre-seeds" {
+			fmt.Fprint(w, `{"genres":["jazz"]}`)
+			return
+		}
+
+		<-release
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"markets":["SE","US"]}`)
+	})
+
+	for _, failing := range []bool{true, false} {
+		if !failing {
+			atomic.StoreInt32(&fail, 0)
+			release = make(chan struct{})
+		}
+		before := atomic.LoadInt32(requests)
+
+		var wg sync.WaitGroup
+		markets := make([][]string, 5)
+		errs := make([]error, len(markets))
+		for i := range markets {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				markets[i], errs[i] = c.AvailableMarkets()
+			}(i)
+		}
+
+		// the other list doesn't wait for the running fetch
+		genres := make(chan error, 1)
+		go func() {
+			_, err := c.AvailableGenreSeeds()
+			genres <- err
+		}()
+		select {
+		case err := <-genres:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("the genre seeds waited for the markets")
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		for i := range markets {
+			if failing {
+				if e, ok := errs[i].(*SpotifyError); !ok || e.Status != http.StatusServiceUnavailable {
+					t.Errorf("call %d got %v, want the 503", i, errs[i])
+				}
+			} else if errs[i] != nil || !reflect.DeepEqual(markets[i], []string{"SE", "US"}) {
+				t.Errorf("call %d got %v, %v", i, markets[i], errs[i])
+			}
+		}
+
+		// one markets request, and the genre seeds once at first
+		want := before + 1
+		if failing {
+			want++
+		}
+		if n := atomic.LoadInt32(requests); n != want {
+			t.Errorf("%d requests made, want %d", n, want)
+		}
+	}
+
+	// each caller has a copy of its own
+	a, _ := c.AvailableMarkets()
+	b, _ := c.AvailableMarkets()
+	a[0] = "XX"
+	if b[0] != "SE" {
+		t.Error("the callers share the list")
+	}
+}
+
+func TestValidateCountry(t *testing.T) {
+	c, requests := apiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"markets":["SE","US"]}`)
+	})
+
+	if err := c.validateCountry("xx"); err != nil || atomic.LoadInt32(requests) != 0 {
+		t.Errorf("validated without ValidateCountry: %v", err)
+	}
+
+	c.ValidateCountry = true
+	tests := []struct {
+		country string
+		wantErr bool
+	}{
+		{"SE", false},
+		{"se", false},
+		{"Us", false},
+		{"", false},
+		{"XX", true},
+		{"swe", true},
+	}
+
+	for _, tt := range tests {
+		if err := c.validateCountry(tt.country); (err != nil) != tt.wantErr {
+			t.Errorf("validateCountry(%q) error = %v, wantErr %v", tt.country, err, tt.wantErr)
+		}
+	}
+}
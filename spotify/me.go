@@ -0,0 +1,132 @@
+package spotify
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// GetCurrentUser gets the profile of the user the client acts for
+func (c *Client) GetCurrentUser() (*User, error) {
+	res, err := c.request("GET", EndpointMe(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{}
+	err = unmarshal(res, user)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetMyPlaylists gets a page of the playlists the user owns or follows
+// The items of the page are SimplePlaylists
+func (c *Client) GetMyPlaylists(limit, offset int) (*Paging, error) {
+	vals := url.Values{}
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetMyPlaylists(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// AllMyPlaylists gets all the playlists the user owns or follows, following the paging
+func (c *Client) AllMyPlaylists() ([]*SimplePlaylist, error) {
+	var playlists []*SimplePlaylist
+	for offset := 0; ; {
+		page, err := c.GetMyPlaylists(50, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*SimplePlaylist
+		if err = json.Unmarshal(page.Items, &items); err != nil {
+			return nil, err
+		}
+		playlists = append(playlists, items...)
+
+		offset += len(items)
+		if page.Next == "" || len(items) == 0 {
+			return playlists, nil
+		}
+	}
+}
+
+// GetFollowedArtists gets a page of the artists the user follows
+// The page is cursor based, pass the After cursor of a page to get the next one, "" for the first.
+func (c *Client) GetFollowedArtists(limit int, after string) (*ArtistCursorPage, error) {
+	vals := url.Values{}
+	vals.Add("type", "artist")
+	if limit > 0 && limit <= 50 {
+		vals.Add("limit", strconv.Itoa(limit))
+	}
+
+	if after != "" {
+		vals.Add("after", after)
+	}
+
+	res, err := c.request("GET", withValues(EndpointGetFollowedArtists(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Artists *ArtistCursorPage `json:"artists"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Artists, nil
+}
+
+// AllFollowedArtists gets all the artists the user follows, following the cursors
+func (c *Client) AllFollowedArtists() ([]*FullArtist, error) {
+	var artists []*FullArtist
+	after := ""
+	for {
+		page, err := c.GetFollowedArtists(50, after)
+		if err != nil {
+			return nil, err
+		}
+
+		if page == nil {
+			return artists, nil
+		}
+		artists = append(artists, page.Items...)
+
+		if page.Next == "" || page.Cursors == nil || page.Cursors.After == "" || len(page.Items) == 0 {
+			return artists, nil
+		}
+		after = page.Cursors.After
+	}
+}
+
+// FollowArtists follows at most 50 artists
+func (c *Client) FollowArtists(ids []ID) error {
+	res, err := c.request("PUT", EndpointFollowArtists(idStrings(ids), "artist"), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// UnfollowArtists unfollows at most 50 artists
+func (c *Client) UnfollowArtists(ids []ID) error {
+	res, err := c.request("DELETE", EndpointUnfollowArtists(idStrings(ids), "artist"), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
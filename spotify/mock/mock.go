@@ -0,0 +1,846 @@
+// Package mock has stand-ins for the interfaces of the spotify package
+// Every method calls the function field of the same name with a Func suffix, a method whose field
+// is nil returns ErrNotMocked so a test notices calls it didn't expect.
+//
+//	lib := &mock.Library{
+//		SaveTracksFunc: func(ids []spotify.ID) error { return nil },
+//	}
+package mock
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// ErrNotMocked is returned by methods without a function
+var ErrNotMocked = fmt.Errorf("mock: method not mocked")
+
+// Catalog is a spotify.Catalog whose methods call its functions
+type Catalog struct {
+	GetAlbumFunc             func(id spotify.ID) (*spotify.FullAlbum, error)
+	GetAlbumsFunc            func(ids []spotify.ID) ([]*spotify.FullAlbum, error)
+	GetAlbumTracksFunc       func(id spotify.ID, limit, offset int) (*spotify.Paging, error)
+	GetArtistFunc            func(id spotify.ID) (*spotify.FullArtist, error)
+	GetArtistsFunc           func(ids []spotify.ID) ([]*spotify.FullArtist, error)
+	GetArtistAlbumsFunc      func(id spotify.ID, limit, offset int) (*spotify.Paging, error)
+	GetArtistTopTracksFunc   func(id spotify.ID, market string) ([]*spotify.FullTrack, error)
+	GetRelatedArtistsFunc    func(id spotify.ID) ([]*spotify.FullArtist, error)
+	GetTrackFunc             func(id spotify.ID, market string) (*spotify.FullTrack, error)
+	GetTracksFunc            func(ids []spotify.ID, market string) ([]*spotify.FullTrack, error)
+	GetAudioAnalysisFunc     func(id spotify.ID) (*spotify.AudioAnalysis, error)
+	GetAudioFeatureFunc      func(id spotify.ID) (*spotify.AudioFeatures, error)
+	GetAudioFeaturesFunc     func(ids []spotify.ID) ([]*spotify.AudioFeatures, error)
+	GetShowFunc              func(id spotify.ID, market string) (*spotify.Show, error)
+	GetShowsFunc             func(ids []spotify.ID, market string) ([]*spotify.Show, error)
+	GetShowEpisodesFunc      func(id spotify.ID, market string, limit, offset int) (*spotify.Paging, error)
+	GetEpisodeFunc           func(id spotify.ID, market string) (*spotify.Episode, error)
+	GetEpisodesFunc          func(ids []spotify.ID, market string) ([]*spotify.Episode, error)
+	GetAudiobookFunc         func(id spotify.ID, market string) (*spotify.Audiobook, error)
+	GetAudiobooksFunc        func(ids []spotify.ID, market string) ([]*spotify.Audiobook, error)
+	GetAudiobookChaptersFunc func(id spotify.ID, market string, limit, offset int) (*spotify.Paging, error)
+	GetChapterFunc           func(id spotify.ID, market string) (*spotify.Chapter, error)
+	GetChaptersFunc          func(ids []spotify.ID, market string) ([]*spotify.Chapter, error)
+}
+
+func (m *Catalog) GetAlbum(id spotify.ID) (*spotify.FullAlbum, error) {
+	if m.GetAlbumFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAlbumFunc(id)
+}
+
+func (m *Catalog) GetAlbums(ids []spotify.ID) ([]*spotify.FullAlbum, error) {
+	if m.GetAlbumsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAlbumsFunc(ids)
+}
+
+func (m *Catalog) GetAlbumTracks(id spotify.ID, limit, offset int) (*spotify.Paging, error) {
+	if m.GetAlbumTracksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAlbumTracksFunc(id, limit, offset)
+}
+
+func (m *Catalog) GetArtist(id spotify.ID) (*spotify.FullArtist, error) {
+	if m.GetArtistFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetArtistFunc(id)
+}
+
+func (m *Catalog) GetArtists(ids []spotify.ID) ([]*spotify.FullArtist, error) {
+	if m.GetArtistsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetArtistsFunc(ids)
+}
+
+func (m *Catalog) GetArtistAlbums(id spotify.ID, limit, offset int) (*spotify.Paging, error) {
+	if m.GetArtistAlbumsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetArtistAlbumsFunc(id, limit, offset)
+}
+
+func (m *Catalog) GetArtistTopTracks(id spotify.ID, market string) ([]*spotify.FullTrack, error) {
+	if m.GetArtistTopTracksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetArtistTopTracksFunc(id, market)
+}
+
+func (m *Catalog) GetRelatedArtists(id spotify.ID) ([]*spotify.FullArtist, error) {
+	if m.GetRelatedArtistsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetRelatedArtistsFunc(id)
+}
+
+func (m *Catalog) GetTrack(id spotify.ID, market string) (*spotify.FullTrack, error) {
+	if m.GetTrackFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetTrackFunc(id, market)
+}
+
+func (m *Catalog) GetTracks(ids []spotify.ID, market string) ([]*spotify.FullTrack, error) {
+	if m.GetTracksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetTracksFunc(ids, market)
+}
+
+func (m *Catalog) GetAudioAnalysis(id spotify.ID) (*spotify.AudioAnalysis, error) {
+	if m.GetAudioAnalysisFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAudioAnalysisFunc(id)
+}
+
+func (m *Catalog) GetAudioFeature(id spotify.ID) (*spotify.AudioFeatures, error) {
+	if m.GetAudioFeatureFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAudioFeatureFunc(id)
+}
+
+func (m *Catalog) GetAudioFeatures(ids []spotify.ID) ([]*spotify.AudioFeatures, error) {
+	if m.GetAudioFeaturesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAudioFeaturesFunc(ids)
+}
+
+func (m *Catalog) GetShow(id spotify.ID, market string) (*spotify.Show, error) {
+	if m.GetShowFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetShowFunc(id, market)
+}
+
+func (m *Catalog) GetShows(ids []spotify.ID, market string) ([]*spotify.Show, error) {
+	if m.GetShowsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetShowsFunc(ids, market)
+}
+
+func (m *Catalog) GetShowEpisodes(id spotify.ID, market string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetShowEpisodesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetShowEpisodesFunc(id, market, limit, offset)
+}
+
+func (m *Catalog) GetEpisode(id spotify.ID, market string) (*spotify.Episode, error) {
+	if m.GetEpisodeFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetEpisodeFunc(id, market)
+}
+
+func (m *Catalog) GetEpisodes(ids []spotify.ID, market string) ([]*spotify.Episode, error) {
+	if m.GetEpisodesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetEpisodesFunc(ids, market)
+}
+
+func (m *Catalog) GetAudiobook(id spotify.ID, market string) (*spotify.Audiobook, error) {
+	if m.GetAudiobookFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAudiobookFunc(id, market)
+}
+
+func (m *Catalog) GetAudiobooks(ids []spotify.ID, market string) ([]*spotify.Audiobook, error) {
+	if m.GetAudiobooksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAudiobooksFunc(ids, market)
+}
+
+func (m *Catalog) GetAudiobookChapters(id spotify.ID, market string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetAudiobookChaptersFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAudiobookChaptersFunc(id, market, limit, offset)
+}
+
+func (m *Catalog) GetChapter(id spotify.ID, market string) (*spotify.Chapter, error) {
+	if m.GetChapterFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetChapterFunc(id, market)
+}
+
+func (m *Catalog) GetChapters(ids []spotify.ID, market string) ([]*spotify.Chapter, error) {
+	if m.GetChaptersFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetChaptersFunc(ids, market)
+}
+
+// Searcher is a spotify.Searcher whose methods call its functions
+type Searcher struct {
+	SearchFunc       func(ctx context.Context, query string, types []spotify.SearchType, opts *spotify.SearchOptions) (*spotify.SearchResult, error)
+	SearchTrackFunc  func(query string, offset int) ([]*spotify.FullTrack, error)
+	SearchAlbumFunc  func(query string, offset int) ([]*spotify.SimpleAlbum, error)
+	SearchArtistFunc func(query string, offset int) ([]*spotify.FullArtist, error)
+	TrackByISRCFunc  func(ctx context.Context, isrc string) ([]*spotify.FullTrack, error)
+	AlbumByUPCFunc   func(ctx context.Context, upc string) ([]*spotify.FullAlbum, error)
+	TracksByISRCFunc func(ctx context.Context, isrcs []string) (*spotify.ISRCResult, error)
+	AlbumsByUPCFunc  func(ctx context.Context, upcs []string) (*spotify.UPCResult, error)
+}
+
+func (m *Searcher) Search(ctx context.Context, query string, types []spotify.SearchType, opts *spotify.SearchOptions) (*spotify.SearchResult, error) {
+	if m.SearchFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.SearchFunc(ctx, query, types, opts)
+}
+
+func (m *Searcher) SearchTrack(query string, offset int) ([]*spotify.FullTrack, error) {
+	if m.SearchTrackFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.SearchTrackFunc(query, offset)
+}
+
+func (m *Searcher) SearchAlbum(query string, offset int) ([]*spotify.SimpleAlbum, error) {
+	if m.SearchAlbumFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.SearchAlbumFunc(query, offset)
+}
+
+func (m *Searcher) SearchArtist(query string, offset int) ([]*spotify.FullArtist, error) {
+	if m.SearchArtistFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.SearchArtistFunc(query, offset)
+}
+
+func (m *Searcher) TrackByISRC(ctx context.Context, isrc string) ([]*spotify.FullTrack, error) {
+	if m.TrackByISRCFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.TrackByISRCFunc(ctx, isrc)
+}
+
+func (m *Searcher) AlbumByUPC(ctx context.Context, upc string) ([]*spotify.FullAlbum, error) {
+	if m.AlbumByUPCFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AlbumByUPCFunc(ctx, upc)
+}
+
+func (m *Searcher) TracksByISRC(ctx context.Context, isrcs []string) (*spotify.ISRCResult, error) {
+	if m.TracksByISRCFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.TracksByISRCFunc(ctx, isrcs)
+}
+
+func (m *Searcher) AlbumsByUPC(ctx context.Context, upcs []string) (*spotify.UPCResult, error) {
+	if m.AlbumsByUPCFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AlbumsByUPCFunc(ctx, upcs)
+}
+
+// Library is a spotify.Library whose methods call its functions
+type Library struct {
+	GetCurrentUserFunc        func() (*spotify.User, error)
+	GetSavedTracksFunc        func(market string, limit, offset int) (*spotify.Paging, error)
+	AllSavedTracksFunc        func(market string) ([]*spotify.SavedTrack, error)
+	SaveTracksFunc            func(ids []spotify.ID) error
+	RemoveSavedTracksFunc     func(ids []spotify.ID) error
+	HasTracksSavedFunc        func(ids []spotify.ID) ([]bool, error)
+	GetSavedAlbumsFunc        func(market string, limit, offset int) (*spotify.Paging, error)
+	AllSavedAlbumsFunc        func(market string) ([]*spotify.SavedAlbum, error)
+	SaveAlbumsFunc            func(ids []spotify.ID) error
+	RemoveSavedAlbumsFunc     func(ids []spotify.ID) error
+	HasAlbumsSavedFunc        func(ids []spotify.ID) ([]bool, error)
+	GetSavedShowsFunc         func(limit, offset int) (*spotify.Paging, error)
+	AllSavedShowsFunc         func() ([]*spotify.SavedShow, error)
+	SaveShowsFunc             func(ids []spotify.ID) error
+	RemoveSavedShowsFunc      func(ids []spotify.ID) error
+	HasShowsSavedFunc         func(ids []spotify.ID) ([]bool, error)
+	GetSavedEpisodesFunc      func(market string, limit, offset int) (*spotify.Paging, error)
+	SaveEpisodesFunc          func(ids []spotify.ID) error
+	RemoveSavedEpisodesFunc   func(ids []spotify.ID) error
+	HasEpisodesSavedFunc      func(ids []spotify.ID) ([]bool, error)
+	GetSavedAudiobooksFunc    func(limit, offset int) (*spotify.Paging, error)
+	SaveAudiobooksFunc        func(ids []spotify.ID) error
+	RemoveSavedAudiobooksFunc func(ids []spotify.ID) error
+	HasAudiobooksSavedFunc    func(ids []spotify.ID) ([]bool, error)
+	GetFollowedArtistsFunc    func(limit int, after string) (*spotify.ArtistCursorPage, error)
+	AllFollowedArtistsFunc    func() ([]*spotify.FullArtist, error)
+	FollowArtistsFunc         func(ids []spotify.ID) error
+	UnfollowArtistsFunc       func(ids []spotify.ID) error
+}
+
+func (m *Library) GetCurrentUser() (*spotify.User, error) {
+	if m.GetCurrentUserFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCurrentUserFunc()
+}
+
+func (m *Library) GetSavedTracks(market string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetSavedTracksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetSavedTracksFunc(market, limit, offset)
+}
+
+func (m *Library) AllSavedTracks(market string) ([]*spotify.SavedTrack, error) {
+	if m.AllSavedTracksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AllSavedTracksFunc(market)
+}
+
+func (m *Library) SaveTracks(ids []spotify.ID) error {
+	if m.SaveTracksFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SaveTracksFunc(ids)
+}
+
+func (m *Library) RemoveSavedTracks(ids []spotify.ID) error {
+	if m.RemoveSavedTracksFunc == nil {
+		return ErrNotMocked
+	}
+	return m.RemoveSavedTracksFunc(ids)
+}
+
+func (m *Library) HasTracksSaved(ids []spotify.ID) ([]bool, error) {
+	if m.HasTracksSavedFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.HasTracksSavedFunc(ids)
+}
+
+func (m *Library) GetSavedAlbums(market string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetSavedAlbumsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetSavedAlbumsFunc(market, limit, offset)
+}
+
+func (m *Library) AllSavedAlbums(market string) ([]*spotify.SavedAlbum, error) {
+	if m.AllSavedAlbumsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AllSavedAlbumsFunc(market)
+}
+
+func (m *Library) SaveAlbums(ids []spotify.ID) error {
+	if m.SaveAlbumsFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SaveAlbumsFunc(ids)
+}
+
+func (m *Library) RemoveSavedAlbums(ids []spotify.ID) error {
+	if m.RemoveSavedAlbumsFunc == nil {
+		return ErrNotMocked
+	}
+	return m.RemoveSavedAlbumsFunc(ids)
+}
+
+func (m *Library) HasAlbumsSaved(ids []spotify.ID) ([]bool, error) {
+	if m.HasAlbumsSavedFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.HasAlbumsSavedFunc(ids)
+}
+
+func (m *Library) GetSavedShows(limit, offset int) (*spotify.Paging, error) {
+	if m.GetSavedShowsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetSavedShowsFunc(limit, offset)
+}
+
+func (m *Library) AllSavedShows() ([]*spotify.SavedShow, error) {
+	if m.AllSavedShowsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AllSavedShowsFunc()
+}
+
+func (m *Library) SaveShows(ids []spotify.ID) error {
+	if m.SaveShowsFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SaveShowsFunc(ids)
+}
+
+func (m *Library) RemoveSavedShows(ids []spotify.ID) error {
+	if m.RemoveSavedShowsFunc == nil {
+		return ErrNotMocked
+	}
+	return m.RemoveSavedShowsFunc(ids)
+}
+
+func (m *Library) HasShowsSaved(ids []spotify.ID) ([]bool, error) {
+	if m.HasShowsSavedFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.HasShowsSavedFunc(ids)
+}
+
+func (m *Library) GetSavedEpisodes(market string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetSavedEpisodesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetSavedEpisodesFunc(market, limit, offset)
+}
+
+func (m *Library) SaveEpisodes(ids []spotify.ID) error {
+	if m.SaveEpisodesFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SaveEpisodesFunc(ids)
+}
+
+func (m *Library) RemoveSavedEpisodes(ids []spotify.ID) error {
+	if m.RemoveSavedEpisodesFunc == nil {
+		return ErrNotMocked
+	}
+	return m.RemoveSavedEpisodesFunc(ids)
+}
+
+func (m *Library) HasEpisodesSaved(ids []spotify.ID) ([]bool, error) {
+	if m.HasEpisodesSavedFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.HasEpisodesSavedFunc(ids)
+}
+
+func (m *Library) GetSavedAudiobooks(limit, offset int) (*spotify.Paging, error) {
+	if m.GetSavedAudiobooksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetSavedAudiobooksFunc(limit, offset)
+}
+
+func (m *Library) SaveAudiobooks(ids []spotify.ID) error {
+	if m.SaveAudiobooksFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SaveAudiobooksFunc(ids)
+}
+
+func (m *Library) RemoveSavedAudiobooks(ids []spotify.ID) error {
+	if m.RemoveSavedAudiobooksFunc == nil {
+		return ErrNotMocked
+	}
+	return m.RemoveSavedAudiobooksFunc(ids)
+}
+
+func (m *Library) HasAudiobooksSaved(ids []spotify.ID) ([]bool, error) {
+	if m.HasAudiobooksSavedFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.HasAudiobooksSavedFunc(ids)
+}
+
+func (m *Library) GetFollowedArtists(limit int, after string) (*spotify.ArtistCursorPage, error) {
+	if m.GetFollowedArtistsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFollowedArtistsFunc(limit, after)
+}
+
+func (m *Library) AllFollowedArtists() ([]*spotify.FullArtist, error) {
+	if m.AllFollowedArtistsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AllFollowedArtistsFunc()
+}
+
+func (m *Library) FollowArtists(ids []spotify.ID) error {
+	if m.FollowArtistsFunc == nil {
+		return ErrNotMocked
+	}
+	return m.FollowArtistsFunc(ids)
+}
+
+func (m *Library) UnfollowArtists(ids []spotify.ID) error {
+	if m.UnfollowArtistsFunc == nil {
+		return ErrNotMocked
+	}
+	return m.UnfollowArtistsFunc(ids)
+}
+
+// Playlists is a spotify.Playlists whose methods call its functions
+type Playlists struct {
+	GetPlaylistFunc                func(uid, pid spotify.ID) (*spotify.FullPlaylist, error)
+	CreatePlaylistFunc             func(uid spotify.ID, name, description string, public bool) (*spotify.FullPlaylist, error)
+	GetPlaylistTracksFunc          func(uid, pid spotify.ID, limit, offset int) (*spotify.Paging, error)
+	GetPlaylistTracksForMarketFunc func(uid, pid spotify.ID, market string, limit, offset int) (*spotify.Paging, error)
+	AllPlaylistTracksFunc          func(uid, pid spotify.ID) ([]*spotify.PlaylistTrack, error)
+	AllPlaylistTracksForMarketFunc func(uid, pid spotify.ID, market string) ([]*spotify.PlaylistTrack, error)
+	AddTracksToPlaylistFunc        func(uid, pid spotify.ID, uris []spotify.URI, position int) (string, error)
+	ReplacePlaylistTracksFunc      func(uid, pid spotify.ID, uris []spotify.URI) (string, error)
+	RemovePlaylistTracksFunc       func(uid, pid spotify.ID, tracks []spotify.TrackPositions, snapshotID string) (string, error)
+	ReorderPlaylistTracksFunc      func(uid, pid spotify.ID, rangeStart, insertBefore, rangeLength int, snapshotID string) (string, error)
+	GetMyPlaylistsFunc             func(limit, offset int) (*spotify.Paging, error)
+	AllMyPlaylistsFunc             func() ([]*spotify.SimplePlaylist, error)
+	UserFollowPlaylistFunc         func(oid, pid spotify.ID) error
+	UserUnfollowPlaylistFunc       func(oid, pid spotify.ID) error
+	UsersFollowsPlaylistFunc       func(oid, pid spotify.ID, uid []spotify.ID) ([]bool, error)
+}
+
+func (m *Playlists) GetPlaylist(uid, pid spotify.ID) (*spotify.FullPlaylist, error) {
+	if m.GetPlaylistFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetPlaylistFunc(uid, pid)
+}
+
+func (m *Playlists) CreatePlaylist(uid spotify.ID, name, description string, public bool) (*spotify.FullPlaylist, error) {
+	if m.CreatePlaylistFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CreatePlaylistFunc(uid, name, description, public)
+}
+
+func (m *Playlists) GetPlaylistTracks(uid, pid spotify.ID, limit, offset int) (*spotify.Paging, error) {
+	if m.GetPlaylistTracksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetPlaylistTracksFunc(uid, pid, limit, offset)
+}
+
+func (m *Playlists) GetPlaylistTracksForMarket(uid, pid spotify.ID, market string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetPlaylistTracksForMarketFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetPlaylistTracksForMarketFunc(uid, pid, market, limit, offset)
+}
+
+func (m *Playlists) AllPlaylistTracks(uid, pid spotify.ID) ([]*spotify.PlaylistTrack, error) {
+	if m.AllPlaylistTracksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AllPlaylistTracksFunc(uid, pid)
+}
+
+func (m *Playlists) AllPlaylistTracksForMarket(uid, pid spotify.ID, market string) ([]*spotify.PlaylistTrack, error) {
+	if m.AllPlaylistTracksForMarketFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AllPlaylistTracksForMarketFunc(uid, pid, market)
+}
+
+func (m *Playlists) AddTracksToPlaylist(uid, pid spotify.ID, uris []spotify.URI, position int) (string, error) {
+	if m.AddTracksToPlaylistFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.AddTracksToPlaylistFunc(uid, pid, uris, position)
+}
+
+func (m *Playlists) ReplacePlaylistTracks(uid, pid spotify.ID, uris []spotify.URI) (string, error) {
+	if m.ReplacePlaylistTracksFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.ReplacePlaylistTracksFunc(uid, pid, uris)
+}
+
+func (m *Playlists) RemovePlaylistTracks(uid, pid spotify.ID, tracks []spotify.TrackPositions, snapshotID string) (string, error) {
+	if m.RemovePlaylistTracksFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.RemovePlaylistTracksFunc(uid, pid, tracks, snapshotID)
+}
+
+func (m *Playlists) ReorderPlaylistTracks(uid, pid spotify.ID, rangeStart, insertBefore, rangeLength int, snapshotID string) (string, error) {
+	if m.ReorderPlaylistTracksFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.ReorderPlaylistTracksFunc(uid, pid, rangeStart, insertBefore, rangeLength, snapshotID)
+}
+
+func (m *Playlists) GetMyPlaylists(limit, offset int) (*spotify.Paging, error) {
+	if m.GetMyPlaylistsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetMyPlaylistsFunc(limit, offset)
+}
+
+func (m *Playlists) AllMyPlaylists() ([]*spotify.SimplePlaylist, error) {
+	if m.AllMyPlaylistsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AllMyPlaylistsFunc()
+}
+
+func (m *Playlists) UserFollowPlaylist(oid, pid spotify.ID) error {
+	if m.UserFollowPlaylistFunc == nil {
+		return ErrNotMocked
+	}
+	return m.UserFollowPlaylistFunc(oid, pid)
+}
+
+func (m *Playlists) UserUnfollowPlaylist(oid, pid spotify.ID) error {
+	if m.UserUnfollowPlaylistFunc == nil {
+		return ErrNotMocked
+	}
+	return m.UserUnfollowPlaylistFunc(oid, pid)
+}
+
+func (m *Playlists) UsersFollowsPlaylist(oid, pid spotify.ID, uid []spotify.ID) ([]bool, error) {
+	if m.UsersFollowsPlaylistFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.UsersFollowsPlaylistFunc(oid, pid, uid)
+}
+
+// Player is a spotify.Player whose methods call its functions
+type Player struct {
+	GetPlaybackStateFunc    func(market string) (*spotify.PlaybackState, error)
+	GetCurrentlyPlayingFunc func(market string) (*spotify.CurrentlyPlaying, error)
+	GetDevicesFunc          func() ([]*spotify.Device, error)
+	TransferPlaybackFunc    func(deviceID spotify.ID, play bool) error
+	PlayFunc                func(deviceID spotify.ID, opts *spotify.PlayOptions) error
+	PauseFunc               func(deviceID spotify.ID) error
+	NextFunc                func(deviceID spotify.ID) error
+	PreviousFunc            func(deviceID spotify.ID) error
+	SeekFunc                func(deviceID spotify.ID, positionMs int) error
+	SetRepeatFunc           func(deviceID spotify.ID, state string) error
+	SetVolumeFunc           func(deviceID spotify.ID, percent int) error
+	SetShuffleFunc          func(deviceID spotify.ID, shuffle bool) error
+	GetQueueFunc            func() (*spotify.Queue, error)
+	AddToQueueFunc          func(deviceID spotify.ID, uri spotify.URI) error
+	GetRecentlyPlayedFunc   func(limit int, before string) (*spotify.PlayHistoryCursorPage, error)
+}
+
+func (m *Player) GetPlaybackState(market string) (*spotify.PlaybackState, error) {
+	if m.GetPlaybackStateFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetPlaybackStateFunc(market)
+}
+
+func (m *Player) GetCurrentlyPlaying(market string) (*spotify.CurrentlyPlaying, error) {
+	if m.GetCurrentlyPlayingFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCurrentlyPlayingFunc(market)
+}
+
+func (m *Player) GetDevices() ([]*spotify.Device, error) {
+	if m.GetDevicesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetDevicesFunc()
+}
+
+func (m *Player) TransferPlayback(deviceID spotify.ID, play bool) error {
+	if m.TransferPlaybackFunc == nil {
+		return ErrNotMocked
+	}
+	return m.TransferPlaybackFunc(deviceID, play)
+}
+
+func (m *Player) Play(deviceID spotify.ID, opts *spotify.PlayOptions) error {
+	if m.PlayFunc == nil {
+		return ErrNotMocked
+	}
+	return m.PlayFunc(deviceID, opts)
+}
+
+func (m *Player) Pause(deviceID spotify.ID) error {
+	if m.PauseFunc == nil {
+		return ErrNotMocked
+	}
+	return m.PauseFunc(deviceID)
+}
+
+func (m *Player) Next(deviceID spotify.ID) error {
+	if m.NextFunc == nil {
+		return ErrNotMocked
+	}
+	return m.NextFunc(deviceID)
+}
+
+func (m *Player) Previous(deviceID spotify.ID) error {
+	if m.PreviousFunc == nil {
+		return ErrNotMocked
+	}
+	return m.PreviousFunc(deviceID)
+}
+
+func (m *Player) Seek(deviceID spotify.ID, positionMs int) error {
+	if m.SeekFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SeekFunc(deviceID, positionMs)
+}
+
+func (m *Player) SetRepeat(deviceID spotify.ID, state string) error {
+	if m.SetRepeatFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetRepeatFunc(deviceID, state)
+}
+
+func (m *Player) SetVolume(deviceID spotify.ID, percent int) error {
+	if m.SetVolumeFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetVolumeFunc(deviceID, percent)
+}
+
+func (m *Player) SetShuffle(deviceID spotify.ID, shuffle bool) error {
+	if m.SetShuffleFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetShuffleFunc(deviceID, shuffle)
+}
+
+func (m *Player) GetQueue() (*spotify.Queue, error) {
+	if m.GetQueueFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetQueueFunc()
+}
+
+func (m *Player) AddToQueue(deviceID spotify.ID, uri spotify.URI) error {
+	if m.AddToQueueFunc == nil {
+		return ErrNotMocked
+	}
+	return m.AddToQueueFunc(deviceID, uri)
+}
+
+func (m *Player) GetRecentlyPlayed(limit int, before string) (*spotify.PlayHistoryCursorPage, error) {
+	if m.GetRecentlyPlayedFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetRecentlyPlayedFunc(limit, before)
+}
+
+// Browser is a spotify.Browser whose methods call its functions
+type Browser struct {
+	GetFeaturedPlaylistsFunc          func(locale, country, timestamp string, limit, offset int) (*spotify.Paging, error)
+	GetNewReleasesFunc                func(country string, limit, offset int) (*spotify.Paging, error)
+	GetCategoriesFunc                 func(country, locale string, offset, limit int) (*spotify.Paging, error)
+	GetCategoryFunc                   func(name, country, locale string) (*spotify.Category, error)
+	GetCategoryPlaylistsFunc          func(name, country string, limit, offset int) (*spotify.Paging, error)
+	GetRecommendationsFunc            func(args ...string) (*spotify.Recommendations, error)
+	GetRecommendationsWithOptionsFunc func(opts *spotify.RecommendationOptions) (*spotify.Recommendations, error)
+	AvailableMarketsFunc              func() ([]string, error)
+	AvailableGenreSeedsFunc           func() ([]string, error)
+}
+
+func (m *Browser) GetFeaturedPlaylists(locale, country, timestamp string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetFeaturedPlaylistsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetFeaturedPlaylistsFunc(locale, country, timestamp, limit, offset)
+}
+
+func (m *Browser) GetNewReleases(country string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetNewReleasesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetNewReleasesFunc(country, limit, offset)
+}
+
+func (m *Browser) GetCategories(country, locale string, offset, limit int) (*spotify.Paging, error) {
+	if m.GetCategoriesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCategoriesFunc(country, locale, offset, limit)
+}
+
+func (m *Browser) GetCategory(name, country, locale string) (*spotify.Category, error) {
+	if m.GetCategoryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCategoryFunc(name, country, locale)
+}
+
+func (m *Browser) GetCategoryPlaylists(name, country string, limit, offset int) (*spotify.Paging, error) {
+	if m.GetCategoryPlaylistsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetCategoryPlaylistsFunc(name, country, limit, offset)
+}
+
+func (m *Browser) GetRecommendations(args ...string) (*spotify.Recommendations, error) {
+	if m.GetRecommendationsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetRecommendationsFunc(args...)
+}
+
+func (m *Browser) GetRecommendationsWithOptions(opts *spotify.RecommendationOptions) (*spotify.Recommendations, error) {
+	if m.GetRecommendationsWithOptionsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetRecommendationsWithOptionsFunc(opts)
+}
+
+func (m *Browser) AvailableMarkets() ([]string, error) {
+	if m.AvailableMarketsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AvailableMarketsFunc()
+}
+
+func (m *Browser) AvailableGenreSeeds() ([]string, error) {
+	if m.AvailableGenreSeedsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AvailableGenreSeedsFunc()
+}
+
+// API is a spotify.API made of the mocks of the smaller interfaces
+type API struct {
+	Catalog
+	Searcher
+	Library
+	Playlists
+	Player
+	Browser
+}
+
+var (
+	_ spotify.Catalog   = (*Catalog)(nil)
+	_ spotify.Searcher  = (*Searcher)(nil)
+	_ spotify.Library   = (*Library)(nil)
+	_ spotify.Playlists = (*Playlists)(nil)
+	_ spotify.Player    = (*Player)(nil)
+	_ spotify.Browser   = (*Browser)(nil)
+	_ spotify.API       = (*API)(nil)
+)
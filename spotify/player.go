@@ -0,0 +1,254 @@
+package spotify
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// The player commands act on a device of the user, pass "" for the active one
+// They need a premium user and fail with a 404 SpotifyError when there is no device to act on.
+
+// PlayOptions is what Play starts, either a context or a list of URIs
+type PlayOptions struct {
+	// ContextURI is an album, artist, playlist or show to play
+	ContextURI URI `json:"context_uri,omitempty"`
+	// URIs are the tracks or episodes to play
+	URIs []URI `json:"uris,omitempty"`
+	// Offset is where in the context or URIs to start
+	Offset *PlayOffset `json:"offset,omitempty"`
+	// PositionMs is where in the first item to start
+	PositionMs int `json:"position_ms,omitempty"`
+}
+
+// PlayOffset is the position or the URI of the item to start with
+type PlayOffset struct {
+	Position int `json:"position,omitempty"`
+	URI      URI `json:"uri,omitempty"`
+}
+
+// deviceValues returns the query values for an optional device
+func deviceValues(deviceID ID) url.Values {
+	vals := url.Values{}
+	if deviceID != "" {
+		vals.Add("device_id", string(deviceID))
+	}
+	return vals
+}
+
+// command sends a player command, v is encoded as the body unless it is nil
+func (c *Client) command(method, endpoint string, v interface{}) error {
+	var body io.Reader
+	if v != nil {
+		b, err := jsonBody(v)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+
+	res, err := c.request(method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// GetPlaybackState gets the playback on the user's active device, nil if there is none
+// Market: optional ISO 3166-1 alpha-2 country code, used to relink the playing track
+func (c *Client) GetPlaybackState(market string) (*PlaybackState, error) {
+	vals := marketValues(market)
+	vals.Add("additional_types", "track,episode")
+
+	res, err := c.request("GET", withValues(EndpointGetPlaybackState(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNoContent {
+		return nil, discard(res)
+	}
+
+	state := &PlaybackState{}
+	err = unmarshal(res, state)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// GetCurrentlyPlaying gets what plays on the user's active device, nil if nothing does
+// Market: optional ISO 3166-1 alpha-2 country code, used to relink the playing track
+func (c *Client) GetCurrentlyPlaying(market string) (*CurrentlyPlaying, error) {
+	vals := marketValues(market)
+	vals.Add("additional_types", "track,episode")
+
+	res, err := c.request("GET", withValues(EndpointGetCurrentlyPlaying(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNoContent {
+		return nil, discard(res)
+	}
+
+	playing := &CurrentlyPlaying{}
+	err = unmarshal(res, playing)
+	if err != nil {
+		return nil, err
+	}
+	return playing, nil
+}
+
+// GetDevices gets the devices of the user
+func (c *Client) GetDevices() ([]*Device, error) {
+	res, err := c.request("GET", EndpointGetDevices(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Devices []*Device `json:"devices"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Devices, nil
+}
+
+// TransferPlayback moves the playback to a device, play starts it there, otherwise it keeps its state
+func (c *Client) TransferPlayback(deviceID ID, play bool) error {
+	if deviceID == "" {
+		return fmt.Errorf("Missing required parameter: deviceID")
+	}
+
+	body := struct {
+		DeviceIDs []ID `json:"device_ids"`
+		Play      bool `json:"play"`
+	}{[]ID{deviceID}, play}
+	return c.command("PUT", EndpointTransferPlayback(), &body)
+}
+
+// Play starts the playback of the options on a device, nil options resume the current playback
+func (c *Client) Play(deviceID ID, opts *PlayOptions) error {
+	var body interface{}
+	if opts != nil {
+		body = opts
+	}
+	return c.command("PUT", withValues(EndpointPlay(), deviceValues(deviceID)), body)
+}
+
+// Pause pauses the playback on a device
+func (c *Client) Pause(deviceID ID) error {
+	return c.command("PUT", withValues(EndpointPause(), deviceValues(deviceID)), nil)
+}
+
+// Next skips to the next item on a device
+func (c *Client) Next(deviceID ID) error {
+	return c.command("POST", withValues(EndpointNext(), deviceValues(deviceID)), nil)
+}
+
+// Previous skips to the previous item on a device
+func (c *Client) Previous(deviceID ID) error {
+	return c.command("POST", withValues(EndpointPrevious(), deviceValues(deviceID)), nil)
+}
+
+// Seek moves to a position in the playing item, in milliseconds
+func (c *Client) Seek(deviceID ID, positionMs int) error {
+	if positionMs < 0 {
+		return fmt.Errorf("Invalid position: %d", positionMs)
+	}
+
+	vals := deviceValues(deviceID)
+	vals.Add("position_ms", strconv.Itoa(positionMs))
+	return c.command("PUT", withValues(EndpointSeek(), vals), nil)
+}
+
+// SetRepeat sets the repeat mode of a device
+// State: "track", "context" or "off"
+func (c *Client) SetRepeat(deviceID ID, state string) error {
+	switch state {
+	case "track", "context", "off":
+	default:
+		return fmt.Errorf("Invalid repeat state: %s", state)
+	}
+
+	vals := deviceValues(deviceID)
+	vals.Add("state", state)
+	return c.command("PUT", withValues(EndpointSetRepeat(), vals), nil)
+}
+
+// SetVolume sets the volume of a device, in percent
+func (c *Client) SetVolume(deviceID ID, percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("Invalid volume: %d", percent)
+	}
+
+	vals := deviceValues(deviceID)
+	vals.Add("volume_percent", strconv.Itoa(percent))
+	return c.command("PUT", withValues(EndpointSetVolume(), vals), nil)
+}
+
+// SetShuffle turns the shuffle of a device on or off
+func (c *Client) SetShuffle(deviceID ID, shuffle bool) error {
+	vals := deviceValues(deviceID)
+	vals.Add("state", strconv.FormatBool(shuffle))
+	return c.command("PUT", withValues(EndpointSetShuffle(), vals), nil)
+}
+
+// GetQueue gets the item playing and the items that play after it
+func (c *Client) GetQueue() (*Queue, error) {
+	res, err := c.request("GET", EndpointGetQueue(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &Queue{}
+	err = unmarshal(res, queue)
+	if err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// AddToQueue adds a track or an episode to the end of the queue of a device
+func (c *Client) AddToQueue(deviceID ID, uri URI) error {
+	switch uri.Kind() {
+	case KindTrack, KindEpisode:
+	default:
+		return fmt.Errorf("Invalid queue item: %s", uri)
+	}
+
+	vals := deviceValues(deviceID)
+	vals.Add("uri", string(uri))
+	return c.command("POST", withValues(EndpointAddToQueue(), vals), nil)
+}
+
+// GetRecentlyPlayed gets a page of the tracks the user played, newest first
+// The page is cursor based, pass the Before cursor of a page to get the older one, "" for the first.
+func (c *Client) GetRecentlyPlayed(limit int, before string) (*PlayHistoryCursorPage, error) {
+	vals := url.Values{}
+	if limit > 0 && limit <= 50 {
+		vals.Add("limit", strconv.Itoa(limit))
+	}
+
+	if before != "" {
+		vals.Add("before", before)
+	}
+
+	res, err := c.request("GET", withValues(EndpointGetRecentlyPlayed(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &PlayHistoryCursorPage{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
@@ -0,0 +1,209 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// MaxPlaylistItems is the most items a single add or replace request can have
+const MaxPlaylistItems = 100
+
+// GetPlaylist gets a playlist with the first page of its tracks
+func (c *Client) GetPlaylist(uid, pid ID) (*FullPlaylist, error) {
+	res, err := c.request("GET", EndpointGetUserPlaylist(string(uid), string(pid)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist := &FullPlaylist{}
+	err = unmarshal(res, playlist)
+	if err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// CreatePlaylist creates an empty playlist for the user
+func (c *Client) CreatePlaylist(uid ID, name, description string, public bool) (*FullPlaylist, error) {
+	if name == "" {
+		return nil, fmt.Errorf("Missing required parameter: name")
+	}
+
+	body, err := jsonBody(struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Public      bool   `json:"public"`
+	}{name, description, public})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.request("POST", EndpointCreatePlaylist(string(uid)), body)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist := &FullPlaylist{}
+	err = unmarshal(res, playlist)
+	if err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// GetPlaylistTracks gets a page of the tracks of a playlist
+// The items of the page are PlaylistTracks, limit is at most 100
+func (c *Client) GetPlaylistTracks(uid, pid ID, limit, offset int) (*Paging, error) {
+	return c.GetPlaylistTracksForMarket(uid, pid, "", limit, offset)
+}
+
+// GetPlaylistTracksForMarket is GetPlaylistTracks with the tracks relinked for a market
+// Relinked tracks have LinkedFrom set to the track that is in the playlist, market may be "from_token".
+func (c *Client) GetPlaylistTracksForMarket(uid, pid ID, market string, limit, offset int) (*Paging, error) {
+	if limit < 1 || limit > MaxPlaylistItems {
+		limit = MaxPlaylistItems
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	vals := url.Values{}
+	if market != "" {
+		vals.Add("market", market)
+	}
+	vals.Add("limit", strconv.Itoa(limit))
+	vals.Add("offset", strconv.Itoa(offset))
+
+	res, err := c.request("GET", withValues(EndpointGetPlaylistTracks(string(uid), string(pid)), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// AllPlaylistTracks gets all the tracks of a playlist, following the paging
+func (c *Client) AllPlaylistTracks(uid, pid ID) ([]*PlaylistTrack, error) {
+	return c.AllPlaylistTracksForMarket(uid, pid, "")
+}
+
+// AllPlaylistTracksForMarket is AllPlaylistTracks with the tracks relinked for a market
+func (c *Client) AllPlaylistTracksForMarket(uid, pid ID, market string) ([]*PlaylistTrack, error) {
+	var tracks []*PlaylistTrack
+	for offset := 0; ; {
+		page, err := c.GetPlaylistTracksForMarket(uid, pid, market, MaxPlaylistItems, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*PlaylistTrack
+		if err = json.Unmarshal(page.Items, &items); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, items...)
+
+		offset += len(items)
+		if page.Next == "" || len(items) == 0 {
+			return tracks, nil
+		}
+	}
+}
+
+// snapshot is the response of the requests that modify a playlist's tracks
+type snapshot struct {
+	SnapshotID string `json:"snapshot_id"`
+}
+
+// AddTracksToPlaylist adds at most MaxPlaylistItems tracks or episodes to a playlist
+// Position is the index to insert them at, or -1 to append them.
+// The snapshot ID of the new version of the playlist is returned.
+func (c *Client) AddTracksToPlaylist(uid, pid ID, uris []URI, position int) (string, error) {
+	if len(uris) > MaxPlaylistItems {
+		return "", fmt.Errorf("Too many items: %d given, at most %d allowed", len(uris), MaxPlaylistItems)
+	}
+
+	req := struct {
+		URIs     []URI `json:"uris"`
+		Position *int  `json:"position,omitempty"`
+	}{URIs: uris}
+	if position >= 0 {
+		req.Position = &position
+	}
+	return c.modifyPlaylist("POST", EndpointAddTracksToPlaylist(string(uid), string(pid)), req)
+}
+
+// ReplacePlaylistTracks replaces all the items of a playlist with at most MaxPlaylistItems tracks or episodes
+func (c *Client) ReplacePlaylistTracks(uid, pid ID, uris []URI) (string, error) {
+	if len(uris) > MaxPlaylistItems {
+		return "", fmt.Errorf("Too many items: %d given, at most %d allowed", len(uris), MaxPlaylistItems)
+	}
+
+	if uris == nil {
+		uris = []URI{}
+	}
+
+	req := struct {
+		URIs []URI `json:"uris"`
+	}{URIs: uris}
+	return c.modifyPlaylist("PUT", EndpointReplaceTracksInPlaylist(string(uid), string(pid)), req)
+}
+
+// TrackPositions is an item to remove from a playlist
+// Positions are the indexes of the occurrences to remove, all occurrences are removed when it's empty.
+type TrackPositions struct {
+	URI       URI   `json:"uri"`
+	Positions []int `json:"positions,omitempty"`
+}
+
+// RemovePlaylistTracks removes at most MaxPlaylistItems tracks or episodes from a playlist
+// The snapshotID is optional, positions are checked against that version of the playlist when it's given.
+func (c *Client) RemovePlaylistTracks(uid, pid ID, tracks []TrackPositions, snapshotID string) (string, error) {
+	if len(tracks) > MaxPlaylistItems {
+		return "", fmt.Errorf("Too many items: %d given, at most %d allowed", len(tracks), MaxPlaylistItems)
+	}
+
+	req := struct {
+		Tracks     []TrackPositions `json:"tracks"`
+		SnapshotID string           `json:"snapshot_id,omitempty"`
+	}{tracks, snapshotID}
+	return c.modifyPlaylist("DELETE", EndpointDeleteTracksFromPlaylist(string(uid), string(pid)), req)
+}
+
+// ReorderPlaylistTracks moves the rangeLength items starting at rangeStart to before insertBefore
+// The snapshotID is optional, when given the change is made against that version of the playlist.
+func (c *Client) ReorderPlaylistTracks(uid, pid ID, rangeStart, insertBefore, rangeLength int, snapshotID string) (string, error) {
+	req := struct {
+		RangeStart   int    `json:"range_start"`
+		InsertBefore int    `json:"insert_before"`
+		RangeLength  int    `json:"range_length"`
+		SnapshotID   string `json:"snapshot_id,omitempty"`
+	}{rangeStart, insertBefore, rangeLength, snapshotID}
+	return c.modifyPlaylist("PUT", EndpointReorderTracksInPlaylist(string(uid), string(pid)), req)
+}
+
+func (c *Client) modifyPlaylist(method, endpoint string, v interface{}) (string, error) {
+	body, err := jsonBody(v)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.request(method, endpoint, body)
+	if err != nil {
+		return "", err
+	}
+
+	var snap snapshot
+	err = unmarshal(res, &snap)
+	if err != nil {
+		return "", err
+	}
+	return snap.SnapshotID, nil
+}
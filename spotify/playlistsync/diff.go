@@ -0,0 +1,232 @@
+// Package playlistsync mirrors an ordered list of URIs into a playlist with as few changes as possible
+package playlistsync
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+type OpKind string
+
+const (
+	Remove OpKind = "remove"
+	Move   OpKind = "move"
+	Add    OpKind = "add"
+)
+
+// Op is one change to a playlist, positions are those of the playlist right before the op
+// Remove uses URIs and Positions, Move uses From and To as the API's range_start and insert_before,
+// Add inserts URIs at Position.
+type Op struct {
+	Kind      OpKind
+	URIs      []spotify.URI
+	Positions []int
+	From      int
+	To        int
+	Position  int
+}
+
+func (o Op) String() string {
+	switch o.Kind {
+	case Remove:
+		return fmt.Sprintf("remove %d item(s) at %v", len(o.Positions), o.Positions)
+	case Move:
+		return fmt.Sprintf("move %s from %d to before %d", o.URIs[0], o.From, o.To)
+	case Add:
+		return fmt.Sprintf("add %d item(s) at %d: %v", len(o.URIs), o.Position, o.URIs)
+	}
+	return string(o.Kind)
+}
+
+// Plan is the list of ops that turn the current items into the desired ones
+type Plan struct {
+	Ops []Op
+}
+
+// Empty checks if the playlist is already in sync
+func (p *Plan) Empty() bool { return len(p.Ops) == 0 }
+
+// Print writes the plan one op per line
+func (p *Plan) Print(w io.Writer) error {
+	if p.Empty() {
+		_, err := fmt.Fprintln(w, "in sync, nothing to do")
+		return err
+	}
+
+	for i, op := range p.Ops {
+		if _, err := fmt.Fprintf(w, "%d. %s\n", i+1, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Diff computes the ops that turn current into desired
+// Removals come first, from the end of the playlist so earlier positions don't shift, then the fewest moves
+// that put the kept items in order, then the additions from the start so every position is final.
+func Diff(current, desired []spotify.URI) *Plan {
+	plan := &Plan{}
+
+	// pair the k-th occurrence of a URI in current with its k-th occurrence in desired
+	wanted := make(map[spotify.URI][]int)
+	for i, u := range desired {
+		wanted[u] = append(wanted[u], i)
+	}
+
+	target := make([]int, len(current))
+	paired := make([]bool, len(desired))
+	var removed []int
+	for i, u := range current {
+		if len(wanted[u]) == 0 {
+			target[i] = -1
+			removed = append(removed, i)
+			continue
+		}
+		target[i] = wanted[u][0]
+		paired[target[i]] = true
+		wanted[u] = wanted[u][1:]
+	}
+
+	// removals, grouped into requests from the end
+	sort.Sort(sort.Reverse(sort.IntSlice(removed)))
+	for len(removed) > 0 {
+		n := len(removed)
+		if n > spotify.MaxPlaylistItems {
+			n = spotify.MaxPlaylistItems
+		}
+
+		op := Op{Kind: Remove}
+		for _, pos := range removed[:n] {
+			op.URIs = append(op.URIs, current[pos])
+			op.Positions = append(op.Positions, pos)
+		}
+		plan.Ops = append(plan.Ops, op)
+		removed = removed[n:]
+	}
+
+	// the kept items, by their index in desired
+	var kept []int
+	for _, t := range target {
+		if t >= 0 {
+			kept = append(kept, t)
+		}
+	}
+	plan.Ops = append(plan.Ops, moves(kept, desired)...)
+
+	// additions, consecutive positions in one request
+	for i := 0; i < len(desired); {
+		if paired[i] {
+			i++
+			continue
+		}
+
+		op := Op{Kind: Add, Position: i}
+		for ; i < len(desired) && !paired[i] && len(op.URIs) < spotify.MaxPlaylistItems; i++ {
+			op.URIs = append(op.URIs, desired[i])
+		}
+		plan.Ops = append(plan.Ops, op)
+	}
+	return plan
+}
+
+// moves returns the fewest single item moves that sort the items
+// The items in a longest increasing subsequence stay, every other one is moved right after its predecessor.
+func moves(items []int, desired []spotify.URI) []Op {
+	stay := lis(items)
+
+	order := make([]int, len(items))
+	copy(order, items)
+	sorted := make([]int, len(items))
+	copy(sorted, items)
+	sort.Ints(sorted)
+
+	var ops []Op
+	for k, item := range sorted {
+		if stay[item] {
+			continue
+		}
+
+		from := indexOf(order, item)
+		to := 0
+		if k > 0 {
+			to = indexOf(order, sorted[k-1]) + 1
+		}
+
+		if to != from && to != from+1 {
+			ops = append(ops, Op{Kind: Move, URIs: []spotify.URI{desired[item]}, From: from, To: to})
+		}
+
+		order = append(order[:from], order[from+1:]...)
+		if to > from {
+			to--
+		}
+		order = append(order[:to], append([]int{item}, order[to:]...)...)
+	}
+	return ops
+}
+
+// lis returns the values of a longest strictly increasing subsequence
+func lis(values []int) map[int]bool {
+	var tails, tailIdx []int
+	prev := make([]int, len(values))
+	for i, v := range values {
+		j := sort.SearchInts(tails, v)
+		if j == len(tails) {
+			tails = append(tails, v)
+			tailIdx = append(tailIdx, i)
+		} else {
+			tails[j], tailIdx[j] = v, i
+		}
+
+		prev[i] = -1
+		if j > 0 {
+			prev[i] = tailIdx[j-1]
+		}
+	}
+
+	stay := make(map[int]bool)
+	if len(tailIdx) == 0 {
+		return stay
+	}
+
+	for i := tailIdx[len(tailIdx)-1]; i >= 0; i = prev[i] {
+		stay[values[i]] = true
+	}
+	return stay
+}
+
+func indexOf(s []int, v int) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// apply runs the plan on a copy of the items, it's what the API does with the ops
+func apply(items []spotify.URI, plan *Plan) []spotify.URI {
+	out := append([]spotify.URI(nil), items...)
+	for _, op := range plan.Ops {
+		switch op.Kind {
+		case Remove:
+			for _, pos := range op.Positions {
+				out = append(out[:pos], out[pos+1:]...)
+			}
+		case Move:
+			u := out[op.From]
+			to := op.To
+			out = append(out[:op.From], out[op.From+1:]...)
+			if to > op.From {
+				to--
+			}
+			out = append(out[:to], append([]spotify.URI{u}, out[to:]...)...)
+		case Add:
+			out = append(out[:op.Position], append(append([]spotify.URI(nil), op.URIs...), out[op.Position:]...)...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,222 @@
+package playlistsync
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Options configure Sync
+type Options struct {
+	// DryRun computes and prints the plan without changing the playlist
+	DryRun bool
+
+	// Out is where the plan is printed in a dry run, nothing is printed when it's nil
+	Out io.Writer
+
+	// MaxRetries is how often the sync starts over after the playlist changed underneath it, defaults to 3
+	MaxRetries int
+}
+
+// Sync makes the playlist hold exactly the desired URIs in order
+// Every change is made against the snapshot it was planned on, when someone else changes the playlist
+// in between, the contents are fetched again and a new plan is made. The plan that was applied, or would be
+// in a dry run, is returned.
+// Unavailable items have no URI to remove them by, so they stay where they are and the rest is synced around them.
+func Sync(c spotify.Playlists, uid, pid spotify.ID, desired []spotify.URI, opts *Options) (*Plan, error) {
+	o := Options{MaxRetries: 3}
+	if opts != nil {
+		o = *opts
+		if o.MaxRetries <= 0 {
+			o.MaxRetries = 3
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		before, err := c.GetPlaylist(uid, pid)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := c.AllPlaylistTracks(uid, pid)
+		if err != nil {
+			return nil, err
+		}
+
+		// the pages aren't read from one snapshot, so make sure nothing changed while they were read
+		after, err := c.GetPlaylist(uid, pid)
+		if err != nil {
+			return nil, err
+		}
+
+		if after.SnapshotID != before.SnapshotID {
+			if attempt >= o.MaxRetries {
+				return nil, fmt.Errorf("Playlist %s kept changing while its tracks were read", pid)
+			}
+			continue
+		}
+
+		current := URIs(items)
+		plan := Diff(current, desired)
+		if got := apply(current, plan); !equal(got, desired) {
+			return plan, fmt.Errorf("Invalid sync plan: it leads to %d items instead of the %d desired", len(got), len(desired))
+		}
+		plan = place(plan, available(items))
+
+		if o.DryRun {
+			if o.Out != nil {
+				return plan, plan.Print(o.Out)
+			}
+			return plan, nil
+		}
+
+		err = execute(c, uid, pid, plan, after.SnapshotID)
+		if err == nil || !IsConflict(err) || attempt >= o.MaxRetries {
+			return plan, err
+		}
+	}
+}
+
+// execute runs the ops in order, each against the snapshot the previous one returned
+func execute(c spotify.Playlists, uid, pid spotify.ID, plan *Plan, snapshotID string) error {
+	var err error
+	for _, op := range plan.Ops {
+		switch op.Kind {
+		case Remove:
+			tracks := make([]spotify.TrackPositions, len(op.URIs))
+			for i := range op.URIs {
+				tracks[i] = spotify.TrackPositions{URI: op.URIs[i], Positions: []int{op.Positions[i]}}
+			}
+			snapshotID, err = c.RemovePlaylistTracks(uid, pid, tracks, snapshotID)
+		case Move:
+			snapshotID, err = c.ReorderPlaylistTracks(uid, pid, op.From, op.To, 1, snapshotID)
+		case Add:
+			// adding doesn't take a snapshot, so the positions are only as good as the last checked change
+			snapshotID, err = c.AddTracksToPlaylist(uid, pid, op.URIs, op.Position)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsConflict checks if the error is the API rejecting a change because the snapshot is outdated
+func IsConflict(err error) bool {
+	e, ok := err.(*spotify.SpotifyError)
+	if !ok {
+		return false
+	}
+
+	switch e.Status {
+	case 409, 412:
+		return true
+	case 400:
+		return strings.Contains(strings.ToLower(e.Message), "snapshot")
+	}
+	return false
+}
+
+// URIs returns the URIs of the available playlist items in order, unavailable items are left out
+func URIs(items []*spotify.PlaylistTrack) []spotify.URI {
+	uris := make([]spotify.URI, 0, len(items))
+	for _, item := range items {
+		if u := uri(item); u != "" {
+			uris = append(uris, u)
+		}
+	}
+	return uris
+}
+
+func uri(item *spotify.PlaylistTrack) spotify.URI {
+	switch {
+	case item.Track != nil && item.Track.Track != nil:
+		return item.Track.Track.URI
+	case item.Track != nil && item.Track.Episode != nil:
+		return item.Track.Episode.URI
+	}
+	return ""
+}
+
+// available reports for every playlist item if it has a URI
+func available(items []*spotify.PlaylistTrack) []bool {
+	ok := make([]bool, len(items))
+	for i, item := range items {
+		ok[i] = uri(item) != ""
+	}
+	return ok
+}
+
+// place turns the positions of a plan made on the available items into positions in the playlist,
+// in which the unavailable items stay where they are
+func place(plan *Plan, available []bool) *Plan {
+	slots := append([]bool(nil), available...)
+
+	// position returns the playlist index of the n-th available item, or the end of the playlist
+	position := func(n int) int {
+		for i, ok := range slots {
+			if !ok {
+				continue
+			}
+			if n == 0 {
+				return i
+			}
+			n--
+		}
+		return len(slots)
+	}
+
+	placed := &Plan{}
+	for _, op := range plan.Ops {
+		switch op.Kind {
+		case Remove:
+			positions := make([]int, len(op.Positions))
+			for i, pos := range op.Positions {
+				positions[i] = position(pos)
+			}
+			op.Positions = positions
+
+			removed := append([]int(nil), positions...)
+			sort.Sort(sort.Reverse(sort.IntSlice(removed)))
+			for _, pos := range removed {
+				slots = append(slots[:pos], slots[pos+1:]...)
+			}
+		case Move:
+			op.From, op.To = position(op.From), position(op.To)
+
+			to := op.To
+			slots = append(slots[:op.From], slots[op.From+1:]...)
+			if to > op.From {
+				to--
+			}
+			slots = append(slots[:to], append([]bool{true}, slots[to:]...)...)
+		case Add:
+			op.Position = position(op.Position)
+
+			added := make([]bool, len(op.URIs))
+			for i := range added {
+				added[i] = true
+			}
+			slots = append(slots[:op.Position], append(added, slots[op.Position:]...)...)
+		}
+		placed.Ops = append(placed.Ops, op)
+	}
+	return placed
+}
+
+func equal(a, b []spotify.URI) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package playlistsync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/spotifytest"
+)
+
+func TestPlaceAroundUnavailableItems(t *testing.T) {
+	tests := []struct {
+		name     string
+		playlist []spotify.URI // "" is an unavailable item
+		desired  []spotify.URI
+	}{
+		{"none unavailable", []spotify.URI{"a", "b", "c"}, []spotify.URI{"c", "a", "d"}},
+		{"first", []spotify.URI{"", "a", "b", "c"}, []spotify.URI{"c", "b", "a"}},
+		{"between", []spotify.URI{"a", "", "b", "c", "", "d"}, []spotify.URI{"d", "x", "b", "a", "y"}},
+		{"last", []spotify.URI{"a", "b", ""}, []spotify.URI{"b", "c"}},
+		{"only", []spotify.URI{"", ""}, []spotify.URI{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]*spotify.PlaylistTrack, len(tt.playlist))
+			for i, u := range tt.playlist {
+				items[i] = &spotify.PlaylistTrack{}
+				if u != "" {
+					items[i].Track = &spotify.PlaylistItem{Track: &spotify.FullTrack{}}
+					items[i].Track.Track.URI = u
+				}
+			}
+
+			current := URIs(items)
+			plan := place(Diff(current, tt.desired), available(items))
+
+			for _, op := range plan.Ops {
+				for i, pos := range op.Positions {
+					if op.Kind == Remove && op.URIs[i] == "" {
+						t.Errorf("remove without a URI at %d", pos)
+					}
+				}
+			}
+
+			got := apply(tt.playlist, plan)
+			var synced []spotify.URI
+			holes := 0
+			for _, u := range got {
+				if u == "" {
+					holes++
+					continue
+				}
+				synced = append(synced, u)
+			}
+
+			if !reflect.DeepEqual(synced, tt.desired) {
+				t.Errorf("synced to %v, want %v", synced, tt.desired)
+			}
+			if holes != len(tt.playlist)-len(current) {
+				t.Errorf("%d unavailable items left, want %d", holes, len(tt.playlist)-len(current))
+			}
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	d := spotifytest.Seed()
+	s := spotifytest.NewServer(d)
+	defer s.Close()
+
+	pl := d.Playlists[0]
+	var desired []spotify.URI
+	for i := len(pl.Tracks) - 1; i > 0; i-- {
+		desired = append(desired, pl.Tracks[i].URI(spotify.KindTrack))
+	}
+	desired = append(desired, d.Playlists[1].Tracks[0].URI(spotify.KindTrack))
+
+	plan, err := Sync(s.Client(), d.User.ID, pl.ID, desired, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Empty() {
+		t.Fatal("empty plan")
+	}
+
+	ids, _ := s.PlaylistTracks(pl.ID)
+	var got []spotify.URI
+	for _, id := range ids {
+		got = append(got, id.URI(spotify.KindTrack))
+	}
+	if !reflect.DeepEqual(got, desired) {
+		t.Errorf("playlist is %v, want %v", got, desired)
+	}
+
+	plan, err = Sync(s.Client(), d.User.ID, pl.ID, desired, nil)
+	if err != nil || !plan.Empty() {
+		t.Errorf("second sync = %v, %v, want an empty plan", plan.Ops, err)
+	}
+}
@@ -0,0 +1,249 @@
+package spotify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SearchQuery builds a search query with field filters
+// Empty fields are left out, e.g:
+//
+//	SearchQuery{Artist: "Miles Davis", YearFrom: 1955, YearTo: 1960, Tags: []string{"hipster"}}
+//
+// becomes `artist:"Miles Davis" year:1955-1960 tag:hipster`
+type SearchQuery struct {
+	// Terms are free keywords, each a single word without quotes or ':'
+	// Use Phrases for anything longer, see Validate
+	Terms []string
+
+	// Phrases are matched exactly and always quoted
+	Phrases []string
+
+	Artist string
+	Album  string
+	Track  string
+	Genre  string
+	ISRC   string
+	UPC    string
+
+	// YearFrom and YearTo filter on the release year, set only YearFrom for a single year
+	// and only YearTo for every year up to it
+	YearFrom int
+	YearTo   int
+
+	// Tags are "new" for albums released in the past two weeks and "hipster" for the lowest 10% popularity
+	Tags []string
+}
+
+// Validate reports terms, tags and years that String can't render so that ParseSearchQuery gives them back unchanged
+func (q *SearchQuery) Validate() error {
+	if q.YearFrom < 0 || q.YearTo < 0 || (q.YearFrom > 0 && q.YearTo > 0 && q.YearTo < q.YearFrom) {
+		return fmt.Errorf("Invalid year range: %d-%d", q.YearFrom, q.YearTo)
+	}
+
+	for _, t := range q.Terms {
+		if strings.IndexFunc(t, unicode.IsSpace) >= 0 || strings.ContainsAny(t, `":`) {
+			return fmt.Errorf("Invalid search term: %q, use a phrase or a field filter instead", t)
+		}
+	}
+
+	for _, t := range q.Tags {
+		if strings.IndexFunc(t, unicode.IsSpace) >= 0 || strings.ContainsAny(t, `":`) {
+			return fmt.Errorf("Invalid search tag: %q", t)
+		}
+	}
+	return nil
+}
+
+// String renders the query in Spotify's search syntax
+// The result is only exact for queries that pass Validate
+func (q *SearchQuery) String() string {
+	var parts []string
+	for _, t := range q.Terms {
+		if t = strings.TrimSpace(t); t != "" {
+			parts = append(parts, quoteTerm(t, false))
+		}
+	}
+
+	for _, p := range q.Phrases {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, quoteTerm(p, true))
+		}
+	}
+
+	fields := []struct{ name, value string }{
+		{"artist", q.Artist},
+		{"album", q.Album},
+		{"track", q.Track},
+		{"genre", q.Genre},
+		{"isrc", q.ISRC},
+		{"upc", q.UPC},
+	}
+	for _, f := range fields {
+		if v := strings.TrimSpace(f.value); v != "" {
+			parts = append(parts, f.name+":"+quoteTerm(v, false))
+		}
+	}
+
+	switch {
+	case q.YearTo > 0 && q.YearTo != q.YearFrom:
+		parts = append(parts, fmt.Sprintf("year:%d-%d", q.YearFrom, q.YearTo))
+	case q.YearFrom > 0:
+		parts = append(parts, fmt.Sprintf("year:%d", q.YearFrom))
+	}
+
+	for _, t := range q.Tags {
+		if t = strings.TrimSpace(t); t != "" {
+			parts = append(parts, "tag:"+t)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteTerm quotes a value if it has to be, or always when force is set
+// Double quotes inside the value can't be escaped in the search syntax, so they are dropped
+func quoteTerm(v string, force bool) string {
+	v = strings.Replace(v, `"`, "", -1)
+	if force || strings.IndexFunc(v, unicode.IsSpace) >= 0 || strings.Contains(v, ":") {
+		return `"` + v + `"`
+	}
+	return v
+}
+
+// ParseSearchQuery parses a query string in Spotify's search syntax back into a SearchQuery
+// Unknown field filters are rejected since Spotify doesn't support them either, and so are repeated ones
+// since a SearchQuery holds one value per field. Only tag: can be repeated.
+// A word with a ':' that doesn't follow a field name, like 12:30, is free text and becomes a phrase.
+func ParseSearchQuery(s string) (*SearchQuery, error) {
+	tokens, err := tokenizeQuery(s)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &SearchQuery{}
+	seen := make(map[string]bool)
+	for _, tok := range tokens {
+		if tok.field == "" {
+			if tok.quoted || strings.Contains(tok.value, ":") {
+				q.Phrases = append(q.Phrases, tok.value)
+			} else {
+				q.Terms = append(q.Terms, tok.value)
+			}
+			continue
+		}
+
+		if seen[tok.field] && tok.field != "tag" {
+			return nil, fmt.Errorf("Invalid query: repeated field filter %s:", tok.field)
+		}
+		seen[tok.field] = true
+
+		switch tok.field {
+		case "artist":
+			q.Artist = tok.value
+		case "album":
+			q.Album = tok.value
+		case "track":
+			q.Track = tok.value
+		case "genre":
+			q.Genre = tok.value
+		case "isrc":
+			q.ISRC = tok.value
+		case "upc":
+			q.UPC = tok.value
+		case "tag":
+			q.Tags = append(q.Tags, tok.value)
+		case "year":
+			from, to, err := parseYearRange(tok.value)
+			if err != nil {
+				return nil, err
+			}
+			q.YearFrom, q.YearTo = from, to
+		default:
+			return nil, fmt.Errorf("Invalid query: unknown field filter %s:", tok.field)
+		}
+	}
+
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func parseYearRange(s string) (int, int, error) {
+	parts := strings.SplitN(s, "-", 2)
+	from, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid year: %s", s)
+	}
+
+	if len(parts) == 1 {
+		return from, 0, nil
+	}
+
+	to, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid year range: %s", s)
+	}
+	return from, to, nil
+}
+
+type queryToken struct {
+	field  string
+	value  string
+	quoted bool
+}
+
+// tokenizeQuery splits a query on whitespace outside of quotes, separating field prefixes like artist:
+func tokenizeQuery(s string) ([]queryToken, error) {
+	var (
+		tokens []queryToken
+		buf    strings.Builder
+		tok    queryToken
+		inTok  bool
+		quoted bool
+	)
+
+	flush := func() {
+		if inTok {
+			tok.value = buf.String()
+			if tok.value != "" || tok.quoted {
+				tokens = append(tokens, tok)
+			}
+		}
+		buf.Reset()
+		tok = queryToken{}
+		inTok = false
+	}
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inTok = true
+			quoted = !quoted
+			tok.quoted = true
+		case quoted:
+			buf.WriteRune(r)
+		case unicode.IsSpace(r):
+			flush()
+		case r == ':' && tok.field == "" && !tok.quoted && isFieldName(buf.String()):
+			tok.field = strings.ToLower(buf.String())
+			buf.Reset()
+		default:
+			inTok = true
+			buf.WriteRune(r)
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("Invalid query: unterminated quote in %q", s)
+	}
+	flush()
+	return tokens, nil
+}
+
+// isFieldName reports if s can be the name of a field filter, names are letters only
+func isFieldName(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }) < 0
+}
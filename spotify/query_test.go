@@ -0,0 +1,128 @@
+package spotify
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchQueryString(t *testing.T) {
+	tests := []struct {
+		name  string
+		query SearchQuery
+		want  string
+	}{
+		{"empty", SearchQuery{}, ""},
+		{"terms and phrases", SearchQuery{Terms: []string{"so", "what"}, Phrases: []string{"kind of blue"}}, `so what "kind of blue"`},
+		{"fields", SearchQuery{Artist: "Miles Davis", Album: "Kind", Track: "So What", Genre: "jazz", ISRC: "USSM15900113", UPC: "886445195805"},
+			`artist:"Miles Davis" album:Kind track:"So What" genre:jazz isrc:USSM15900113 upc:886445195805`},
+		{"quotes are dropped", SearchQuery{Artist: `The "Real" Group`}, `artist:"The Real Group"`},
+		{"colon is quoted", SearchQuery{Track: "Re:Stacks"}, `track:"Re:Stacks"`},
+		{"single year", SearchQuery{YearFrom: 1959}, "year:1959"},
+		{"same years", SearchQuery{YearFrom: 1959, YearTo: 1959}, "year:1959"},
+		{"year range", SearchQuery{YearFrom: 1955, YearTo: 1960}, "year:1955-1960"},
+		{"up to a year", SearchQuery{YearTo: 1960}, "year:0-1960"},
+		{"tags", SearchQuery{Terms: []string{"jazz"}, Tags: []string{"new", "hipster"}}, "jazz tag:new tag:hipster"},
+		{"blanks are left out", SearchQuery{Terms: []string{" "}, Phrases: []string{""}, Artist: " "}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    *SearchQuery
+		wantErr bool
+	}{
+		{"so what", &SearchQuery{Terms: []string{"so", "what"}}, false},
+		{`"kind of blue" miles`, &SearchQuery{Terms: []string{"miles"}, Phrases: []string{"kind of blue"}}, false},
+		{`artist:"Miles Davis" year:1955-1960 tag:hipster`, &SearchQuery{Artist: "Miles Davis", YearFrom: 1955, YearTo: 1960, Tags: []string{"hipster"}}, false},
+		{`ARTIST:miles Track:"so what"`, &SearchQuery{Artist: "miles", Track: "so what"}, false},
+		{"year:1959", &SearchQuery{YearFrom: 1959}, false},
+		{"year:0-1960", &SearchQuery{YearTo: 1960}, false},
+		{"tag:new tag:hipster", &SearchQuery{Tags: []string{"new", "hipster"}}, false},
+		{"  isrc:USSM15900113\tupc:886445195805 ", &SearchQuery{ISRC: "USSM15900113", UPC: "886445195805"}, false},
+		{"meet at 12:30", &SearchQuery{Terms: []string{"meet", "at"}, Phrases: []string{"12:30"}}, false},
+		{`track:"Re:Stacks"`, &SearchQuery{Track: "Re:Stacks"}, false},
+		{"", &SearchQuery{}, false},
+		{"label:blue", nil, true},
+		{"artist:miles artist:coltrane", nil, true},
+		{"year:1959 year:1960", nil, true},
+		{"year:1960-1955", nil, true},
+		{"year:late", nil, true},
+		{"year:1955-", nil, true},
+		{`artist:"Miles`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := ParseSearchQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQueryRoundTrip(t *testing.T) {
+	tests := []SearchQuery{
+		{Terms: []string{"so", "what"}},
+		{Phrases: []string{"kind of blue", "12:30"}},
+		{Artist: "Miles Davis", Album: "Kind Of Blue", Track: "So What", Genre: "cool jazz"},
+		{ISRC: "USSM15900113", UPC: "886445195805"},
+		{Track: "Re:Stacks", Artist: "Bon Iver"},
+		{YearFrom: 1959},
+		{YearFrom: 1955, YearTo: 1960},
+		{YearTo: 1960},
+		{Terms: []string{"jazz"}, Phrases: []string{"blue note"}, Artist: "Lee Morgan", YearFrom: 1963, YearTo: 1965, Tags: []string{"new", "hipster"}},
+	}
+
+	for _, q := range tests {
+		t.Run(q.String(), func(t *testing.T) {
+			if err := q.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseSearchQuery(q.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, q) {
+				t.Errorf("got %+v back, want %+v", *got, q)
+			}
+		})
+	}
+}
+
+func TestSearchQueryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   SearchQuery
+		wantErr bool
+	}{
+		{"valid", SearchQuery{Terms: []string{"jazz"}, Tags: []string{"new"}, YearFrom: 1955, YearTo: 1960}, false},
+		{"term with a space", SearchQuery{Terms: []string{"so what"}}, true},
+		{"term with a colon", SearchQuery{Terms: []string{"12:30"}}, true},
+		{"term with a quote", SearchQuery{Terms: []string{`"so`}}, true},
+		{"tag with a space", SearchQuery{Tags: []string{"new hipster"}}, true},
+		{"years backwards", SearchQuery{YearFrom: 1960, YearTo: 1955}, true},
+		{"negative year", SearchQuery{YearFrom: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package spotify
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MaxRecommendationSeeds is the most seed artists, tracks and genres combined a recommendation request can have
+const MaxRecommendationSeeds = 5
+
+// RecommendationOptions are the parameters of a recommendation request
+// At least one and at most MaxRecommendationSeeds seeds have to be given.
+// The tunable attributes mirror AudioFeatures, nil attributes are left out of the request.
+// Float and Int can be used to set them inline.
+type RecommendationOptions struct {
+	SeedArtists []ID
+	SeedTracks  []ID
+	SeedGenres  []string
+
+	// Limit is the target size of the list of tracks, default is 20, minimum is 1 and maximum is 100
+	Limit int
+
+	// Market is an ISO 3166-1 alpha-2 country code, only tracks playable in it are returned
+	Market string
+
+	MinAcousticness, MaxAcousticness, TargetAcousticness             *float32
+	MinDanceability, MaxDanceability, TargetDanceability             *float32
+	MinDurationMs, MaxDurationMs, TargetDurationMs                   *int
+	MinEnergy, MaxEnergy, TargetEnergy                               *float32
+	MinInstrumentalness, MaxInstrumentalness, TargetInstrumentalness *float32
+	MinKey, MaxKey, TargetKey                                        *int
+	MinLiveness, MaxLiveness, TargetLiveness                         *float32
+	MinLoudness, MaxLoudness, TargetLoudness                         *float32
+	MinMode, MaxMode, TargetMode                                     *int
+	MinPopularity, MaxPopularity, TargetPopularity                   *int
+	MinSpeechiness, MaxSpeechiness, TargetSpeechiness                *float32
+	MinTempo, MaxTempo, TargetTempo                                  *float32
+	MinTimeSignature, MaxTimeSignature, TargetTimeSignature          *int
+	MinValence, MaxValence, TargetValence                            *float32
+}
+
+// Float returns a pointer to v, for setting the float attributes of RecommendationOptions
+func Float(v float32) *float32 { return &v }
+
+// Int returns a pointer to v, for setting the integer attributes of RecommendationOptions
+func Int(v int) *int { return &v }
+
+type floatAttribute struct {
+	name          string
+	min, max, tgt *float32
+	lo, hi        float32
+	bounded       bool
+}
+
+type intAttribute struct {
+	name          string
+	min, max, tgt *int
+	lo, hi        int
+	bounded       bool
+}
+
+func (o *RecommendationOptions) floatAttributes() []floatAttribute {
+	return []floatAttribute{
+		{"acousticness", o.MinAcousticness, o.MaxAcousticness, o.TargetAcousticness, 0, 1, true},
+		{"danceability", o.MinDanceability, o.MaxDanceability, o.TargetDanceability, 0, 1, true},
+		{"energy", o.MinEnergy, o.MaxEnergy, o.TargetEnergy, 0, 1, true},
+		{"instrumentalness", o.MinInstrumentalness, o.MaxInstrumentalness, o.TargetInstrumentalness, 0, 1, true},
+		{"liveness", o.MinLiveness, o.MaxLiveness, o.TargetLiveness, 0, 1, true},
+		{"loudness", o.MinLoudness, o.MaxLoudness, o.TargetLoudness, 0, 0, false},
+		{"speechiness", o.MinSpeechiness, o.MaxSpeechiness, o.TargetSpeechiness, 0, 1, true},
+		{"tempo", o.MinTempo, o.MaxTempo, o.TargetTempo, 0, 0, false},
+		{"valence", o.MinValence, o.MaxValence, o.TargetValence, 0, 1, true},
+	}
+}
+
+func (o *RecommendationOptions) intAttributes() []intAttribute {
+	return []intAttribute{
+		{"duration_ms", o.MinDurationMs, o.MaxDurationMs, o.TargetDurationMs, 0, 0, false},
+		{"key", o.MinKey, o.MaxKey, o.TargetKey, 0, 11, true},
+		{"mode", o.MinMode, o.MaxMode, o.TargetMode, 0, 1, true},
+		{"popularity", o.MinPopularity, o.MaxPopularity, o.TargetPopularity, 0, 100, true},
+		{"time_signature", o.MinTimeSignature, o.MaxTimeSignature, o.TargetTimeSignature, 0, 0, false},
+	}
+}
+
+// Validate checks the seed count, the limit and the ranges of the tunable attributes
+func (o *RecommendationOptions) Validate() error {
+	seeds := len(o.SeedArtists) + len(o.SeedTracks) + len(o.SeedGenres)
+	if seeds == 0 {
+		return fmt.Errorf("Missing required parameter: at least one seed artist, track or genre")
+	}
+
+	if seeds > MaxRecommendationSeeds {
+		return fmt.Errorf("Too many seeds: %d given, at most %d allowed", seeds, MaxRecommendationSeeds)
+	}
+
+	if o.Limit < 0 || o.Limit > 100 {
+		return fmt.Errorf("Invalid limit: %d, must be between 1 and 100", o.Limit)
+	}
+
+	for _, a := range o.floatAttributes() {
+		for _, v := range []*float32{a.min, a.max, a.tgt} {
+			if v != nil && a.bounded && (*v < a.lo || *v > a.hi) {
+				return fmt.Errorf("Invalid %s: %v, must be between %v and %v", a.name, *v, a.lo, a.hi)
+			}
+		}
+
+		if a.min != nil && a.max != nil && *a.min > *a.max {
+			return fmt.Errorf("Invalid %s: min %v is greater than max %v", a.name, *a.min, *a.max)
+		}
+	}
+
+	for _, a := range o.intAttributes() {
+		for _, v := range []*int{a.min, a.max, a.tgt} {
+			if v != nil && a.bounded && (*v < a.lo || *v > a.hi) {
+				return fmt.Errorf("Invalid %s: %d, must be between %d and %d", a.name, *v, a.lo, a.hi)
+			}
+		}
+
+		if a.min != nil && a.max != nil && *a.min > *a.max {
+			return fmt.Errorf("Invalid %s: min %d is greater than max %d", a.name, *a.min, *a.max)
+		}
+	}
+	return nil
+}
+
+// Values serializes the options into query parameters
+func (o *RecommendationOptions) Values() url.Values {
+	vals := url.Values{}
+	if len(o.SeedArtists) > 0 {
+		vals.Set("seed_artists", strings.Join(idStrings(o.SeedArtists), ","))
+	}
+
+	if len(o.SeedTracks) > 0 {
+		vals.Set("seed_tracks", strings.Join(idStrings(o.SeedTracks), ","))
+	}
+
+	if len(o.SeedGenres) > 0 {
+		vals.Set("seed_genres", strings.Join(o.SeedGenres, ","))
+	}
+
+	if o.Limit > 0 {
+		vals.Set("limit", strconv.Itoa(o.Limit))
+	}
+
+	if o.Market != "" {
+		vals.Set("market", o.Market)
+	}
+
+	for _, a := range o.floatAttributes() {
+		setFloat(vals, "min_"+a.name, a.min)
+		setFloat(vals, "max_"+a.name, a.max)
+		setFloat(vals, "target_"+a.name, a.tgt)
+	}
+
+	for _, a := range o.intAttributes() {
+		setInt(vals, "min_"+a.name, a.min)
+		setInt(vals, "max_"+a.name, a.max)
+		setInt(vals, "target_"+a.name, a.tgt)
+	}
+	return vals
+}
+
+func setFloat(vals url.Values, key string, v *float32) {
+	if v != nil {
+		vals.Set(key, strconv.FormatFloat(float64(*v), 'f', -1, 32))
+	}
+}
+
+func setInt(vals url.Values, key string, v *int) {
+	if v != nil {
+		vals.Set(key, strconv.Itoa(*v))
+	}
+}
+
+// GetRecommendationsWithOptions gets track recommendations for the validated options
+func (c *Client) GetRecommendationsWithOptions(opts *RecommendationOptions) (*Recommendations, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("Missing required parameter: opts")
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return c.GetRecommendations(opts.Values().Encode())
+}
@@ -0,0 +1,205 @@
+// Package recorder is an http.RoundTripper that records interactions with the API into fixture files
+// and replays them, so code using spotify.Client can be run against pinned responses
+//
+//	rec, err := recorder.New("testdata/get_album.json", recorder.Replay)
+//	c := spotify.NewWithToken("token")
+//	c.HTTPClient = rec.Client()
+//	...
+//	err = rec.Stop()
+//
+// In Record mode the requests go to the real API and the scrubbed interactions are written by Stop.
+// In Replay mode a request is scrubbed the same way before it is matched against the recorded ones.
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Mode is whether a Recorder records or replays
+type Mode int
+
+const (
+	// Replay serves the interactions of the fixture file and fails requests that weren't recorded
+	Replay Mode = iota
+
+	// Record sends the requests on and records them, the fixture file is overwritten by Stop
+	Record
+
+	// ReplayOrRecord replays if the fixture file exists and records it otherwise
+	ReplayOrRecord
+)
+
+// ModeFromEnv returns Record if the environment variable is set to anything, Replay otherwise
+// e.g: SPOTIFY_RECORD=1 go test ./...
+func ModeFromEnv(name string) Mode {
+	if os.Getenv(name) != "" {
+		return Record
+	}
+	return Replay
+}
+
+// Recorder records or replays the interactions of a fixture file
+type Recorder struct {
+	// Transport sends the requests in Record mode, defaults to http.DefaultTransport
+	Transport http.RoundTripper
+
+	// Scrubbers are run on every recorded interaction after Scrub, to remove other private data
+	Scrubbers []func(*Interaction)
+
+	path     string
+	mode     Mode
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New makes a recorder for the fixture file, in Replay mode the file is loaded right away
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, cassette: &Cassette{}}
+
+	if mode == ReplayOrRecord {
+		r.mode = Record
+		if _, err := os.Stat(path); err == nil {
+			r.mode = Replay
+		}
+	}
+
+	if r.mode == Replay {
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	}
+	return r, nil
+}
+
+// Mode is the mode the recorder runs in, ReplayOrRecord is resolved to one of the others
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client using the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays a request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == Replay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+// replay serves the first unused interaction matching the request
+// Identical requests are answered in the order they were recorded.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the recorded requests are scrubbed, so the request is compared in its scrubbed form
+	live := &Interaction{
+		Request:  &Request{Method: req.Method, URL: req.URL.String(), Body: newBody(body)},
+		Response: &Response{Header: http.Header{}},
+	}
+	r.scrub(live)
+
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !in.Request.matches(live.Request) {
+			continue
+		}
+
+		r.used[i] = true
+		b := in.Response.Body.Bytes()
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(b)),
+			ContentLength: int64(len(b)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("recorder: no recorded interaction for %s %s in %s", req.Method, req.URL, r.path)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	t := r.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+
+	res, err := t.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := readBody(&res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &Interaction{
+		Request:  &Request{Method: req.Method, URL: req.URL.String(), Body: newBody(body)},
+		Response: &Response{Status: res.StatusCode, Header: res.Header.Clone(), Body: newBody(resBody)},
+	}
+
+	r.scrub(in)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.mu.Unlock()
+	return res, nil
+}
+
+func (r *Recorder) scrub(in *Interaction) {
+	Scrub(in)
+	for _, s := range r.Scrubbers {
+		s(in)
+	}
+}
+
+// Unused returns the recorded interactions that weren't replayed
+// A replay that leaves some unused made fewer requests than the recording did.
+func (r *Recorder) Unused() []*Interaction {
+	if r.mode != Replay {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []*Interaction
+	for i, in := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, in)
+		}
+	}
+	return unused
+}
+
+// Stop writes the fixture file when recording, it does nothing when replaying
+func (r *Recorder) Stop() error {
+	if r.mode != Record {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
@@ -0,0 +1,220 @@
+// Package resolve matches tracklists from other services to Spotify tracks
+// Tracklists in CSV, M3U8, XSPF or JSPF are read into entries with export.Read.
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/export"
+)
+
+type Status string
+
+const (
+	Matched   Status = "matched"
+	Ambiguous Status = "ambiguous"
+	Unmatched Status = "unmatched"
+)
+
+// Candidate is a Spotify track scored against an entry, Confidence is 0-1
+type Candidate struct {
+	Track      *spotify.FullTrack
+	Confidence float64
+}
+
+// Match is the outcome for one entry
+// Track is the best candidate when the entry is Matched, Candidates holds every scored track, best first.
+type Match struct {
+	Entry      export.Entry
+	Status     Status
+	Track      *spotify.FullTrack
+	Confidence float64
+	Candidates []Candidate
+	Err        error
+}
+
+// Report is the outcome for a whole tracklist, Matches are in the order of the entries
+type Report struct {
+	Matches []*Match
+}
+
+// Filter returns the matches with the status
+func (r *Report) Filter(status Status) []*Match {
+	var matches []*Match
+	for _, m := range r.Matches {
+		if m.Status == status {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// URIs returns the URIs of the matched tracks in order
+func (r *Report) URIs() []spotify.URI {
+	var uris []spotify.URI
+	for _, m := range r.Filter(Matched) {
+		uris = append(uris, m.Track.URI)
+	}
+	return uris
+}
+
+// Options tune the matching
+type Options struct {
+	// MinConfidence is the least confidence a fuzzy match needs, defaults to 0.8
+	MinConfidence float64
+
+	// AmbiguityMargin is how close the two best candidates may be before the entry is ambiguous, defaults to 0.05
+	AmbiguityMargin float64
+
+	// SearchOnly skips the ISRC lookup, for lists whose ISRCs aren't trusted
+	SearchOnly bool
+}
+
+// Client is what a Resolver searches the catalog with and creates playlists of the matches with
+type Client interface {
+	spotify.Searcher
+	spotify.Playlists
+}
+
+// Resolver matches entries using a client
+type Resolver struct {
+	client Client
+	opts   Options
+}
+
+func New(c Client, opts *Options) *Resolver {
+	r := &Resolver{client: c, opts: Options{MinConfidence: 0.8, AmbiguityMargin: 0.05}}
+	if opts != nil {
+		if opts.MinConfidence > 0 {
+			r.opts.MinConfidence = opts.MinConfidence
+		}
+
+		if opts.AmbiguityMargin > 0 {
+			r.opts.AmbiguityMargin = opts.AmbiguityMargin
+		}
+		r.opts.SearchOnly = opts.SearchOnly
+	}
+	return r
+}
+
+// Resolve matches every entry, an entry whose lookups fail is Unmatched with its Err set
+// It only returns an error when the context is done.
+func (r *Resolver) Resolve(ctx context.Context, entries []export.Entry) (*Report, error) {
+	report := &Report{}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		report.Matches = append(report.Matches, r.ResolveEntry(ctx, e))
+	}
+	return report, nil
+}
+
+// ResolveEntry matches one entry, by ISRC first and then by searching for its artist and title
+func (r *Resolver) ResolveEntry(ctx context.Context, e export.Entry) *Match {
+	m := &Match{Entry: e, Status: Unmatched}
+	if e.ISRC != "" && !r.opts.SearchOnly {
+		tracks, err := r.client.TrackByISRC(ctx, e.ISRC)
+		if err != nil {
+			// kept unless the search below succeeds
+			m.Err = err
+		} else if len(tracks) > 0 {
+			// the same recording on several releases is the same match, prefer the most popular one
+			sort.SliceStable(tracks, func(i, j int) bool { return tracks[i].Popularity > tracks[j].Popularity })
+			for _, t := range tracks {
+				m.Candidates = append(m.Candidates, Candidate{Track: t, Confidence: 1})
+			}
+			m.Status, m.Track, m.Confidence = Matched, tracks[0], 1
+			return m
+		}
+	}
+
+	if e.Title == "" {
+		return m
+	}
+
+	tracks, err := r.search(ctx, e)
+	if err != nil {
+		m.Err = err
+		return m
+	}
+	m.Err = nil
+
+	for _, t := range tracks {
+		m.Candidates = append(m.Candidates, Candidate{Track: t, Confidence: Score(e, t)})
+	}
+
+	sort.SliceStable(m.Candidates, func(i, j int) bool { return m.Candidates[i].Confidence > m.Candidates[j].Confidence })
+	if len(m.Candidates) == 0 || m.Candidates[0].Confidence < r.opts.MinConfidence {
+		return m
+	}
+
+	best := m.Candidates[0]
+	m.Track, m.Confidence = best.Track, best.Confidence
+	m.Status = Matched
+
+	if len(m.Candidates) > 1 {
+		next := m.Candidates[1]
+		if best.Confidence-next.Confidence < r.opts.AmbiguityMargin && !sameRecording(best.Track, next.Track) {
+			m.Status = Ambiguous
+		}
+	}
+	return m
+}
+
+// search looks for the entry with field filters and falls back to a plain query when that finds nothing
+func (r *Resolver) search(ctx context.Context, e export.Entry) ([]*spotify.FullTrack, error) {
+	q := &spotify.SearchQuery{Track: e.Title}
+	if len(e.Artists) > 0 {
+		q.Artist = e.Artists[0]
+	}
+
+	tracks, err := r.searchTracks(ctx, q.String())
+	if err != nil || len(tracks) > 0 {
+		return tracks, err
+	}
+
+	plain := e.Title
+	if len(e.Artists) > 0 {
+		plain = e.Artists[0] + " " + plain
+	}
+	return r.searchTracks(ctx, normalize(plain))
+}
+
+func (r *Resolver) searchTracks(ctx context.Context, query string) ([]*spotify.FullTrack, error) {
+	res, err := r.client.Search(ctx, query, []spotify.SearchType{spotify.SearchTypeTrack}, nil)
+	if err != nil || res.Tracks == nil {
+		return nil, err
+	}
+	return res.Tracks.Items, nil
+}
+
+func sameRecording(a, b *spotify.FullTrack) bool {
+	isrc := a.ExternalIDs.ISRC()
+	return a.ID == b.ID || isrc != "" && isrc == b.ExternalIDs.ISRC()
+}
+
+// CreatePlaylist creates a playlist for the user with the matched tracks of the report
+func (r *Resolver) CreatePlaylist(uid spotify.ID, name, description string, public bool, report *Report) (*spotify.FullPlaylist, error) {
+	pl, err := r.client.CreatePlaylist(uid, name, description, public)
+	if err != nil {
+		return nil, err
+	}
+
+	uris := report.URIs()
+	for len(uris) > 0 {
+		n := len(uris)
+		if n > spotify.MaxPlaylistItems {
+			n = spotify.MaxPlaylistItems
+		}
+
+		if _, err = r.client.AddTracksToPlaylist(uid, pl.ID, uris[:n], -1); err != nil {
+			return pl, fmt.Errorf("Created playlist %s but adding tracks failed: %v", pl.ID, err)
+		}
+		uris = uris[n:]
+	}
+	return pl, nil
+}
@@ -0,0 +1,123 @@
+package resolve
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/export"
+)
+
+// The weights of the parts of a score, the duration weight is spread over the others when it's unknown
+const (
+	titleWeight    = 0.5
+	artistWeight   = 0.35
+	durationWeight = 0.15
+
+	// durationTolerance is the difference in milliseconds at which the duration stops counting
+	durationTolerance = 10000
+)
+
+// noise are the title suffixes services add that don't identify a recording
+var noise = []string{" - remaster", " - remastered", " - single version", " - radio edit", " (remaster", " (remastered", " [remaster"}
+
+// Score returns how confident it is that the track is the entry, 0-1
+func Score(e export.Entry, t *spotify.FullTrack) float64 {
+	title := similarity(normalizeTitle(e.Title), normalizeTitle(t.Name))
+
+	artist := 0.0
+	if len(e.Artists) == 0 {
+		artist = 0.5
+	}
+
+	for _, want := range e.Artists {
+		for _, a := range t.Artists {
+			artist = math.Max(artist, similarity(normalize(want), normalize(a.Name)))
+		}
+	}
+
+	if e.DurationMs <= 0 || t.DurationMs <= 0 {
+		return (title*titleWeight + artist*artistWeight) / (titleWeight + artistWeight)
+	}
+
+	diff := math.Abs(float64(e.DurationMs - t.DurationMs))
+	duration := math.Max(0, 1-diff/durationTolerance)
+	return title*titleWeight + artist*artistWeight + duration*durationWeight
+}
+
+// normalize lowercases and strips punctuation and repeated whitespace
+func normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		case unicode.IsSpace(r) || r == '-' || r == '/':
+			return ' '
+		}
+		return -1
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// normalizeTitle drops featured artists and remaster suffixes before normalizing
+func normalizeTitle(s string) string {
+	// normalize lowercases anyway, and lowering can change byte lengths, so search and cut the lowered title
+	s = strings.ToLower(s)
+	for _, marker := range append([]string{" (feat", " [feat", " feat.", " ft."}, noise...) {
+		if i := strings.Index(s, marker); i > 0 {
+			s = s[:i]
+		}
+	}
+	return normalize(s)
+}
+
+// similarity is 1 minus the edit distance relative to the longer string
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+
+	if c < a {
+		a = c
+	}
+	return a
+}
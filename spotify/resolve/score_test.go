@@ -0,0 +1,22 @@
+package resolve
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct{ title, want string }{
+		{"So What (feat. John Coltrane)", "so what"},
+		{"Blue in Green - Remastered 2009", "blue in green"},
+		{"Freddie Freeloader ft. Wynton Kelly", "freddie freeloader"},
+		// İ lowers to more bytes than it has, the cut has to be made in the lowered title
+		{strings.Repeat("İ", 10) + " ft.", strings.ToLower(strings.Repeat("İ", 10))},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeTitle(tt.title); got != normalize(tt.want) {
+			t.Errorf("normalizeTitle(%q) = %q, want %q", tt.title, got, normalize(tt.want))
+		}
+	}
+}
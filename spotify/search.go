@@ -0,0 +1,93 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type SearchType string
+
+const (
+	SearchTypeTrack     SearchType = "track"
+	SearchTypeAlbum     SearchType = "album"
+	SearchTypeArtist    SearchType = "artist"
+	SearchTypePlaylist  SearchType = "playlist"
+	SearchTypeShow      SearchType = "show"
+	SearchTypeEpisode   SearchType = "episode"
+	SearchTypeAudiobook SearchType = "audiobook"
+)
+
+// SearchOptions are the optional parameters of Search
+// Market: ISO 3166-1 alpha-2 country code, or "from_token" for the user's country
+// Limit: Max amount of items per type, Default is 20, minimum is 1 and maximum is 50
+// Offset: The index of the first object, default is 0, maximum is 1000
+// IncludeExternalAudio: include externally hosted audio content in the episode results
+type SearchOptions struct {
+	Market               string
+	Limit                int
+	Offset               int
+	IncludeExternalAudio bool
+}
+
+// SearchResult holds a page for every type that was searched for, the other pages are nil
+type SearchResult struct {
+	Tracks     *TrackPage     `json:"tracks"`
+	Albums     *AlbumPage     `json:"albums"`
+	Artists    *ArtistPage    `json:"artists"`
+	Playlists  *PlaylistPage  `json:"playlists"`
+	Shows      *ShowPage      `json:"shows"`
+	Episodes   *EpisodePage   `json:"episodes"`
+	Audiobooks *AudiobookPage `json:"audiobooks"`
+}
+
+// Search searches the catalog for one or more types at once
+// opts may be nil to use the defaults
+func (c *Client) Search(ctx context.Context, query string, types []SearchType, opts *SearchOptions) (*SearchResult, error) {
+	if query == "" {
+		return nil, fmt.Errorf("Missing required parameter: query")
+	}
+
+	if len(types) == 0 {
+		return nil, fmt.Errorf("Missing required parameter: types")
+	}
+
+	typs := make([]string, len(types))
+	for i, t := range types {
+		typs[i] = string(t)
+	}
+
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+
+	vals := url.Values{}
+	if opts.Market != "" {
+		vals.Add("market", opts.Market)
+	}
+
+	if opts.Limit != 0 {
+		addPaging(vals, opts.Limit, opts.Offset)
+	} else if opts.Offset > 0 {
+		vals.Add("offset", strconv.Itoa(opts.Offset))
+	}
+
+	if opts.IncludeExternalAudio {
+		vals.Add("include_external", "audio")
+	}
+
+	endpoint := EndpointSearch(url.QueryEscape(query), url.QueryEscape(strings.Join(typs, ",")))
+	res, err := c.requestContext(ctx, "GET", withValues(endpoint, vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SearchResult{}
+	err = unmarshal(res, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
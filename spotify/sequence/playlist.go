@@ -0,0 +1,146 @@
+package sequence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Source is what FromPlaylist reads a playlist and the audio features of its tracks from
+type Source interface {
+	spotify.Playlists
+	spotify.Catalog
+}
+
+// FromPlaylist fetches all the items of a playlist and the audio features of its tracks
+// The tracks are in playlist order, so a Result can be applied to the playlist with Apply.
+func FromPlaylist(c Source, uid, pid spotify.ID) ([]Track, error) {
+	items, err := c.AllPlaylistTracks(uid, pid)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]Track, len(items))
+	index := make(map[spotify.ID][]int)
+	var ids []spotify.ID
+	for i, item := range items {
+		if item.Track == nil {
+			continue
+		}
+
+		if t := item.Track.Track; t != nil {
+			tracks[i].URI = t.URI
+			if !t.IsLocal && t.ID != "" {
+				if _, ok := index[t.ID]; !ok {
+					ids = append(ids, t.ID)
+				}
+				index[t.ID] = append(index[t.ID], i)
+			}
+		} else if e := item.Track.Episode; e != nil {
+			tracks[i].URI = e.URI
+		}
+	}
+
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > 100 {
+			n = 100
+		}
+
+		feats, err := c.GetAudioFeatures(ids[:n])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range feats {
+			if f == nil {
+				continue
+			}
+
+			for _, i := range index[spotify.ID(f.ID)] {
+				tracks[i].Features = f
+			}
+		}
+		ids = ids[n:]
+	}
+	return tracks, nil
+}
+
+// Apply reorders the playlist into the order of the result with one reorder request per moved item,
+// which keeps when and by whom the items were added. The playlist must still be in the order the
+// result was computed from, snapshotID is optional and guards against concurrent changes.
+// The snapshot ID of the reordered playlist is returned.
+func Apply(c spotify.Playlists, uid, pid spotify.ID, res *Result, snapshotID string) (string, error) {
+	current := make([]int, len(res.Order))
+	for i := range current {
+		current[i] = i
+	}
+
+	for pos, want := range res.Order {
+		from := pos
+		for current[from] != want {
+			from++
+		}
+
+		if from == pos {
+			continue
+		}
+
+		snap, err := c.ReorderPlaylistTracks(uid, pid, from, pos, 1, snapshotID)
+		if err != nil {
+			return snapshotID, err
+		}
+		snapshotID = snap
+
+		copy(current[pos+1:from+1], current[pos:from])
+		current[pos] = want
+	}
+	return snapshotID, nil
+}
+
+// ApplyReplace replaces the items of the playlist with the tracks of the result in their new order
+// It needs fewer requests than Apply, but the added at dates and users are lost. Unavailable items and
+// local files can't be added through the API, they're left out and named in the returned error after the
+// rest of the playlist is replaced.
+func ApplyReplace(c spotify.Playlists, uid, pid spotify.ID, res *Result) (string, error) {
+	var uris []spotify.URI
+	var left []string
+	for i, t := range res.Tracks {
+		switch {
+		case t.URI == "":
+			left = append(left, fmt.Sprintf("unavailable item at %d", res.Order[i]))
+		case strings.HasPrefix(string(t.URI), "spotify:local:"):
+			left = append(left, fmt.Sprintf("local file %s at %d", t.URI, res.Order[i]))
+		default:
+			uris = append(uris, t.URI)
+		}
+	}
+
+	n := len(uris)
+	if n > spotify.MaxPlaylistItems {
+		n = spotify.MaxPlaylistItems
+	}
+
+	snap, err := c.ReplacePlaylistTracks(uid, pid, uris[:n])
+	if err != nil {
+		return "", err
+	}
+
+	for uris = uris[n:]; len(uris) > 0; uris = uris[n:] {
+		n = len(uris)
+		if n > spotify.MaxPlaylistItems {
+			n = spotify.MaxPlaylistItems
+		}
+
+		snap, err = c.AddTracksToPlaylist(uid, pid, uris[:n], -1)
+		if err != nil {
+			return snap, err
+		}
+	}
+
+	if len(left) > 0 {
+		return snap, fmt.Errorf("Left out %d items that can't be added: %s", len(left), strings.Join(left, ", "))
+	}
+	return snap, nil
+}
@@ -0,0 +1,131 @@
+package sequence
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/mock"
+	"github.com/Krognol/go-spotify/spotify/spotifytest"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []int
+	}{
+		{"unchanged", []int{0, 1, 2, 3, 4, 5, 6}},
+		{"reversed", []int{6, 5, 4, 3, 2, 1, 0}},
+		{"last to first", []int{6, 0, 1, 2, 3, 4, 5}},
+		{"first to last", []int{1, 2, 3, 4, 5, 6, 0}},
+		{"shuffled", []int{3, 0, 6, 2, 5, 1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := spotifytest.Seed()
+			srv := spotifytest.NewServer(d)
+			defer srv.Close()
+
+			pl := d.Playlists[0]
+			tracks, err := FromPlaylist(srv.Client(), d.User.ID, pl.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tracks) != len(tt.order) {
+				t.Fatalf("got %d tracks, want %d", len(tracks), len(tt.order))
+			}
+			for i, tr := range tracks {
+				if tr.URI != pl.Tracks[i].URI(spotify.KindTrack) || tr.Features == nil {
+					t.Fatalf("track %d is %s with features %v", i, tr.URI, tr.Features)
+				}
+			}
+
+			_, snapshot := srv.PlaylistTracks(pl.ID)
+			res := &Result{Order: tt.order}
+			if _, err = Apply(srv.Client(), d.User.ID, pl.ID, res, snapshot); err != nil {
+				t.Fatal(err)
+			}
+
+			want := make([]spotify.ID, len(tt.order))
+			for pos, i := range tt.order {
+				want[pos] = pl.Tracks[i]
+			}
+
+			if got, _ := srv.PlaylistTracks(pl.ID); !reflect.DeepEqual(got, want) {
+				t.Errorf("got playlist %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyReplace(t *testing.T) {
+	many := make([]Track, 250)
+	for i := range many {
+		many[i].URI = spotify.ID(strings.Repeat("a", i%5+1)).URI(spotify.KindTrack)
+	}
+
+	tests := []struct {
+		name     string
+		tracks   []Track
+		requests int
+		err      string
+	}{
+		{"tracks and episodes", []Track{{URI: "spotify:track:b"}, {URI: "spotify:episode:a"}, {URI: "spotify:track:a"}}, 1, ""},
+		{"in batches", many, 3, ""},
+		{"unavailable", []Track{{URI: "spotify:track:b"}, {}, {URI: "spotify:track:a"}}, 1, "unavailable item at 1"},
+		{"local file", []Track{{URI: "spotify:local:::song:180"}, {URI: "spotify:track:a"}}, 1, "local file spotify:local:::song:180 at 0"},
+		{"nothing left", []Track{{}}, 1, "Left out 1 items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []spotify.URI
+			requests := 0
+			c := &mock.Playlists{
+				ReplacePlaylistTracksFunc: func(uid, pid spotify.ID, uris []spotify.URI) (string, error) {
+					requests++
+					got = append([]spotify.URI{}, uris...)
+					return "replaced", nil
+				},
+				AddTracksToPlaylistFunc: func(uid, pid spotify.ID, uris []spotify.URI, position int) (string, error) {
+					requests++
+					if position != -1 {
+						t.Errorf("added at %d, want appended", position)
+					}
+					got = append(got, uris...)
+					return "added", nil
+				},
+			}
+
+			res := &Result{Tracks: tt.tracks}
+			var want []spotify.URI
+			for i, tr := range tt.tracks {
+				res.Order = append(res.Order, i)
+				if tr.URI != "" && !strings.HasPrefix(string(tr.URI), "spotify:local:") {
+					want = append(want, tr.URI)
+				}
+			}
+
+			snap, err := ApplyReplace(c, "user", "playlist", res)
+			if tt.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("got error %v, want one naming %q", err, tt.err)
+			}
+
+			if requests != tt.requests {
+				t.Errorf("made %d requests, want %d", requests, tt.requests)
+			}
+			if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Errorf("got playlist %v, want %v", got, want)
+			}
+			if snap == "" {
+				t.Errorf("got no snapshot")
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+// Package sequence reorders playlists for flow using the audio features of their tracks
+package sequence
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/harmonic"
+)
+
+// Track is a playlist item to sequence, Features is nil for items without audio features
+// like episodes and local files, they only add the cost of their position in the energy curve
+type Track struct {
+	URI      spotify.URI
+	Features *spotify.AudioFeatures
+}
+
+// Curve returns the target energy, 0-1, at a relative position in the playlist, 0-1
+type Curve func(pos float64) float64
+
+// Flat keeps the energy at one level
+func Flat(level float64) Curve {
+	return func(float64) float64 { return level }
+}
+
+// Ramp moves the energy linearly from one level to another
+func Ramp(from, to float64) Curve {
+	return func(pos float64) float64 { return from + (to-from)*pos }
+}
+
+// Arc builds the energy up to a peak in the middle and brings it back down
+func Arc(start, peak, end float64) Curve {
+	return func(pos float64) float64 {
+		if pos < 0.5 {
+			return start + (peak-start)*pos*2
+		}
+		return peak + (end-peak)*(pos-0.5)*2
+	}
+}
+
+// Options are the objectives of the sequencer
+type Options struct {
+	// EnergyCurve is the shape the energy should follow, nil ignores energy
+	EnergyCurve Curve
+
+	// MaxBPMJump is the largest tempo change in percent between two tracks, 0 is no limit
+	// Half and double time count as the same tempo.
+	MaxBPMJump float32
+
+	// Harmonic prefers transitions between compatible keys
+	Harmonic bool
+
+	// The weights of the objectives, 0 defaults to 1
+	EnergyWeight float64
+	TempoWeight  float64
+	KeyWeight    float64
+
+	// KeepFirst keeps the first track in place, for playlists with a fixed opener
+	KeepFirst bool
+
+	// Iterations of the local search after the greedy construction, defaults to 200 per track
+	// and at most DefaultMaxIterations
+	Iterations int
+
+	// Seed makes the local search deterministic
+	Seed int64
+}
+
+// Result is the sequenced playlist
+// Order holds the indexes of the input tracks in their new order.
+type Result struct {
+	Order  []int
+	Tracks []Track
+	Cost   float64
+}
+
+// DefaultMaxIterations caps the default iterations, so large playlists don't take minutes
+const DefaultMaxIterations = 20000
+
+// jumpPenalty is added for every transition over MaxBPMJump, so it's only broken when nothing else fits
+const jumpPenalty = 100
+
+type solver struct {
+	tracks []Track
+	opts   Options
+}
+
+// Sequence orders the tracks under the objectives
+// It builds a greedy ordering first and improves it with a local search of segment reversals and swaps.
+func Sequence(tracks []Track, opts *Options) *Result {
+	s := &solver{tracks: tracks}
+	if opts != nil {
+		s.opts = *opts
+	}
+
+	for _, w := range []*float64{&s.opts.EnergyWeight, &s.opts.TempoWeight, &s.opts.KeyWeight} {
+		if *w == 0 {
+			*w = 1
+		}
+	}
+
+	if s.opts.Iterations <= 0 {
+		s.opts.Iterations = 200 * len(tracks)
+		if s.opts.Iterations > DefaultMaxIterations {
+			s.opts.Iterations = DefaultMaxIterations
+		}
+	}
+
+	order := s.greedy()
+	order = s.improve(order)
+
+	res := &Result{Order: order, Cost: s.cost(order)}
+	for _, i := range order {
+		res.Tracks = append(res.Tracks, tracks[i])
+	}
+	return res
+}
+
+// target returns the target energy at the position
+func (s *solver) target(pos, n int) float64 {
+	if n < 2 {
+		return s.opts.EnergyCurve(0)
+	}
+	return s.opts.EnergyCurve(float64(pos) / float64(n-1))
+}
+
+// placement is the cost of the track at the position
+func (s *solver) placement(track, pos int) float64 {
+	f := s.tracks[track].Features
+	if s.opts.EnergyCurve == nil || f == nil {
+		return 0
+	}
+
+	d := float64(f.Energy) - s.target(pos, len(s.tracks))
+	return s.opts.EnergyWeight * d * d * 10
+}
+
+// transition is the cost of going from one track to the next
+func (s *solver) transition(from, to int) float64 {
+	a, b := s.tracks[from].Features, s.tracks[to].Features
+	if a == nil || b == nil {
+		return 0
+	}
+
+	var cost float64
+	diff := float64(harmonic.BPMDiff(a.Tempo, b.Tempo))
+	if math.IsInf(diff, 1) {
+		diff = 0
+	}
+
+	if s.opts.MaxBPMJump > 0 {
+		cost += s.opts.TempoWeight * diff / float64(s.opts.MaxBPMJump)
+		if diff > float64(s.opts.MaxBPMJump) {
+			cost += jumpPenalty
+		}
+	} else {
+		cost += s.opts.TempoWeight * diff / 10
+	}
+
+	if s.opts.Harmonic {
+		ka, okA := harmonic.FromFeatures(a)
+		kb, okB := harmonic.FromFeatures(b)
+		if okA && okB {
+			cost += s.opts.KeyWeight * float64(harmonic.Distance(ka, kb))
+		} else {
+			cost += s.opts.KeyWeight * 3
+		}
+	}
+	return cost
+}
+
+func (s *solver) cost(order []int) float64 {
+	var cost float64
+	for pos, t := range order {
+		cost += s.placement(t, pos)
+		if pos > 0 {
+			cost += s.transition(order[pos-1], t)
+		}
+	}
+	return cost
+}
+
+// greedy starts with the track that fits the start of the curve best and keeps appending the cheapest next track
+func (s *solver) greedy() []int {
+	n := len(s.tracks)
+	if n == 0 {
+		return []int{}
+	}
+
+	used := make([]bool, n)
+	order := make([]int, 0, n)
+
+	first := 0
+	if !s.opts.KeepFirst {
+		best := math.Inf(1)
+		for i := range s.tracks {
+			if c := s.placement(i, 0); c < best {
+				best, first = c, i
+			}
+		}
+	}
+	order = append(order, first)
+	used[first] = true
+
+	for pos := 1; pos < n; pos++ {
+		prev := order[pos-1]
+		next, best := -1, math.Inf(1)
+		for i := range s.tracks {
+			if used[i] {
+				continue
+			}
+
+			if c := s.transition(prev, i) + s.placement(i, pos); c < best {
+				best, next = c, i
+			}
+		}
+		order = append(order, next)
+		used[next] = true
+	}
+	return order
+}
+
+// span is the part of the cost of the order that the positions i to j take part in
+func (s *solver) span(order []int, i, j int) float64 {
+	var cost float64
+	for pos := i; pos <= j; pos++ {
+		cost += s.placement(order[pos], pos)
+	}
+
+	for pos := i; pos <= j+1 && pos < len(order); pos++ {
+		if pos > 0 {
+			cost += s.transition(order[pos-1], order[pos])
+		}
+	}
+	return cost
+}
+
+// around is the part of the cost of the order that the position takes part in
+func (s *solver) around(order []int, pos int) float64 {
+	return s.span(order, pos, pos)
+}
+
+// improve runs a hill climbing local search over random segment reversals and swaps
+// Moves are made in place and scored by the change in the cost of the positions they touch,
+// a swap costs O(1) and a reversal the length of its segment.
+func (s *solver) improve(order []int) []int {
+	n := len(order)
+	lo := 0
+	if s.opts.KeepFirst {
+		lo = 1
+	}
+
+	if n-lo < 2 {
+		return order
+	}
+
+	rnd := rand.New(rand.NewSource(s.opts.Seed))
+	reverse := func(i, j int) {
+		for ; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	for it := 0; it < s.opts.Iterations; it++ {
+		i := lo + rnd.Intn(n-lo)
+		j := lo + rnd.Intn(n-lo)
+		if i == j {
+			continue
+		}
+
+		if i > j {
+			i, j = j, i
+		}
+
+		// swapping neighbours is reversing them, the transition between them counts once
+		if rnd.Intn(2) == 0 || j == i+1 {
+			before := s.span(order, i, j)
+			reverse(i, j)
+			if s.span(order, i, j) >= before {
+				reverse(i, j)
+			}
+		} else {
+			before := s.around(order, i) + s.around(order, j)
+			order[i], order[j] = order[j], order[i]
+			if s.around(order, i)+s.around(order, j) >= before {
+				order[i], order[j] = order[j], order[i]
+			}
+		}
+	}
+	return order
+}
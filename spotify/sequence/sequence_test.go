@@ -0,0 +1,141 @@
+package sequence
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/harmonic"
+)
+
+// features is a track with the energy, tempo and major key of a pitch class
+func features(energy, tempo float32, pitch int) Track {
+	return Track{Features: &spotify.AudioFeatures{Energy: energy, Tempo: tempo, Key: pitch, Mode: 1}}
+}
+
+// shuffled returns the tracks in a random order that is the same for every run
+func shuffled(tracks []Track) []Track {
+	out := append([]Track(nil), tracks...)
+	rand.New(rand.NewSource(7)).Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+func TestSequence(t *testing.T) {
+	var ramp, fifths, tempos []Track
+	for i := 0; i < 12; i++ {
+		ramp = append(ramp, features(float32(i)/11, 120, 0))
+		tempos = append(tempos, features(0.5, 100+4*float32(i), 0))
+	}
+	// a chain of major keys a fifth apart, neighbours on the Camelot wheel
+	for _, pitch := range []int{0, 7, 2, 9, 4, 11} {
+		fifths = append(fifths, features(0.5, 120, pitch))
+	}
+
+	tests := []struct {
+		name   string
+		tracks []Track
+		opts   *Options
+		check  func(t *testing.T, res *Result)
+	}{
+		{"follows the energy ramp", shuffled(ramp), &Options{EnergyCurve: Ramp(0, 1)}, func(t *testing.T, res *Result) {
+			for i := 1; i < len(res.Tracks); i++ {
+				if res.Tracks[i].Features.Energy < res.Tracks[i-1].Features.Energy {
+					t.Errorf("energy drops from %v to %v at %d", res.Tracks[i-1].Features.Energy, res.Tracks[i].Features.Energy, i)
+				}
+			}
+		}},
+		{"follows a falling ramp", shuffled(ramp), &Options{EnergyCurve: Ramp(1, 0)}, func(t *testing.T, res *Result) {
+			for i := 1; i < len(res.Tracks); i++ {
+				if res.Tracks[i].Features.Energy > res.Tracks[i-1].Features.Energy {
+					t.Errorf("energy rises from %v to %v at %d", res.Tracks[i-1].Features.Energy, res.Tracks[i].Features.Energy, i)
+				}
+			}
+		}},
+		{"harmonic transitions", shuffled(fifths), &Options{Harmonic: true}, func(t *testing.T, res *Result) {
+			for i := 1; i < len(res.Tracks); i++ {
+				a, _ := harmonic.FromFeatures(res.Tracks[i-1].Features)
+				b, _ := harmonic.FromFeatures(res.Tracks[i].Features)
+				if d := harmonic.Distance(a, b); d > 1 {
+					t.Errorf("%s to %s at %d is %d apart", a.Camelot(), b.Camelot(), i, d)
+				}
+			}
+		}},
+		{"tempo jumps", shuffled(tempos), &Options{MaxBPMJump: 5}, func(t *testing.T, res *Result) {
+			for i := 1; i < len(res.Tracks); i++ {
+				a, b := res.Tracks[i-1].Features.Tempo, res.Tracks[i].Features.Tempo
+				if d := harmonic.BPMDiff(a, b); d > 5 {
+					t.Errorf("%v to %v BPM at %d is a %v%% jump", a, b, i, d)
+				}
+			}
+		}},
+		{"keeps the first", shuffled(ramp), &Options{EnergyCurve: Ramp(0, 1), KeepFirst: true}, func(t *testing.T, res *Result) {
+			if res.Order[0] != 0 {
+				t.Errorf("first is %d, want 0", res.Order[0])
+			}
+		}},
+		{"items without features", append(shuffled(fifths), Track{URI: "spotify:episode:a"}), &Options{Harmonic: true}, func(t *testing.T, res *Result) {
+			if len(res.Order) != len(fifths)+1 {
+				t.Errorf("got %d tracks, want %d", len(res.Order), len(fifths)+1)
+			}
+		}},
+		{"empty", nil, nil, func(t *testing.T, res *Result) {
+			if len(res.Order) != 0 || res.Cost != 0 {
+				t.Errorf("got order %v with cost %v", res.Order, res.Cost)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Sequence(tt.tracks, tt.opts)
+
+			seen := make([]bool, len(tt.tracks))
+			for pos, i := range res.Order {
+				if seen[i] {
+					t.Fatalf("track %d is in the order twice", i)
+				}
+				seen[i] = true
+
+				if res.Tracks[pos] != tt.tracks[i] {
+					t.Errorf("track at %d isn't input track %d", pos, i)
+				}
+			}
+			if len(res.Order) != len(tt.tracks) {
+				t.Fatalf("got %d tracks, want %d", len(res.Order), len(tt.tracks))
+			}
+
+			tt.check(t, res)
+		})
+	}
+}
+
+func TestImproveKeepsTheCost(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tracks := make([]Track, 40)
+	for i := range tracks {
+		tracks[i] = features(rnd.Float32(), 80+rnd.Float32()*80, rnd.Intn(12))
+	}
+
+	opts := &Options{EnergyCurve: Arc(0.2, 0.9, 0.3), MaxBPMJump: 8, Harmonic: true, KeepFirst: true}
+	s := &solver{tracks: tracks, opts: *opts}
+	s.opts.EnergyWeight, s.opts.TempoWeight, s.opts.KeyWeight = 1, 1, 1
+	s.opts.Iterations = 5000
+
+	greedy := s.greedy()
+	before := s.cost(greedy)
+	order := s.improve(append([]int(nil), greedy...))
+
+	// the moves are scored by their local change, the full cost must agree that they only improved
+	if after := s.cost(order); after > before {
+		t.Errorf("cost went from %v to %v", before, after)
+	}
+	if order[0] != greedy[0] {
+		t.Errorf("first moved from %d to %d", greedy[0], order[0])
+	}
+
+	res := Sequence(tracks, opts)
+	if again := Sequence(tracks, opts); !reflect.DeepEqual(res.Order, again.Order) {
+		t.Errorf("same seed gave %v and %v", res.Order, again.Order)
+	}
+}
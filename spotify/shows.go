@@ -0,0 +1,215 @@
+package spotify
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+// GetShow gets a podcast show
+// Market: optional ISO 3166-1 alpha-2 country code, episodes not available in it are left out
+func (c *Client) GetShow(id ID, market string) (*Show, error) {
+	res, err := c.request("GET", withValues(EndpointGetShow(string(id)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	show := &Show{}
+	err = unmarshal(res, show)
+	if err != nil {
+		return nil, err
+	}
+	return show, nil
+}
+
+// GetShows gets several podcast shows, at most 50
+func (c *Client) GetShows(ids []ID, market string) ([]*Show, error) {
+	res, err := c.request("GET", withValues(EndpointGetShows(idStrings(ids)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Shows []*Show `json:"shows"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Shows, nil
+}
+
+// GetShowEpisodes gets a page of the episodes of a show
+// The items of the page are Episodes
+func (c *Client) GetShowEpisodes(id ID, market string, limit, offset int) (*Paging, error) {
+	vals := marketValues(market)
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetShowEpisodes(string(id)), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// GetEpisode gets a podcast episode
+// The resume point is only set when the request is made on behalf of a user
+func (c *Client) GetEpisode(id ID, market string) (*Episode, error) {
+	res, err := c.request("GET", withValues(EndpointGetEpisode(string(id)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	episode := &Episode{}
+	err = unmarshal(res, episode)
+	if err != nil {
+		return nil, err
+	}
+	return episode, nil
+}
+
+// GetEpisodes gets several podcast episodes, at most 50
+func (c *Client) GetEpisodes(ids []ID, market string) ([]*Episode, error) {
+	res, err := c.request("GET", withValues(EndpointGetEpisodes(idStrings(ids)), marketValues(market)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var temp struct {
+		Episodes []*Episode `json:"episodes"`
+	}
+
+	err = unmarshal(res, &temp)
+	if err != nil {
+		return nil, err
+	}
+	return temp.Episodes, nil
+}
+
+// GetSavedShows gets a page of the shows saved in the user's library
+// The items of the page are SavedShows
+func (c *Client) GetSavedShows(limit, offset int) (*Paging, error) {
+	vals := url.Values{}
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetSavedShows(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// AllSavedShows gets all the shows saved in the user's library, following the paging
+func (c *Client) AllSavedShows() ([]*SavedShow, error) {
+	var shows []*SavedShow
+	for offset := 0; ; {
+		page, err := c.GetSavedShows(50, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []*SavedShow
+		if err = json.Unmarshal(page.Items, &items); err != nil {
+			return nil, err
+		}
+		shows = append(shows, items...)
+
+		offset += len(items)
+		if page.Next == "" || len(items) == 0 {
+			return shows, nil
+		}
+	}
+}
+
+func (c *Client) SaveShows(ids []ID) error {
+	res, err := c.request("PUT", EndpointSaveShows(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+func (c *Client) RemoveSavedShows(ids []ID) error {
+	res, err := c.request("DELETE", EndpointRemoveSavedShows(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// HasShowsSaved checks if the shows are saved in the user's library
+func (c *Client) HasShowsSaved(ids []ID) ([]bool, error) {
+	res, err := c.request("GET", EndpointHasShowsSaved(idStrings(ids)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var bools []bool
+	err = unmarshal(res, &bools)
+	if err != nil {
+		return nil, err
+	}
+	return bools, nil
+}
+
+// GetSavedEpisodes gets a page of the episodes saved in the user's library
+// The items of the page are SavedEpisodes
+func (c *Client) GetSavedEpisodes(market string, limit, offset int) (*Paging, error) {
+	vals := marketValues(market)
+	addPaging(vals, limit, offset)
+
+	res, err := c.request("GET", withValues(EndpointGetSavedEpisodes(), vals), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Paging{}
+	err = unmarshal(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (c *Client) SaveEpisodes(ids []ID) error {
+	res, err := c.request("PUT", EndpointSaveEpisodes(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+func (c *Client) RemoveSavedEpisodes(ids []ID) error {
+	res, err := c.request("DELETE", EndpointRemoveSavedEpisodes(idStrings(ids)), nil)
+	if err != nil {
+		return err
+	}
+	return discard(res)
+}
+
+// HasEpisodesSaved checks if the episodes are saved in the user's library
+func (c *Client) HasEpisodesSaved(ids []ID) ([]bool, error) {
+	res, err := c.request("GET", EndpointHasEpisodesSaved(idStrings(ids)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var bools []bool
+	err = unmarshal(res, &bools)
+	if err != nil {
+		return nil, err
+	}
+	return bools, nil
+}
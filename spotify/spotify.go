@@ -1,6 +1,8 @@
 package spotify
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -9,24 +11,73 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Client struct {
 	auth         *auth
+	lists        listCache
+	userToken    bool
 	ClientID     string
 	ClientSecret string
+
+	// ListTTL is how long the available markets and genre seeds are cached, defaults to 24 hours
+	ListTTL time.Duration
+
+	// ValidateCountry makes the browse methods check their country parameter
+	// against the available markets before making a request
+	ValidateCountry bool
+
+	// Cache keeps GET responses and revalidates them with their ETag, nil disables caching
+	Cache Cache
+
+	// Coalesce makes concurrent identical GET requests share one request and its response
+	Coalesce bool
+
+	// CoalesceTimeout limits a shared request, which outlives the context of the caller that started it,
+	// defaults to 30 seconds
+	CoalesceTimeout time.Duration
+
+	// BatchWindow merges the GetAlbum, GetArtist and GetTrack calls arriving within it
+	// into one request for several IDs, zero disables batching
+	BatchWindow time.Duration
+
+	// HTTPClient sends the requests, http.DefaultClient is used when it's nil
+	HTTPClient *http.Client
+
+	flights *flightGroup
+	batches *batchGroup
 }
 
 func New(clientid, clientsecret string) *Client {
-	return &Client{auth: &auth{}, ClientID: clientid, ClientSecret: clientsecret}
+	c := &Client{auth: &auth{}, ClientID: clientid, ClientSecret: clientsecret}
+	c.init()
+	return c
+}
+
+// NewWithToken makes a client that acts on behalf of a user with an access token from the authorization code flow
+// The 'Me' endpoints, the library and playlist changes need one, the client credentials of New can't access them.
+// The token isn't refreshed, make a new client when it expires.
+func NewWithToken(accessToken string) *Client {
+	c := &Client{auth: &auth{AccessToken: accessToken, TokenType: "Bearer"}, userToken: true}
+	c.init()
+	return c
+}
+
+func (c *Client) init() {
+	c.flights = &flightGroup{flights: make(map[string]*flight)}
+	c.batches = &batchGroup{batches: make(map[string]*batch)}
 }
 
 func (c *Client) authorize() error {
-	httpc := &http.Client{}
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	httpc := c.HTTPClient
+	if httpc == nil {
+		httpc = &http.Client{}
+		tr := &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+		httpc.Transport = tr
 	}
-	httpc.Transport = tr
 	body := strings.NewReader(`grant_type=client_credentials`)
 	req, _ := http.NewRequest("POST", "https://accounts.spotify.com/api/token", body)
 	req.Header.Add("cache-control", "no-cache")
@@ -46,28 +97,137 @@ func (c *Client) authorize() error {
 }
 
 func (c *Client) request(method, url string, body io.Reader) (*http.Response, error) {
-	req, _ := http.NewRequest(method, url, body)
+	return c.requestContext(context.Background(), method, url, body)
+}
+
+func (c *Client) requestContext(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
+	if c.Coalesce && c.flights != nil && method == "GET" {
+		return c.sharedRequest(ctx, url)
+	}
 
-	err := c.authorize()
+	if c.Cache != nil && method == "GET" {
+		return c.cachedRequest(ctx, url)
+	}
+
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.do(req)
+}
+
+// do authorizes and sends a request
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if !c.userToken {
+		err := c.authorize()
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	req.Header.Add("Authorization", "Bearer "+c.auth.AccessToken)
+	if c.HTTPClient != nil {
+		return c.HTTPClient.Do(req)
+	}
 	return http.DefaultClient.Do(req)
 }
 
+// jsonBody encodes v as a request body
+func jsonBody(v interface{}) (io.Reader, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// checkResponse returns the error of an unsuccessful response
+// The API wraps errors as {"error": {"status": 404, "message": "..."}}
+func checkResponse(r *http.Response) error {
+	if r.StatusCode < 400 {
+		return nil
+	}
+
+	var temp struct {
+		Error *SpotifyError `json:"error"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&temp); err != nil || temp.Error == nil {
+		return &SpotifyError{Status: r.StatusCode, Message: http.StatusText(r.StatusCode)}
+	}
+
+	if temp.Error.Status == 0 {
+		temp.Error.Status = r.StatusCode
+	}
+	return temp.Error
+}
+
 func unmarshal(r *http.Response, v interface{}) error {
 	defer r.Body.Close()
+	if err := checkResponse(r); err != nil {
+		return err
+	}
 	return json.NewDecoder(r.Body).Decode(v)
 }
 
-func GetAlbum(id string) (*Album, error) {
-	res, err := http.Get(EndpointGetAlbum(id))
+// discard checks a response whose body isn't needed and closes it
+func discard(r *http.Response) error {
+	defer r.Body.Close()
+	return checkResponse(r)
+}
+
+// withValues appends the encoded query values to an endpoint, which may already have a query string
+func withValues(endpoint string, vals url.Values) string {
+	if len(vals) == 0 {
+		return endpoint
+	}
+
+	if strings.Contains(endpoint, "?") {
+		return endpoint + "&" + vals.Encode()
+	}
+	return endpoint + "?" + vals.Encode()
+}
+
+// addPaging adds the limit and offset parameters, clamping the limit to 1-50
+func addPaging(vals url.Values, limit, offset int) {
+	if limit < 1 {
+		vals.Add("limit", "20")
+	} else if limit > 50 {
+		vals.Add("limit", "50")
+	} else {
+		vals.Add("limit", strconv.Itoa(limit))
+	}
+
+	if offset < 0 {
+		vals.Add("offset", "0")
+	} else {
+		vals.Add("offset", strconv.Itoa(offset))
+	}
+}
+
+// marketValues returns the query values for an optional market
+func marketValues(market string) url.Values {
+	vals := url.Values{}
+	if market != "" {
+		vals.Add("market", market)
+	}
+	return vals
+}
+
+// GetAlbum is the package level version of Client.GetAlbum
+//
+// Deprecated: the API rejects requests without a token, use Client.GetAlbum instead
+func GetAlbum(id ID) (*FullAlbum, error) {
+	res, err := http.Get(EndpointGetAlbum(string(id)))
 	if err != nil {
 		return nil, err
 	}
-	album := &Album{}
+	album := &FullAlbum{}
 	err = unmarshal(res, album)
 	if err != nil {
 		return nil, err
@@ -75,13 +235,16 @@ func GetAlbum(id string) (*Album, error) {
 	return album, nil
 }
 
-func GetAlbums(ids []string) ([]*Album, error) {
-	res, err := http.Get(EndpointGetAlbums(ids))
+// GetAlbums is the package level version of Client.GetAlbums
+//
+// Deprecated: the API rejects requests without a token, use Client.GetAlbums instead
+func GetAlbums(ids []ID) ([]*FullAlbum, error) {
+	res, err := http.Get(EndpointGetAlbums(idStrings(ids)))
 	if err != nil {
 		return nil, err
 	}
 
-	var albums []*Album
+	var albums []*FullAlbum
 	err = unmarshal(res, albums)
 	if err != nil {
 		return nil, err
@@ -89,8 +252,12 @@ func GetAlbums(ids []string) ([]*Album, error) {
 	return albums, nil
 }
 
-func GetAlbumTracks(id string) (*Paging, error) {
-	res, err := http.Get(EndpointGetAlbumTracks(id))
+// GetAlbumTracks gets a page of the tracks of an album
+// The items of the page are SimpleTracks
+//
+// Deprecated: the API rejects requests without a token, use Client.GetAlbumTracks instead
+func GetAlbumTracks(id ID) (*Paging, error) {
+	res, err := http.Get(EndpointGetAlbumTracks(string(id)))
 
 	if err != nil {
 		return nil, err
@@ -106,13 +273,16 @@ func GetAlbumTracks(id string) (*Paging, error) {
 	return page, nil
 }
 
-func GetArtist(id string) (*Artist, error) {
-	res, err := http.Get(EndpointGetArtist(id))
+// GetArtist is the package level version of Client.GetArtist
+//
+// Deprecated: the API rejects requests without a token, use Client.GetArtist instead
+func GetArtist(id ID) (*FullArtist, error) {
+	res, err := http.Get(EndpointGetArtist(string(id)))
 	if err != nil {
 		return nil, err
 	}
 
-	a := &Artist{}
+	a := &FullArtist{}
 
 	err = unmarshal(res, a)
 
@@ -122,13 +292,16 @@ func GetArtist(id string) (*Artist, error) {
 	return a, nil
 }
 
-func GetArtists(ids []string) ([]*Artist, error) {
-	res, err := http.Get(EndpointGetArtists(ids))
+// GetArtists is the package level version of Client.GetArtists
+//
+// Deprecated: the API rejects requests without a token, use Client.GetArtists instead
+func GetArtists(ids []ID) ([]*FullArtist, error) {
+	res, err := http.Get(EndpointGetArtists(idStrings(ids)))
 	if err != nil {
 		return nil, err
 	}
 
-	var as []*Artist
+	var as []*FullArtist
 	err = unmarshal(res, as)
 
 	if err != nil {
@@ -137,8 +310,12 @@ func GetArtists(ids []string) ([]*Artist, error) {
 	return as, nil
 }
 
-func GetArtistAlbums(id string) (*Paging, error) {
-	res, err := http.Get(EndpointGetArtistAlbums(id))
+// GetArtistAlbums gets a page of the albums of an artist
+// The items of the page are SimpleAlbums
+//
+// Deprecated: the API rejects requests without a token, use Client.GetArtistAlbums instead
+func GetArtistAlbums(id ID) (*Paging, error) {
+	res, err := http.Get(EndpointGetArtistAlbums(string(id)))
 	if err != nil {
 		return nil, err
 	}
@@ -152,13 +329,16 @@ func GetArtistAlbums(id string) (*Paging, error) {
 	return page, nil
 }
 
-func GetArtistTopTracks(id string) ([]*Track, error) {
-	res, err := http.Get(EndpointGetArtistTopTracks(id))
+// GetArtistTopTracks is the package level version of Client.GetArtistTopTracks
+//
+// Deprecated: the API rejects requests without a token, use Client.GetArtistTopTracks instead
+func GetArtistTopTracks(id ID) ([]*FullTrack, error) {
+	res, err := http.Get(EndpointGetArtistTopTracks(string(id)))
 	if err != nil {
 		return nil, err
 	}
 
-	var tracks []*Track
+	var tracks []*FullTrack
 
 	err = unmarshal(res, tracks)
 
@@ -169,13 +349,16 @@ func GetArtistTopTracks(id string) ([]*Track, error) {
 	return tracks, nil
 }
 
-func GetRelatedArtists(id string) ([]*Artist, error) {
-	res, err := http.Get(EndpointGetRelatedArtists(id))
+// GetRelatedArtists is the package level version of Client.GetRelatedArtists
+//
+// Deprecated: the API rejects requests without a token, use Client.GetRelatedArtists instead
+func GetRelatedArtists(id ID) ([]*FullArtist, error) {
+	res, err := http.Get(EndpointGetRelatedArtists(string(id)))
 	if err != nil {
 		return nil, err
 	}
 
-	var artists []*Artist
+	var artists []*FullArtist
 
 	err = unmarshal(res, artists)
 	if err != nil {
@@ -184,8 +367,8 @@ func GetRelatedArtists(id string) ([]*Artist, error) {
 	return artists, nil
 }
 
-func (c *Client) GetAudioAnalysis(id string) (*AudioAnalysis, error) {
-	res, err := c.request("GET", EndpointGetAudioAnalysis(id), nil)
+func (c *Client) GetAudioAnalysis(id ID) (*AudioAnalysis, error) {
+	res, err := c.request("GET", EndpointGetAudioAnalysis(string(id)), nil)
 
 	if err != nil {
 		return nil, err
@@ -199,8 +382,8 @@ func (c *Client) GetAudioAnalysis(id string) (*AudioAnalysis, error) {
 	return analysis, nil
 }
 
-func (c *Client) GetAudioFeature(id string) (*AudioFeatures, error) {
-	res, err := c.request("GET", EndpointGetAudioFeature(id), nil)
+func (c *Client) GetAudioFeature(id ID) (*AudioFeatures, error) {
+	res, err := c.request("GET", EndpointGetAudioFeature(string(id)), nil)
 
 	if err != nil {
 		return nil, err
@@ -214,21 +397,25 @@ func (c *Client) GetAudioFeature(id string) (*AudioFeatures, error) {
 	return feat, nil
 }
 
-func (c *Client) GetAudioFeatures(ids []string) ([]*AudioFeatures, error) {
-	res, err := c.request("GET", EndpointGetAudioFeatures(ids), nil)
+// GetAudioFeatures gets the audio features of several tracks, at most 100
+// Tracks without features are nil
+func (c *Client) GetAudioFeatures(ids []ID) ([]*AudioFeatures, error) {
+	res, err := c.request("GET", EndpointGetAudioFeatures(idStrings(ids)), nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var feats []*AudioFeatures
+	var temp struct {
+		Features []*AudioFeatures `json:"audio_features"`
+	}
 
-	err = unmarshal(res, feats)
+	err = unmarshal(res, &temp)
 
 	if err != nil {
 		return nil, err
 	}
-	return feats, nil
+	return temp.Features, nil
 }
 
 // GetFeaturedPlaylists gets the featured playlists
@@ -240,6 +427,10 @@ func (c *Client) GetAudioFeatures(ids []string) ([]*AudioFeatures, error) {
 // Limit: Max amount of items, Default is 20, minimum is 1 and maximum is 50
 // Offset: The index of the first object, default is 0
 func (c *Client) GetFeaturedPlaylists(locale, country, timestamp string, limit, offset int) (*Paging, error) {
+	if err := c.validateCountry(country); err != nil {
+		return nil, err
+	}
+
 	vals := &url.Values{}
 	vals.Add("locale", locale)
 	vals.Add("country", country)
@@ -271,17 +462,23 @@ func (c *Client) GetFeaturedPlaylists(locale, country, timestamp string, limit,
 		return nil, err
 	}
 
-	lists := &Paging{}
+	var temp struct {
+		Playlists *Paging `json:"playlists"`
+	}
 
-	err = unmarshal(res, lists)
+	err = unmarshal(res, &temp)
 
 	if err != nil {
 		return nil, err
 	}
-	return lists, nil
+	return temp.Playlists, nil
 }
 
 func (c *Client) GetNewReleases(country string, limit, offset int) (*Paging, error) {
+	if err := c.validateCountry(country); err != nil {
+		return nil, err
+	}
+
 	vals := url.Values{}
 	if country != "" {
 		vals.Add("country", country)
@@ -306,16 +503,22 @@ func (c *Client) GetNewReleases(country string, limit, offset int) (*Paging, err
 		return nil, err
 	}
 
-	page := &Paging{}
-	err = unmarshal(res, page)
+	var temp struct {
+		Albums *Paging `json:"albums"`
+	}
+	err = unmarshal(res, &temp)
 
 	if err != nil {
 		return nil, err
 	}
-	return page, nil
+	return temp.Albums, nil
 }
 
 func (c *Client) GetCategories(country, locale string, offset, limit int) (*Paging, error) {
+	if err := c.validateCountry(country); err != nil {
+		return nil, err
+	}
+
 	vals := url.Values{}
 	if country != "" {
 		vals.Add("country", country)
@@ -344,16 +547,22 @@ func (c *Client) GetCategories(country, locale string, offset, limit int) (*Pagi
 		return nil, err
 	}
 
-	page := &Paging{}
-	err = unmarshal(res, page)
+	var temp struct {
+		Categories *Paging `json:"categories"`
+	}
+	err = unmarshal(res, &temp)
 
 	if err != nil {
 		return nil, err
 	}
-	return page, nil
+	return temp.Categories, nil
 }
 
 func (c *Client) GetCategory(name, country, locale string) (*Category, error) {
+	if err := c.validateCountry(country); err != nil {
+		return nil, err
+	}
+
 	vals := url.Values{}
 	if country != "" {
 		vals.Add("country", country)
@@ -382,6 +591,10 @@ func (c *Client) GetCategoryPlaylists(name, country string, limit, offset int) (
 		return nil, fmt.Errorf("Missing required parameter: name")
 	}
 
+	if err := c.validateCountry(country); err != nil {
+		return nil, err
+	}
+
 	vals := url.Values{}
 	if country != "" {
 		vals.Add("country", country)
@@ -401,21 +614,25 @@ func (c *Client) GetCategoryPlaylists(name, country string, limit, offset int) (
 		vals.Add("offset", strconv.Itoa(offset))
 	}
 
-	res, err := c.request("GET", EndpointGetCategoryPlaylists(name), nil)
+	res, err := c.request("GET", EndpointGetCategoryPlaylists(name)+"?"+vals.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	page := &Paging{}
+	var temp struct {
+		Playlists *Paging `json:"playlists"`
+	}
 
-	err = unmarshal(res, page)
+	err = unmarshal(res, &temp)
 	if err != nil {
 		return nil, err
 	}
 
-	return page, nil
+	return temp.Playlists, nil
 }
 
+// GetRecommendations gets track recommendations from raw query arguments, e.g: "seed_artists=...", "min_energy=0.4"
+// GetRecommendationsWithOptions builds and validates the arguments from a RecommendationOptions instead
 func (c *Client) GetRecommendations(args ...string) (*Recommendations, error) {
 	res, err := c.request("GET", EndpointGetRecommendations(args...), nil)
 
@@ -423,7 +640,7 @@ func (c *Client) GetRecommendations(args ...string) (*Recommendations, error) {
 		return nil, err
 	}
 
-	rec := &Recommendations{Seeds: make([]*RecommendationSeed, 0), Tracks: make([]*Track, 0)}
+	rec := &Recommendations{Seeds: make([]*RecommendationSeed, 0), Tracks: make([]*FullTrack, 0)}
 
 	err = unmarshal(res, rec)
 
@@ -434,28 +651,28 @@ func (c *Client) GetRecommendations(args ...string) (*Recommendations, error) {
 	return rec, nil
 }
 
-func (c *Client) UserFollowPlaylist(oid, pid string) error {
-	_, err := c.request("PUT", EndpointFollowPlaylist(oid, pid), nil)
+func (c *Client) UserFollowPlaylist(oid, pid ID) error {
+	res, err := c.request("PUT", EndpointFollowPlaylist(string(oid), string(pid)), nil)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return discard(res)
 }
 
-func (c *Client) UserUnfollowPlaylist(oid, pid string) error {
-	_, err := c.request("DELETE", EndpointUnfollowPlaylist(oid, pid), nil)
+func (c *Client) UserUnfollowPlaylist(oid, pid ID) error {
+	res, err := c.request("DELETE", EndpointUnfollowPlaylist(string(oid), string(pid)), nil)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return discard(res)
 }
 
-func (c *Client) UsersFollowsPlaylist(oid, pid string, uid []string) ([]bool, error) {
-	res, err := c.request("GET", EndpointUsersFollowsPlaylist(oid, pid, uid), nil)
+func (c *Client) UsersFollowsPlaylist(oid, pid ID, uid []ID) ([]bool, error) {
+	res, err := c.request("GET", EndpointUsersFollowsPlaylist(string(oid), string(pid), idStrings(uid)), nil)
 
 	if err != nil {
 		return nil, err
@@ -463,7 +680,7 @@ func (c *Client) UsersFollowsPlaylist(oid, pid string, uid []string) ([]bool, er
 
 	var bools = make([]bool, 0)
 
-	err = unmarshal(res, bools)
+	err = unmarshal(res, &bools)
 
 	if err != nil {
 		return nil, err
@@ -472,7 +689,7 @@ func (c *Client) UsersFollowsPlaylist(oid, pid string, uid []string) ([]bool, er
 	return bools, nil
 }
 
-func (c *Client) SearchTrack(query string, offset int) ([]*Track, error) {
+func (c *Client) SearchTrack(query string, offset int) ([]*FullTrack, error) {
 	url := EndpointSearch(url.QueryEscape(query), "track")
 	if offset != 0 {
 		url += fmt.Sprintf("&offset=%d", offset)
@@ -493,13 +710,13 @@ func (c *Client) SearchTrack(query string, offset int) ([]*Track, error) {
 		return nil, err
 	}
 
-	var result []*Track
+	var result []*FullTrack
 	json.Unmarshal(t.Page.Items, &result)
 
 	return result, nil
 }
 
-func (c *Client) SearchAlbum(query string, offset int) ([]*Album, error) {
+func (c *Client) SearchAlbum(query string, offset int) ([]*SimpleAlbum, error) {
 	url := EndpointSearch(url.QueryEscape(query), "album")
 	if offset != 0 {
 		url += fmt.Sprintf("&offset=%d", offset)
@@ -519,12 +736,12 @@ func (c *Client) SearchAlbum(query string, offset int) ([]*Album, error) {
 		return nil, err
 	}
 
-	var result []*Album
+	var result []*SimpleAlbum
 	json.Unmarshal(t.Page.Items, &result)
 	return result, nil
 }
 
-func (c *Client) SearchArtist(query string, offset int) ([]*Artist, error) {
+func (c *Client) SearchArtist(query string, offset int) ([]*FullArtist, error) {
 	url := EndpointSearch(url.QueryEscape(query), "artist")
 	if offset != 0 {
 		url += fmt.Sprintf("&offset=%d", offset)
@@ -538,7 +755,7 @@ func (c *Client) SearchArtist(query string, offset int) ([]*Artist, error) {
 
 		var t temp
 		if err = unmarshal(res, &t); err == nil {
-			var result []*Artist
+			var result []*FullArtist
 			err = json.Unmarshal(t.Page.Items, &result)
 			return result, err
 		}
@@ -546,4 +763,4 @@ func (c *Client) SearchArtist(query string, offset int) ([]*Artist, error) {
 	return nil, err
 }
 
-// the 'Me' endpoints don't work
+// the 'Me' endpoints only work with a client from NewWithToken
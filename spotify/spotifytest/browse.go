@@ -0,0 +1,218 @@
+package spotifytest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// markets are the markets the fake is available in
+var markets = []string{"DE", "FR", "GB", "SE", "US"}
+
+func (s *Server) getMarkets(r *http.Request, args []string) (interface{}, error) {
+	return map[string]interface{}{"markets": markets}, nil
+}
+
+// getFeaturedPlaylists features every public playlist
+func (s *Server) getFeaturedPlaylists(r *http.Request, args []string) (interface{}, error) {
+	var featured []*playlist
+	for _, p := range s.playlists {
+		if p.public {
+			featured = append(featured, p)
+		}
+	}
+
+	p, err := page(r, len(featured), 50, func(i int) interface{} { return s.simplePlaylist(featured[i]) })
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"message": "Featured playlists", "playlists": p}, nil
+}
+
+// getNewReleases lists every album, the latest release first
+func (s *Server) getNewReleases(r *http.Request, args []string) (interface{}, error) {
+	albums := make([]*spotify.SimpleAlbum, len(s.albums))
+	for i, a := range s.albums {
+		albums[i] = &a.SimpleAlbum
+	}
+	sort.SliceStable(albums, func(i, j int) bool { return albums[i].ReleaseDate.After(albums[j].ReleaseDate) })
+
+	p, err := page(r, len(albums), 50, func(i int) interface{} { return albums[i] })
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"albums": p}, nil
+}
+
+func (s *Server) category(id string) *Category {
+	for _, c := range s.categories {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func categoryObject(c *Category) *spotify.Category {
+	return &spotify.Category{
+		Href:  apiBase + "browse/categories/" + c.ID,
+		Icons: []*spotify.Image{},
+		ID:    c.ID,
+		Name:  c.Name,
+	}
+}
+
+func (s *Server) getCategories(r *http.Request, args []string) (interface{}, error) {
+	p, err := page(r, len(s.categories), 50, func(i int) interface{} { return categoryObject(s.categories[i]) })
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"categories": p}, nil
+}
+
+func (s *Server) getCategory(r *http.Request, args []string) (interface{}, error) {
+	c := s.category(args[0])
+	if c == nil {
+		return nil, errorf(http.StatusNotFound, "Specified id doesn't exist")
+	}
+	return categoryObject(c), nil
+}
+
+func (s *Server) getCategoryPlaylists(r *http.Request, args []string) (interface{}, error) {
+	c := s.category(args[0])
+	if c == nil {
+		return nil, errorf(http.StatusNotFound, "Specified id doesn't exist")
+	}
+
+	var listed []*playlist
+	for _, id := range c.Playlists {
+		if p := s.playlist(id); p != nil {
+			listed = append(listed, p)
+		}
+	}
+
+	p, err := page(r, len(listed), 50, func(i int) interface{} { return s.simplePlaylist(listed[i]) })
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"message": c.Name, "playlists": p}, nil
+}
+
+// genres returns the genres of the artists, sorted
+func (s *Server) genres() []string {
+	seen := make(map[string]bool)
+	genres := []string{}
+	for _, a := range s.artists {
+		for _, g := range a.Genres {
+			if !seen[g] {
+				seen[g] = true
+				genres = append(genres, g)
+			}
+		}
+	}
+	sort.Strings(genres)
+	return genres
+}
+
+func (s *Server) getGenreSeeds(r *http.Request, args []string) (interface{}, error) {
+	return map[string]interface{}{"genres": s.genres()}, nil
+}
+
+// seedParam splits a comma separated seed parameter
+func seedParam(r *http.Request, name string) []string {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// getRecommendations recommends the tracks by the seed artists, by the artists of the seed tracks and of
+// albums in the seed genres, in catalog order
+// The tunable attributes are ignored.
+func (s *Server) getRecommendations(r *http.Request, args []string) (interface{}, error) {
+	artists, tracks, genres := seedParam(r, "seed_artists"), seedParam(r, "seed_tracks"), seedParam(r, "seed_genres")
+	switch n := len(artists) + len(tracks) + len(genres); {
+	case n == 0:
+		return nil, errorf(http.StatusBadRequest, "Missing required parameter: at least one seed")
+	case n > spotify.MaxRecommendationSeeds:
+		return nil, errorf(http.StatusBadRequest, "Too many seeds")
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 100 {
+			return nil, errorf(http.StatusBadRequest, "Invalid limit")
+		}
+		limit = n
+	}
+
+	rec := &spotify.Recommendations{Seeds: []*spotify.RecommendationSeed{}, Tracks: []*spotify.FullTrack{}}
+	picked := make(map[spotify.ID]bool)
+
+	// seed adds a seed and the catalog tracks matching it
+	seed := func(id, typ, href string, match func(t *spotify.FullTrack) bool) {
+		n := 0
+		for _, t := range s.tracks {
+			if !match(t) {
+				continue
+			}
+
+			n++
+			if !picked[t.ID] && len(rec.Tracks) < limit {
+				picked[t.ID] = true
+				rec.Tracks = append(rec.Tracks, t)
+			}
+		}
+
+		rec.Seeds = append(rec.Seeds, &spotify.RecommendationSeed{
+			AfterFilteringSize: n,
+			AfterRelinkSize:    n,
+			Href:               href,
+			ID:                 id,
+			InitialPoolSize:    n,
+			Type:               typ,
+		})
+	}
+
+	for _, v := range artists {
+		id, err := idParam(v)
+		if err != nil {
+			return nil, err
+		}
+		seed(v, "ARTIST", apiBase+"artists/"+v, func(t *spotify.FullTrack) bool { return byArtist(t.Artists, id) })
+	}
+
+	for _, v := range tracks {
+		id, err := idParam(v)
+		if err != nil {
+			return nil, err
+		}
+
+		var by []*spotify.SimpleArtist
+		if st := s.track(id); st != nil {
+			by = st.Artists
+		}
+		seed(v, "TRACK", apiBase+"tracks/"+v, func(t *spotify.FullTrack) bool {
+			for _, a := range by {
+				if t.ID != id && byArtist(t.Artists, a.ID) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	for _, g := range genres {
+		g := g
+		seed(g, "GENRE", "", func(t *spotify.FullTrack) bool {
+			a := s.album(t.Album.ID)
+			return a != nil && contains(a.Genres, strings.ToLower(g))
+		})
+	}
+	return rec, nil
+}
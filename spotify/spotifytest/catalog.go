@@ -0,0 +1,508 @@
+package spotifytest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+func (s *Server) artist(id spotify.ID) *spotify.FullArtist {
+	a, _ := s.byID[id].(*spotify.FullArtist)
+	return a
+}
+
+func (s *Server) album(id spotify.ID) *spotify.FullAlbum {
+	a, _ := s.byID[id].(*spotify.FullAlbum)
+	return a
+}
+
+func (s *Server) track(id spotify.ID) *spotify.FullTrack {
+	t, _ := s.byID[id].(*spotify.FullTrack)
+	return t
+}
+
+// albumTracks returns the tracks of an album in track order
+func (s *Server) albumTracks(id spotify.ID) []*spotify.FullTrack {
+	var tracks []*spotify.FullTrack
+	for _, t := range s.tracks {
+		if t.Album != nil && t.Album.ID == id {
+			tracks = append(tracks, t)
+		}
+	}
+
+	sort.SliceStable(tracks, func(i, j int) bool { return tracks[i].TrackNumber < tracks[j].TrackNumber })
+	return tracks
+}
+
+// fullAlbum returns a copy of the album with the first page of its tracks
+func (s *Server) fullAlbum(r *http.Request, a *spotify.FullAlbum) (*spotify.FullAlbum, error) {
+	tracks := s.albumTracks(a.ID)
+	p, err := pageAt(r, len(tracks), 50, 0, func(i int) interface{} { return &tracks[i].SimpleTrack })
+	if err != nil {
+		return nil, err
+	}
+
+	full := *a
+	full.Tracks = p
+	full.Tracks.Href = apiBase + "albums/" + string(a.ID) + "/tracks"
+	return &full, nil
+}
+
+func (s *Server) getAlbum(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	a := s.album(id)
+	if a == nil {
+		return nil, notFound()
+	}
+	return s.fullAlbum(r, a)
+}
+
+func (s *Server) getAlbums(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := make([]*spotify.FullAlbum, len(ids))
+	for i, id := range ids {
+		if a := s.album(id); a != nil {
+			if albums[i], err = s.fullAlbum(r, a); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return map[string]interface{}{"albums": albums}, nil
+}
+
+func (s *Server) getAlbumTracks(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if s.album(id) == nil {
+		return nil, notFound()
+	}
+
+	tracks := s.albumTracks(id)
+	return page(r, len(tracks), 50, func(i int) interface{} { return &tracks[i].SimpleTrack })
+}
+
+func (s *Server) getArtist(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	a := s.artist(id)
+	if a == nil {
+		return nil, notFound()
+	}
+	return a, nil
+}
+
+func (s *Server) getArtists(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	artists := make([]*spotify.FullArtist, len(ids))
+	for i, id := range ids {
+		artists[i] = s.artist(id)
+	}
+	return map[string]interface{}{"artists": artists}, nil
+}
+
+func byArtist(artists []*spotify.SimpleArtist, id spotify.ID) bool {
+	for _, a := range artists {
+		if a.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getArtistAlbums(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if s.artist(id) == nil {
+		return nil, notFound()
+	}
+
+	var albums []*spotify.SimpleAlbum
+	for _, a := range s.albums {
+		if byArtist(a.Artists, id) {
+			albums = append(albums, &a.SimpleAlbum)
+		}
+	}
+	return page(r, len(albums), 50, func(i int) interface{} { return albums[i] })
+}
+
+func (s *Server) getArtistTopTracks(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if s.artist(id) == nil {
+		return nil, notFound()
+	}
+
+	tracks := []*spotify.FullTrack{}
+	for _, t := range s.tracks {
+		if byArtist(t.Artists, id) {
+			tracks = append(tracks, t)
+		}
+	}
+
+	sort.SliceStable(tracks, func(i, j int) bool { return tracks[i].Popularity > tracks[j].Popularity })
+	if len(tracks) > 10 {
+		tracks = tracks[:10]
+	}
+	return map[string]interface{}{"tracks": tracks}, nil
+}
+
+func (s *Server) getRelatedArtists(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if s.artist(id) == nil {
+		return nil, notFound()
+	}
+
+	artists := []*spotify.FullArtist{}
+	for _, rid := range s.related[id] {
+		if a := s.artist(rid); a != nil {
+			artists = append(artists, a)
+		}
+	}
+	return map[string]interface{}{"artists": artists}, nil
+}
+
+func (s *Server) getTrack(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	t := s.track(id)
+	if t == nil {
+		return nil, notFound()
+	}
+	return t, nil
+}
+
+func (s *Server) getTracks(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*spotify.FullTrack, len(ids))
+	for i, id := range ids {
+		tracks[i] = s.track(id)
+	}
+	return map[string]interface{}{"tracks": tracks}, nil
+}
+
+// searchable is what a search query is matched against
+// Free terms match any of the text, field filters only their field.
+type searchable struct {
+	text   []string
+	fields map[string][]string
+	year   int
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if strings.Contains(strings.ToLower(s), v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (q *searchable) match(sq *spotify.SearchQuery) bool {
+	for _, t := range append(append([]string(nil), sq.Terms...), sq.Phrases...) {
+		if !contains(q.text, strings.ToLower(t)) {
+			return false
+		}
+	}
+
+	filters := map[string]string{
+		"artist": sq.Artist,
+		"album":  sq.Album,
+		"track":  sq.Track,
+		"genre":  sq.Genre,
+	}
+	for field, v := range filters {
+		if v != "" && !contains(q.fields[field], strings.ToLower(v)) {
+			return false
+		}
+	}
+
+	codes := map[string]string{
+		"isrc": spotify.NormalizeISRC(sq.ISRC),
+		"upc":  spotify.NormalizeUPC(sq.UPC),
+	}
+	for field, v := range codes {
+		if v == "" {
+			continue
+		}
+
+		found := false
+		for _, c := range q.fields[field] {
+			if c == v {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if sq.YearFrom > 0 || sq.YearTo > 0 {
+		to := sq.YearTo
+		if to == 0 {
+			to = sq.YearFrom
+		}
+
+		if q.year < sq.YearFrom || q.year > to {
+			return false
+		}
+	}
+	return true
+}
+
+func artistNames(artists []*spotify.SimpleArtist) []string {
+	names := make([]string, len(artists))
+	for i, a := range artists {
+		names[i] = a.Name
+	}
+	return names
+}
+
+func (s *Server) search(r *http.Request, args []string) (interface{}, error) {
+	q := r.URL.Query()
+	if q.Get("q") == "" {
+		return nil, errorf(http.StatusBadRequest, "No search query")
+	}
+
+	if q.Get("type") == "" {
+		return nil, errorf(http.StatusBadRequest, "Missing parameter type")
+	}
+
+	sq, err := spotify.ParseSearchQuery(q.Get("q"))
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "%v", err)
+	}
+
+	limit, offset, err := pageParams(r, 50, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	for _, typ := range strings.Split(q.Get("type"), ",") {
+		var items []interface{}
+		switch spotify.SearchType(typ) {
+		case spotify.SearchTypeTrack:
+			for _, t := range s.tracks {
+				sa := searchable{
+					text:   append([]string{t.Name, t.Album.Name}, artistNames(t.Artists)...),
+					fields: map[string][]string{"track": {t.Name}, "album": {t.Album.Name}, "artist": artistNames(t.Artists)},
+					year:   t.Album.ReleaseDate.Year(),
+				}
+				if t.ExternalIDs != nil {
+					sa.fields["isrc"] = []string{spotify.NormalizeISRC(t.ExternalIDs.ISRC())}
+				}
+				if sa.match(sq) {
+					items = append(items, t)
+				}
+			}
+		case spotify.SearchTypeAlbum:
+			for _, a := range s.albums {
+				sa := searchable{
+					text:   append([]string{a.Name}, artistNames(a.Artists)...),
+					fields: map[string][]string{"album": {a.Name}, "artist": artistNames(a.Artists), "genre": a.Genres},
+					year:   a.ReleaseDate.Year(),
+				}
+				if a.ExternalIDs != nil {
+					sa.fields["upc"] = []string{spotify.NormalizeUPC(a.ExternalIDs.UPC())}
+				}
+				if sa.match(sq) {
+					items = append(items, &a.SimpleAlbum)
+				}
+			}
+		case spotify.SearchTypeArtist:
+			for _, a := range s.artists {
+				sa := searchable{
+					text:   []string{a.Name},
+					fields: map[string][]string{"artist": {a.Name}, "genre": a.Genres},
+				}
+				if sa.match(sq) {
+					items = append(items, a)
+				}
+			}
+		case spotify.SearchTypePlaylist:
+			for _, p := range s.playlists {
+				if !p.public && p.owner != s.user.ID {
+					continue
+				}
+
+				sa := searchable{text: []string{p.name, p.description}}
+				if sa.match(sq) {
+					items = append(items, s.simplePlaylist(p))
+				}
+			}
+		case spotify.SearchTypeShow:
+			for _, sh := range s.shows {
+				sa := searchable{text: []string{sh.Name, sh.Publisher, sh.Description}}
+				if sa.match(sq) {
+					items = append(items, sh)
+				}
+			}
+		case spotify.SearchTypeEpisode:
+			for _, e := range s.episodes {
+				sa := searchable{text: []string{e.Name, e.Description}, year: e.ReleaseDate.Year()}
+				if sa.match(sq) {
+					simple := *e
+					simple.Show = nil
+					items = append(items, &simple)
+				}
+			}
+		case spotify.SearchTypeAudiobook:
+			for _, b := range s.audiobooks {
+				sa := searchable{text: []string{b.Name, b.Publisher, b.Description}}
+				if sa.match(sq) {
+					items = append(items, b)
+				}
+			}
+		default:
+			return nil, errorf(http.StatusBadRequest, "Bad search type field %s", typ)
+		}
+
+		p, err := pageAt(r, len(items), limit, offset, func(i int) interface{} { return items[i] })
+		if err != nil {
+			return nil, err
+		}
+		result[typ+"s"] = p
+	}
+	return result, nil
+}
+
+// audioFeatures derives the features of a track from its position on its album
+func audioFeatures(t *spotify.FullTrack) *spotify.AudioFeatures {
+	return &spotify.AudioFeatures{
+		Acousticness:     0.1,
+		AnalysisURL:      apiBase + "audio-analysis/" + string(t.ID),
+		Danceabilitu:     0.7,
+		DurationMs:       t.DurationMs,
+		Energy:           0.5 + 0.05*float32(t.TrackNumber%10),
+		ID:               t.ID,
+		Instrumentalness: 0.2,
+		Key:              (7 * t.TrackNumber) % 12,
+		Liveness:         0.1,
+		Loudness:         -6,
+		Mode:             t.TrackNumber % 2,
+		Speechiness:      0.05,
+		Tempo:            float32(116 + 2*t.TrackNumber),
+		TimeSignature:    4,
+		TrackHref:        t.Href,
+		Type:             "audio_features",
+		URI:              t.URI,
+		Valence:          0.6,
+	}
+}
+
+func (s *Server) getAudioFeature(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	t := s.track(id)
+	if t == nil {
+		return nil, notFound()
+	}
+	return audioFeatures(t), nil
+}
+
+func (s *Server) getAudioFeatures(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	features := make([]*spotify.AudioFeatures, len(ids))
+	for i, id := range ids {
+		if t := s.track(id); t != nil {
+			features[i] = audioFeatures(t)
+		}
+	}
+	return map[string]interface{}{"audio_features": features}, nil
+}
+
+// getAudioAnalysis makes a steady analysis of the track's features, a beat at its tempo, a bar every
+// time signature beats and a single section
+func (s *Server) getAudioAnalysis(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	t := s.track(id)
+	if t == nil {
+		return nil, notFound()
+	}
+
+	f := audioFeatures(t)
+	duration := float32(f.DurationMs) / 1000
+	beat := 60 / f.Tempo
+
+	a := &spotify.AudioAnalysis{
+		Bars:     []*spotify.Interval{},
+		Beats:    []*spotify.Interval{},
+		Meta:     &spotify.AnalysisMeta{AnalyzerVersion: "4.0.0", Platform: "Linux", DetailedStatus: "OK"},
+		Segments: []*spotify.Segment{},
+		Tatums:   []*spotify.Interval{},
+		Track: &spotify.TrackAnalysis{
+			Duration:      duration,
+			Loudness:      f.Loudness,
+			Tempo:         f.Tempo,
+			TimeSignature: f.TimeSignature,
+			Key:           f.Key,
+			Mode:          f.Mode,
+		},
+	}
+
+	a.Sections = []*spotify.Section{{
+		Duration:      duration,
+		Confidence:    1,
+		Loudness:      f.Loudness,
+		Tempo:         f.Tempo,
+		Key:           f.Key,
+		Mode:          f.Mode,
+		TimeSignature: f.TimeSignature,
+	}}
+
+	for i := 0; float32(i)*beat < duration; i++ {
+		a.Beats = append(a.Beats, &spotify.Interval{Start: float32(i) * beat, Duration: beat, Confidence: 1})
+		if i%f.TimeSignature == 0 {
+			a.Bars = append(a.Bars, &spotify.Interval{Start: float32(i) * beat, Duration: beat * float32(f.TimeSignature), Confidence: 1})
+		}
+	}
+	return a, nil
+}
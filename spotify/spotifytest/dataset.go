@@ -0,0 +1,311 @@
+package spotifytest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+const apiBase = "https://api.spotify.com/v1/"
+
+// Dataset is the catalog and library a Server starts with
+// Build one with the Add methods, or start from Seed.
+type Dataset struct {
+	User      *spotify.User
+	Artists   []*spotify.FullArtist
+	Albums    []*spotify.FullAlbum
+	Tracks    []*spotify.FullTrack
+	Playlists []*Playlist
+
+	// Episodes and Chapters point back to their show and audiobook
+	Shows      []*spotify.Show
+	Episodes   []*spotify.Episode
+	Audiobooks []*spotify.Audiobook
+	Chapters   []*spotify.Chapter
+
+	// Categories are the browse categories in the order they are listed
+	Categories []*Category
+
+	// Devices are the user's devices, none is active until playback starts on one
+	Devices []*spotify.Device
+
+	// Related are the related artists of an artist
+	Related map[spotify.ID][]spotify.ID
+
+	// The saved items are newest first, like the API lists them
+	SavedTracks     []spotify.ID
+	SavedAlbums     []spotify.ID
+	SavedShows      []spotify.ID
+	SavedEpisodes   []spotify.ID
+	SavedAudiobooks []spotify.ID
+	FollowedArtists []spotify.ID
+
+	// RecentlyPlayed are the tracks the user played, newest first
+	RecentlyPlayed []spotify.ID
+
+	next int
+}
+
+// Playlist is a playlist of the dataset, it only holds tracks
+type Playlist struct {
+	ID          spotify.ID
+	Name        string
+	Description string
+	Owner       spotify.ID
+	Public      bool
+	Tracks      []spotify.ID
+	Followers   []spotify.ID
+}
+
+// Category is a browse category of the dataset and the playlists listed in it
+type Category struct {
+	ID        string
+	Name      string
+	Playlists []spotify.ID
+}
+
+// NewDataset makes an empty dataset for the user with the ID
+func NewDataset(userID spotify.ID) *Dataset {
+	return &Dataset{
+		User: &spotify.User{
+			Country:     "SE",
+			DisplayName: string(userID),
+			Href:        apiBase + "users/" + string(userID),
+			ID:          userID,
+			Product:     "premium",
+			Type:        "user",
+			URI:         userID.URI(spotify.KindUser),
+		},
+		Related: make(map[spotify.ID][]spotify.ID),
+	}
+}
+
+// Seed makes a small dataset of a few artists, albums, playlists, a show, an audiobook and two devices
+// for the user "test-user"
+func Seed() *Dataset {
+	d := NewDataset("test-user")
+
+	daft := d.AddArtist("Daft Punk", "french house", "electro")
+	justice := d.AddArtist("Justice", "french house", "electro")
+	miles := d.AddArtist("Miles Davis", "jazz", "cool jazz")
+	d.Related[daft.ID] = []spotify.ID{justice.ID}
+	d.Related[justice.ID] = []spotify.ID{daft.ID}
+
+	discovery := d.AddAlbum(daft, "Discovery", "One More Time", "Aerodynamic", "Digital Love", "Harder, Better, Faster, Stronger")
+	cross := d.AddAlbum(justice, "Cross", "Genesis", "Let There Be Light", "D.A.N.C.E.")
+	blue := d.AddAlbum(miles, "Kind of Blue", "So What", "Freddie Freeloader", "Blue in Green")
+
+	tracks := func(a *spotify.FullAlbum) []spotify.ID {
+		var ids []spotify.ID
+		for _, t := range d.Tracks {
+			if t.Album.ID == a.ID {
+				ids = append(ids, t.ID)
+			}
+		}
+		return ids
+	}
+
+	french := d.AddPlaylist("French Touch", append(tracks(discovery), tracks(cross)...)...)
+	late := d.AddPlaylist("Late Night", tracks(blue)...)
+	d.AddCategory("Electronic", french.ID)
+	d.AddCategory("Jazz", late.ID)
+
+	show := d.AddShow("Test Podcast", "Test Publisher", "Pilot", "Second Episode", "Third Episode")
+	book := d.AddAudiobook("Test Book", "Test Author", "Chapter One", "Chapter Two")
+
+	d.SavedTracks = tracks(discovery)[:2]
+	d.SavedAlbums = []spotify.ID{blue.ID}
+	d.SavedShows = []spotify.ID{show.ID}
+	d.SavedEpisodes = []spotify.ID{d.Episodes[0].ID}
+	d.SavedAudiobooks = []spotify.ID{book.ID}
+	d.FollowedArtists = []spotify.ID{daft.ID, miles.ID}
+
+	d.AddDevice("Test Computer", "Computer")
+	d.AddDevice("Test Phone", "Smartphone")
+	d.RecentlyPlayed = tracks(blue)
+	return d
+}
+
+// id makes a valid base62 ID that is unique in the dataset
+func (d *Dataset) id(prefix byte) spotify.ID {
+	d.next++
+	return spotify.ID(fmt.Sprintf("%c%021d", prefix, d.next))
+}
+
+// AddArtist adds an artist
+func (d *Dataset) AddArtist(name string, genres ...string) *spotify.FullArtist {
+	id := d.id('a')
+	a := &spotify.FullArtist{
+		SimpleArtist: spotify.SimpleArtist{
+			Href: apiBase + "artists/" + string(id),
+			ID:   id,
+			Name: name,
+			Type: "artist",
+			URI:  id.URI(spotify.KindArtist),
+		},
+		Followers:  &spotify.Follower{Total: 1000},
+		Genres:     genres,
+		Popularity: 50,
+	}
+	d.Artists = append(d.Artists, a)
+	return a
+}
+
+// AddAlbum adds an album by the artist and a track for every track name
+// The album gets a UPC and the tracks ISRCs, both unique in the dataset.
+func (d *Dataset) AddAlbum(artist *spotify.FullArtist, name string, tracks ...string) *spotify.FullAlbum {
+	id := d.id('b')
+	released, _ := spotify.ParseReleaseDate("2001-03-12", spotify.PrecisionDay)
+	artists := []*spotify.SimpleArtist{&artist.SimpleArtist}
+
+	a := &spotify.FullAlbum{
+		SimpleAlbum: spotify.SimpleAlbum{
+			AlbumType:            "album",
+			Artists:              artists,
+			Href:                 apiBase + "albums/" + string(id),
+			ID:                   id,
+			Name:                 name,
+			ReleaseDate:          released,
+			ReleaseDatePrecision: spotify.PrecisionDay,
+			TotalTracks:          len(tracks),
+			Type:                 "album",
+			URI:                  id.URI(spotify.KindAlbum),
+		},
+		ExternalIDs: &spotify.ExternalIDs{IDs: map[string]string{"upc": fmt.Sprintf("%012d", d.next)}},
+		Genres:      artist.Genres,
+		Label:       "Test Records",
+		Popularity:  50,
+	}
+	d.Albums = append(d.Albums, a)
+
+	for i, tn := range tracks {
+		tid := d.id('t')
+		d.Tracks = append(d.Tracks, &spotify.FullTrack{
+			SimpleTrack: spotify.SimpleTrack{
+				Artists:     artists,
+				DiscNumber:  1,
+				DurationMs:  180000 + 1000*i,
+				Href:        apiBase + "tracks/" + string(tid),
+				ID:          tid,
+				IsPlayable:  true,
+				Name:        tn,
+				TrackNumber: i + 1,
+				Type:        "track",
+				URI:         tid.URI(spotify.KindTrack),
+			},
+			Album:       &a.SimpleAlbum,
+			ExternalIDs: &spotify.ExternalIDs{IDs: map[string]string{"isrc": fmt.Sprintf("TEST%08d", d.next)}},
+			Popularity:  50,
+		})
+	}
+	return a
+}
+
+// AddPlaylist adds a public playlist owned by the user with the tracks
+func (d *Dataset) AddPlaylist(name string, tracks ...spotify.ID) *Playlist {
+	p := &Playlist{ID: d.id('p'), Name: name, Owner: d.User.ID, Public: true, Tracks: tracks}
+	d.Playlists = append(d.Playlists, p)
+	return p
+}
+
+// AddShow adds a podcast show by the publisher and an episode for every episode name, released a day apart
+func (d *Dataset) AddShow(name, publisher string, episodes ...string) *spotify.Show {
+	id := d.id('s')
+	show := &spotify.Show{
+		Copyrights:    []*spotify.Copyright{},
+		Description:   name + " by " + publisher,
+		Href:          apiBase + "shows/" + string(id),
+		ID:            id,
+		Images:        []*spotify.Image{},
+		Languages:     []string{"en"},
+		MediaType:     "audio",
+		Name:          name,
+		Publisher:     publisher,
+		TotalEpisodes: len(episodes),
+		Type:          "show",
+		URI:           id.URI(spotify.KindShow),
+	}
+	d.Shows = append(d.Shows, show)
+
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, en := range episodes {
+		eid := d.id('e')
+		released, _ := spotify.ParseReleaseDate(first.AddDate(0, 0, i).Format("2006-01-02"), spotify.PrecisionDay)
+		d.Episodes = append(d.Episodes, &spotify.Episode{
+			Description:          en,
+			DurationMs:           1800000 + 1000*i,
+			Href:                 apiBase + "episodes/" + string(eid),
+			ID:                   eid,
+			Images:               []*spotify.Image{},
+			IsPlayable:           true,
+			Languages:            []string{"en"},
+			Name:                 en,
+			ReleaseDate:          released,
+			ReleaseDatePrecision: spotify.PrecisionDay,
+			Show:                 show,
+			Type:                 "episode",
+			URI:                  eid.URI(spotify.KindEpisode),
+		})
+	}
+	return show
+}
+
+// AddAudiobook adds an audiobook by the author, who also narrates it, and a chapter for every chapter name
+func (d *Dataset) AddAudiobook(name, author string, chapters ...string) *spotify.Audiobook {
+	id := d.id('k')
+	book := &spotify.Audiobook{
+		Authors:       []*spotify.Author{{Name: author}},
+		Copyrights:    []*spotify.Copyright{},
+		Description:   name + " by " + author,
+		Edition:       "Unabridged",
+		Href:          apiBase + "audiobooks/" + string(id),
+		ID:            id,
+		Images:        []*spotify.Image{},
+		Languages:     []string{"en"},
+		MediaType:     "audio",
+		Name:          name,
+		Narrators:     []*spotify.Narrator{{Name: author}},
+		Publisher:     author,
+		TotalChapters: len(chapters),
+		Type:          "audiobook",
+		URI:           id.URI(spotify.KindAudiobook),
+	}
+	d.Audiobooks = append(d.Audiobooks, book)
+
+	released, _ := spotify.ParseReleaseDate("2019", spotify.PrecisionYear)
+	for i, cn := range chapters {
+		cid := d.id('c')
+		d.Chapters = append(d.Chapters, &spotify.Chapter{
+			Audiobook:            book,
+			ChapterNumber:        i + 1,
+			Description:          cn,
+			DurationMs:           900000 + 1000*i,
+			Href:                 apiBase + "chapters/" + string(cid),
+			ID:                   cid,
+			Images:               []*spotify.Image{},
+			IsPlayable:           true,
+			Languages:            []string{"en"},
+			Name:                 cn,
+			ReleaseDate:          released,
+			ReleaseDatePrecision: spotify.PrecisionYear,
+			Type:                 "chapter",
+			URI:                  cid.URI(spotify.KindChapter),
+		})
+	}
+	return book
+}
+
+// AddCategory adds a browse category listing the playlists
+func (d *Dataset) AddCategory(name string, playlists ...spotify.ID) *Category {
+	c := &Category{ID: string(d.id('g')), Name: name, Playlists: playlists}
+	d.Categories = append(d.Categories, c)
+	return c
+}
+
+// AddDevice adds a device of the user, at half volume
+func (d *Dataset) AddDevice(name, typ string) *spotify.Device {
+	dev := &spotify.Device{ID: d.id('d'), Name: name, Type: typ, VolumePercent: 50, SupportsVolume: true}
+	d.Devices = append(d.Devices, dev)
+	return dev
+}
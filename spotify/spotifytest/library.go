@@ -0,0 +1,367 @@
+package spotifytest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+func (s *Server) getMe(r *http.Request, args []string) (interface{}, error) {
+	return s.user, nil
+}
+
+// userObject returns the profile of a user, only the dataset's user has more than an ID
+func (s *Server) userObject(id spotify.ID) *spotify.User {
+	if id == s.user.ID {
+		return s.user
+	}
+
+	return &spotify.User{
+		DisplayName: string(id),
+		Href:        apiBase + "users/" + string(id),
+		ID:          id,
+		Type:        "user",
+		URI:         id.URI(spotify.KindUser),
+	}
+}
+
+func (s *Server) getUser(r *http.Request, args []string) (interface{}, error) {
+	return s.userObject(spotify.ID(args[0])), nil
+}
+
+func indexOf(items []saved, id spotify.ID) int {
+	for i, item := range items {
+		if item.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// save adds the IDs that aren't saved yet to the front of the library
+func (s *Server) save(items []saved, ids []spotify.ID) []saved {
+	now := s.now().UTC().Truncate(time.Second)
+	for _, id := range ids {
+		if indexOf(items, id) < 0 {
+			items = append([]saved{{id, now}}, items...)
+		}
+	}
+	return items
+}
+
+func unsave(items []saved, ids []spotify.ID) []saved {
+	for _, id := range ids {
+		if i := indexOf(items, id); i >= 0 {
+			items = append(items[:i:i], items[i+1:]...)
+		}
+	}
+	return items
+}
+
+func containsSaved(items []saved, ids []spotify.ID) []bool {
+	found := make([]bool, len(ids))
+	for i, id := range ids {
+		found[i] = indexOf(items, id) >= 0
+	}
+	return found
+}
+
+func (s *Server) getSavedTracks(r *http.Request, args []string) (interface{}, error) {
+	return page(r, len(s.savedTracks), 50, func(i int) interface{} {
+		item := s.savedTracks[i]
+		return &spotify.SavedTrack{AddedAt: timestamp(item.addedAt), Track: s.track(item.id)}
+	})
+}
+
+func (s *Server) saveTracks(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if s.track(id) == nil {
+			return nil, errorf(http.StatusBadRequest, "Payload contains a non-existing ID")
+		}
+	}
+
+	s.savedTracks = s.save(s.savedTracks, ids)
+	return nil, nil
+}
+
+func (s *Server) removeSavedTracks(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	s.savedTracks = unsave(s.savedTracks, ids)
+	return nil, nil
+}
+
+func (s *Server) containsSavedTracks(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+	return containsSaved(s.savedTracks, ids), nil
+}
+
+func (s *Server) getSavedAlbums(r *http.Request, args []string) (interface{}, error) {
+	var err error
+	p, perr := page(r, len(s.savedAlbums), 50, func(i int) interface{} {
+		item := s.savedAlbums[i]
+		sa := &spotify.SavedAlbum{AddedAt: timestamp(item.addedAt)}
+		if a := s.album(item.id); a != nil && err == nil {
+			sa.Album, err = s.fullAlbum(r, a)
+		}
+		return sa
+	})
+	if perr != nil {
+		return nil, perr
+	}
+	return p, err
+}
+
+func (s *Server) saveAlbums(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if s.album(id) == nil {
+			return nil, errorf(http.StatusBadRequest, "Payload contains a non-existing ID")
+		}
+	}
+
+	s.savedAlbums = s.save(s.savedAlbums, ids)
+	return nil, nil
+}
+
+func (s *Server) removeSavedAlbums(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 20)
+	if err != nil {
+		return nil, err
+	}
+
+	s.savedAlbums = unsave(s.savedAlbums, ids)
+	return nil, nil
+}
+
+func (s *Server) containsSavedAlbums(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 20)
+	if err != nil {
+		return nil, err
+	}
+	return containsSaved(s.savedAlbums, ids), nil
+}
+
+// saveItems adds the IDs of the request to a library, they all have to exist
+func (s *Server) saveItems(r *http.Request, items *[]saved, max int, exists func(spotify.ID) bool) error {
+	ids, err := idsParam(r, max)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if !exists(id) {
+			return errorf(http.StatusBadRequest, "Payload contains a non-existing ID")
+		}
+	}
+
+	*items = s.save(*items, ids)
+	return nil
+}
+
+func removeItems(r *http.Request, items *[]saved, max int) error {
+	ids, err := idsParam(r, max)
+	if err != nil {
+		return err
+	}
+
+	*items = unsave(*items, ids)
+	return nil
+}
+
+func containsItems(r *http.Request, items []saved, max int) (interface{}, error) {
+	ids, err := idsParam(r, max)
+	if err != nil {
+		return nil, err
+	}
+	return containsSaved(items, ids), nil
+}
+
+func (s *Server) getSavedShows(r *http.Request, args []string) (interface{}, error) {
+	return page(r, len(s.savedShows), 50, func(i int) interface{} {
+		item := s.savedShows[i]
+		return &spotify.SavedShow{AddedAt: timestamp(item.addedAt), Show: s.show(item.id)}
+	})
+}
+
+func (s *Server) saveShows(r *http.Request, args []string) (interface{}, error) {
+	return nil, s.saveItems(r, &s.savedShows, 50, func(id spotify.ID) bool { return s.show(id) != nil })
+}
+
+func (s *Server) removeSavedShows(r *http.Request, args []string) (interface{}, error) {
+	return nil, removeItems(r, &s.savedShows, 50)
+}
+
+func (s *Server) containsSavedShows(r *http.Request, args []string) (interface{}, error) {
+	return containsItems(r, s.savedShows, 50)
+}
+
+func (s *Server) getSavedEpisodes(r *http.Request, args []string) (interface{}, error) {
+	return page(r, len(s.savedEpisodes), 50, func(i int) interface{} {
+		item := s.savedEpisodes[i]
+		return &spotify.SavedEpisode{AddedAt: timestamp(item.addedAt), Episode: s.episode(item.id)}
+	})
+}
+
+func (s *Server) saveEpisodes(r *http.Request, args []string) (interface{}, error) {
+	return nil, s.saveItems(r, &s.savedEpisodes, 50, func(id spotify.ID) bool { return s.episode(id) != nil })
+}
+
+func (s *Server) removeSavedEpisodes(r *http.Request, args []string) (interface{}, error) {
+	return nil, removeItems(r, &s.savedEpisodes, 50)
+}
+
+func (s *Server) containsSavedEpisodes(r *http.Request, args []string) (interface{}, error) {
+	return containsItems(r, s.savedEpisodes, 50)
+}
+
+func (s *Server) getSavedAudiobooks(r *http.Request, args []string) (interface{}, error) {
+	return page(r, len(s.savedAudiobooks), 50, func(i int) interface{} {
+		item := s.savedAudiobooks[i]
+		return &spotify.SavedAudiobook{AddedAt: timestamp(item.addedAt), Audiobook: s.audiobook(item.id)}
+	})
+}
+
+func (s *Server) saveAudiobooks(r *http.Request, args []string) (interface{}, error) {
+	return nil, s.saveItems(r, &s.savedAudiobooks, 50, func(id spotify.ID) bool { return s.audiobook(id) != nil })
+}
+
+func (s *Server) removeSavedAudiobooks(r *http.Request, args []string) (interface{}, error) {
+	return nil, removeItems(r, &s.savedAudiobooks, 50)
+}
+
+func (s *Server) containsSavedAudiobooks(r *http.Request, args []string) (interface{}, error) {
+	return containsItems(r, s.savedAudiobooks, 50)
+}
+
+// artistType checks the type parameter of the follow endpoints, the fake only follows artists
+func artistType(r *http.Request) error {
+	if r.URL.Query().Get("type") != "artist" {
+		return errorf(http.StatusBadRequest, "Invalid type, only artist is supported")
+	}
+	return nil
+}
+
+// getFollowing pages the followed artists with the ID of the last artist of a page as the after cursor
+func (s *Server) getFollowing(r *http.Request, args []string) (interface{}, error) {
+	if err := artistType(r); err != nil {
+		return nil, err
+	}
+
+	limit, _, err := pageParams(r, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if after := r.URL.Query().Get("after"); after != "" {
+		for i, id := range s.followed {
+			if string(id) == after {
+				start = i + 1
+			}
+		}
+	}
+
+	p := &spotify.ArtistCursorPage{
+		Href:    apiBase + "me/following?type=artist&limit=" + strconv.Itoa(limit),
+		Items:   []*spotify.FullArtist{},
+		Limit:   limit,
+		Cursors: &spotify.Cursor{},
+		Total:   len(s.followed),
+	}
+
+	for i := start; i < len(s.followed) && i < start+limit; i++ {
+		p.Items = append(p.Items, s.artist(s.followed[i]))
+	}
+
+	if end := start + len(p.Items); end < len(s.followed) {
+		p.Cursors.After = string(s.followed[end-1])
+		p.Next = p.Href + "&after=" + p.Cursors.After
+	}
+	return map[string]interface{}{"artists": p}, nil
+}
+
+func (s *Server) follow(r *http.Request, args []string) (interface{}, error) {
+	if err := artistType(r); err != nil {
+		return nil, err
+	}
+
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if s.artist(id) == nil {
+			return nil, errorf(http.StatusBadRequest, "Payload contains a non-existing ID")
+		}
+	}
+
+	for _, id := range ids {
+		if indexOfID(s.followed, id) < 0 {
+			s.followed = append(s.followed, id)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) unfollow(r *http.Request, args []string) (interface{}, error) {
+	if err := artistType(r); err != nil {
+		return nil, err
+	}
+
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if i := indexOfID(s.followed, id); i >= 0 {
+			s.followed = append(s.followed[:i:i], s.followed[i+1:]...)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) containsFollowing(r *http.Request, args []string) (interface{}, error) {
+	if err := artistType(r); err != nil {
+		return nil, err
+	}
+
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]bool, len(ids))
+	for i, id := range ids {
+		found[i] = indexOfID(s.followed, id) >= 0
+	}
+	return found, nil
+}
+
+func indexOfID(ids []spotify.ID, id spotify.ID) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,572 @@
+package spotifytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// playback is the user's player, there is none until it starts on a device
+// The items are those of the context or the played URIs, the queue plays before the rest of them.
+type playback struct {
+	device   *spotify.Device
+	context  spotify.URI
+	items    []spotify.URI
+	pos      int
+	current  spotify.URI
+	started  time.Time
+	progress int
+	playing  bool
+	repeat   string
+	shuffle  bool
+	queue    []spotify.URI
+}
+
+// played is a play of a track, for the recently played tracks
+type played struct {
+	id      spotify.ID
+	at      time.Time
+	context spotify.URI
+}
+
+// Devices returns the user's devices as the player left them
+func (s *Server) Devices() []*spotify.Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	devices := make([]*spotify.Device, len(s.devices))
+	for i, d := range s.devices {
+		copied := *d
+		devices[i] = &copied
+	}
+	return devices
+}
+
+func (s *Server) device(id spotify.ID) *spotify.Device {
+	for _, d := range s.devices {
+		if d.ID == id {
+			return d
+		}
+	}
+	return nil
+}
+
+// playerDevice returns the device a command is for, the active one if the request has no device_id
+// A device_id that isn't the active device becomes it, the playback moves there.
+func (s *Server) playerDevice(r *http.Request) (*spotify.Device, error) {
+	if s.user.Product != "premium" {
+		return nil, errorf(http.StatusForbidden, "Player command failed: Premium required")
+	}
+
+	v := r.URL.Query().Get("device_id")
+	if v == "" {
+		if s.playback.device == nil {
+			return nil, errorf(http.StatusNotFound, "Player command failed: No active device found")
+		}
+		return s.playback.device, nil
+	}
+
+	d := s.device(spotify.ID(v))
+	if d == nil {
+		return nil, errorf(http.StatusNotFound, "Device not found")
+	}
+	s.activate(d)
+	return d, nil
+}
+
+func (s *Server) activate(d *spotify.Device) {
+	for _, other := range s.devices {
+		other.IsActive = other == d
+	}
+	s.playback.device = d
+	if s.playback.repeat == "" {
+		s.playback.repeat = "off"
+	}
+}
+
+// item returns the catalog track or episode of a URI, nil if it is neither
+func (s *Server) item(uri spotify.URI) *spotify.PlaylistItem {
+	switch uri.Kind() {
+	case spotify.KindTrack:
+		if t := s.track(uri.ID()); t != nil {
+			return &spotify.PlaylistItem{Track: t}
+		}
+	case spotify.KindEpisode:
+		if e := s.episode(uri.ID()); e != nil {
+			return &spotify.PlaylistItem{Episode: e}
+		}
+	}
+	return nil
+}
+
+func duration(item *spotify.PlaylistItem) int {
+	if item.Episode != nil {
+		return item.Episode.DurationMs
+	}
+	return item.Track.DurationMs
+}
+
+// contextItems returns the items an album, artist, playlist or show plays
+func (s *Server) contextItems(uri spotify.URI) ([]spotify.URI, error) {
+	var items []spotify.URI
+	switch id := uri.ID(); uri.Kind() {
+	case spotify.KindAlbum:
+		for _, t := range s.albumTracks(id) {
+			items = append(items, t.URI)
+		}
+	case spotify.KindArtist:
+		for _, t := range s.tracks {
+			if byArtist(t.Artists, id) {
+				items = append(items, t.URI)
+			}
+		}
+	case spotify.KindPlaylist:
+		if p := s.playlist(id); p != nil {
+			for _, item := range p.items {
+				items = append(items, item.id.URI(spotify.KindTrack))
+			}
+		}
+	case spotify.KindShow:
+		for _, e := range s.showEpisodes(id) {
+			items = append(items, e.URI)
+		}
+	default:
+		return nil, errorf(http.StatusBadRequest, "Invalid context uri")
+	}
+
+	if len(items) == 0 {
+		return nil, errorf(http.StatusNotFound, "Context not found")
+	}
+	return items, nil
+}
+
+// position is the progress of the playing item, which advances while it plays
+func (s *Server) position() int {
+	p := &s.playback
+	if !p.playing {
+		return p.progress
+	}
+	return p.progress + int(s.now().Sub(p.started)/time.Millisecond)
+}
+
+// seekTo moves to a position in the playing item and notes when it was done
+func (s *Server) seekTo(ms int) {
+	s.playback.progress, s.playback.started = ms, s.now()
+}
+
+func (s *Server) contextObject() *spotify.PlaybackContext {
+	uri := s.playback.context
+	if uri == "" {
+		return nil
+	}
+
+	kind, id := string(uri.Kind()), string(uri.ID())
+	href := apiBase + kind + "s/" + id
+	if uri.Kind() == spotify.KindPlaylist {
+		href = apiBase + "playlists/" + id
+	}
+	return &spotify.PlaybackContext{
+		Type:         kind,
+		Href:         href,
+		ExternalURLs: &spotify.ExternalURLs{URLs: map[string]string{"spotify": "https://open.spotify.com/" + kind + "/" + id}},
+		URI:          uri,
+	}
+}
+
+func (s *Server) currentlyPlaying() *spotify.CurrentlyPlaying {
+	playing := &spotify.CurrentlyPlaying{
+		Context:              s.contextObject(),
+		Timestamp:            s.now().UnixNano() / int64(time.Millisecond),
+		IsPlaying:            s.playback.playing,
+		CurrentlyPlayingType: "unknown",
+	}
+
+	if item := s.item(s.playback.current); item != nil {
+		playing.Item = item
+		playing.ProgressMs = s.position()
+		playing.CurrentlyPlayingType = string(s.playback.current.Kind())
+	}
+	return playing
+}
+
+func (s *Server) getPlaybackState(r *http.Request, args []string) (interface{}, error) {
+	if s.playback.device == nil {
+		return noContent{}, nil
+	}
+
+	return &spotify.PlaybackState{
+		CurrentlyPlaying: *s.currentlyPlaying(),
+		Device:           s.playback.device,
+		RepeatState:      s.playback.repeat,
+		ShuffleState:     s.playback.shuffle,
+	}, nil
+}
+
+func (s *Server) getCurrentlyPlaying(r *http.Request, args []string) (interface{}, error) {
+	if s.playback.device == nil || s.playback.current == "" {
+		return noContent{}, nil
+	}
+	return s.currentlyPlaying(), nil
+}
+
+func (s *Server) getDevices(r *http.Request, args []string) (interface{}, error) {
+	return map[string]interface{}{"devices": s.devices}, nil
+}
+
+func (s *Server) transferPlayback(r *http.Request, args []string) (interface{}, error) {
+	var body struct {
+		DeviceIDs []spotify.ID `json:"device_ids"`
+		Play      bool         `json:"play"`
+	}
+	if err := decodeBody(r, &body); err != nil {
+		return nil, err
+	}
+
+	if len(body.DeviceIDs) != 1 {
+		return nil, errorf(http.StatusBadRequest, "Exactly one device id is supported")
+	}
+
+	d := s.device(body.DeviceIDs[0])
+	if d == nil {
+		return nil, errorf(http.StatusNotFound, "Device not found")
+	}
+
+	progress := s.position()
+	s.activate(d)
+	if body.Play && s.playback.current != "" {
+		s.playback.playing = true
+	}
+	s.seekTo(progress)
+	return noContent{}, nil
+}
+
+// play starts the context or URIs of the body, an empty body resumes the playback
+func (s *Server) play(r *http.Request, args []string) (interface{}, error) {
+	if _, err := s.playerDevice(r); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var body spotify.PlayOptions
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err = json.Unmarshal(data, &body); err != nil {
+			return nil, errorf(http.StatusBadRequest, "Error parsing JSON.")
+		}
+	}
+
+	var items []spotify.URI
+	switch {
+	case body.ContextURI != "" && len(body.URIs) > 0:
+		return nil, errorf(http.StatusBadRequest, "Only one of context_uri and uris can be specified")
+	case body.ContextURI != "":
+		if items, err = s.contextItems(body.ContextURI); err != nil {
+			return nil, err
+		}
+	case len(body.URIs) > 0:
+		for _, uri := range body.URIs {
+			if s.item(uri) == nil {
+				return nil, errorf(http.StatusBadRequest, "Invalid track uri: %s", uri)
+			}
+		}
+		items = body.URIs
+	default:
+		if s.playback.current == "" {
+			return nil, errorf(http.StatusForbidden, "Player command failed: Restriction violated")
+		}
+		s.seekTo(s.position())
+		s.playback.playing = true
+		return noContent{}, nil
+	}
+
+	pos := 0
+	if o := body.Offset; o != nil {
+		pos = o.Position
+		if o.URI != "" {
+			pos = -1
+			for i, uri := range items {
+				if uri == o.URI {
+					pos = i
+				}
+			}
+		}
+		if pos < 0 || pos >= len(items) {
+			return nil, errorf(http.StatusBadRequest, "Invalid offset")
+		}
+	}
+
+	s.playback.context, s.playback.items, s.playback.pos = body.ContextURI, items, pos
+	s.playback.current = items[pos]
+	s.playback.playing = true
+	s.seekTo(body.PositionMs)
+	return noContent{}, nil
+}
+
+func (s *Server) pause(r *http.Request, args []string) (interface{}, error) {
+	if _, err := s.playerDevice(r); err != nil {
+		return nil, err
+	}
+
+	if !s.playback.playing {
+		return nil, errorf(http.StatusForbidden, "Player command failed: Restriction violated")
+	}
+	s.seekTo(s.position())
+	s.playback.playing = false
+	return noContent{}, nil
+}
+
+// advance moves to the next item, the queue first, and records the play of the current one
+func (s *Server) advance() {
+	p := &s.playback
+	if p.current.Kind() == spotify.KindTrack {
+		s.played = append([]played{{id: p.current.ID(), at: s.now().UTC(), context: p.context}}, s.played...)
+	}
+
+	switch {
+	case len(p.queue) > 0:
+		p.current, p.queue = p.queue[0], p.queue[1:]
+	case p.pos+1 < len(p.items):
+		p.pos++
+		p.current = p.items[p.pos]
+	case p.repeat == "context" && len(p.items) > 0:
+		p.pos = 0
+		p.current = p.items[0]
+	default:
+		p.current, p.playing = "", false
+	}
+	s.seekTo(0)
+}
+
+func (s *Server) next(r *http.Request, args []string) (interface{}, error) {
+	if _, err := s.playerDevice(r); err != nil {
+		return nil, err
+	}
+
+	if s.playback.current == "" {
+		return nil, errorf(http.StatusForbidden, "Player command failed: Restriction violated")
+	}
+	s.advance()
+	return noContent{}, nil
+}
+
+// previous goes back to the previous item of the context, or the start of the first one
+func (s *Server) previous(r *http.Request, args []string) (interface{}, error) {
+	if _, err := s.playerDevice(r); err != nil {
+		return nil, err
+	}
+
+	p := &s.playback
+	if p.current == "" {
+		return nil, errorf(http.StatusForbidden, "Player command failed: Restriction violated")
+	}
+
+	if p.pos > 0 {
+		p.pos--
+	}
+	if len(p.items) > 0 {
+		p.current = p.items[p.pos]
+	}
+	s.seekTo(0)
+	return noContent{}, nil
+}
+
+// intParam reads a required integer parameter between min and max
+func intParam(r *http.Request, name string, min, max int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, errorf(http.StatusBadRequest, "Missing required parameter: %s", name)
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		return 0, errorf(http.StatusBadRequest, "Invalid %s", name)
+	}
+	return n, nil
+}
+
+// seek moves in the playing item, past its end the next item plays
+func (s *Server) seek(r *http.Request, args []string) (interface{}, error) {
+	if _, err := s.playerDevice(r); err != nil {
+		return nil, err
+	}
+
+	ms, err := intParam(r, "position_ms", 0, int(^uint(0)>>1))
+	if err != nil {
+		return nil, err
+	}
+
+	item := s.item(s.playback.current)
+	if item == nil {
+		return nil, errorf(http.StatusForbidden, "Player command failed: Restriction violated")
+	}
+
+	if ms >= duration(item) {
+		s.advance()
+		return noContent{}, nil
+	}
+	s.seekTo(ms)
+	return noContent{}, nil
+}
+
+func (s *Server) setRepeat(r *http.Request, args []string) (interface{}, error) {
+	if _, err := s.playerDevice(r); err != nil {
+		return nil, err
+	}
+
+	switch state := r.URL.Query().Get("state"); state {
+	case "track", "context", "off":
+		s.playback.repeat = state
+	default:
+		return nil, errorf(http.StatusBadRequest, "Invalid repeat state")
+	}
+	return noContent{}, nil
+}
+
+func (s *Server) setVolume(r *http.Request, args []string) (interface{}, error) {
+	d, err := s.playerDevice(r)
+	if err != nil {
+		return nil, err
+	}
+
+	percent, err := intParam(r, "volume_percent", 0, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.SupportsVolume {
+		return nil, errorf(http.StatusForbidden, "Player command failed: Cannot control device volume")
+	}
+	d.VolumePercent = percent
+	return noContent{}, nil
+}
+
+func (s *Server) setShuffle(r *http.Request, args []string) (interface{}, error) {
+	if _, err := s.playerDevice(r); err != nil {
+		return nil, err
+	}
+
+	shuffle, err := strconv.ParseBool(r.URL.Query().Get("state"))
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "Invalid shuffle state")
+	}
+	s.playback.shuffle = shuffle
+	return noContent{}, nil
+}
+
+// getQueue lists the queued items followed by the rest of the context
+func (s *Server) getQueue(r *http.Request, args []string) (interface{}, error) {
+	p := &s.playback
+	queue := []*spotify.PlaylistItem{}
+	next := p.queue
+	if p.pos+1 < len(p.items) {
+		next = append(next[:len(next):len(next)], p.items[p.pos+1:]...)
+	}
+	for _, uri := range next {
+		queue = append(queue, s.item(uri))
+	}
+
+	return &spotify.Queue{CurrentlyPlaying: s.item(p.current), Queue: queue}, nil
+}
+
+func (s *Server) addToQueue(r *http.Request, args []string) (interface{}, error) {
+	if _, err := s.playerDevice(r); err != nil {
+		return nil, err
+	}
+
+	uri := spotify.URI(r.URL.Query().Get("uri"))
+	if uri == "" {
+		return nil, errorf(http.StatusBadRequest, "Missing required parameter: uri")
+	}
+
+	if s.item(uri) == nil {
+		return nil, errorf(http.StatusBadRequest, "Invalid track uri: %s", uri)
+	}
+	s.playback.queue = append(s.playback.queue, uri)
+	return noContent{}, nil
+}
+
+// playedAtFormat is the format of the play times, which unlike the other timestamps have milliseconds
+const playedAtFormat = "2006-01-02T15:04:05.000Z07:00"
+
+func unixMillis(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
+// getRecentlyPlayed lists the plays newest first, before and after are cursors of unix milliseconds
+func (s *Server) getRecentlyPlayed(r *http.Request, args []string) (interface{}, error) {
+	limit, _, err := pageParams(r, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	q := r.URL.Query()
+	if q.Get("before") != "" && q.Get("after") != "" {
+		return nil, errorf(http.StatusBadRequest, "Only one of before and after can be specified")
+	}
+
+	cursor := func(name string) (int64, error) {
+		v := q.Get(name)
+		if v == "" {
+			return 0, nil
+		}
+
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return 0, errorf(http.StatusBadRequest, "Invalid %s", name)
+		}
+		return n, nil
+	}
+
+	before, err := cursor("before")
+	if err != nil {
+		return nil, err
+	}
+	after, err := cursor("after")
+	if err != nil {
+		return nil, err
+	}
+
+	href := apiBase + "me/player/recently-played?limit=" + strconv.Itoa(limit)
+	p := &spotify.PlayHistoryCursorPage{Href: href, Items: []*spotify.PlayHistory{}, Limit: limit}
+
+	var older bool
+	var newest, oldest time.Time
+	for _, pl := range s.played {
+		ms := pl.at.UnixNano() / int64(time.Millisecond)
+		if before > 0 && ms >= before || ms <= after {
+			continue
+		}
+
+		if len(p.Items) == limit {
+			older = true
+			break
+		}
+
+		var context *spotify.PlaybackContext
+		if pl.context != "" {
+			context = &spotify.PlaybackContext{Type: string(pl.context.Kind()), URI: pl.context}
+		}
+		p.Items = append(p.Items, &spotify.PlayHistory{Track: s.track(pl.id), PlayedAt: pl.at.UTC().Format(playedAtFormat), Context: context})
+
+		if newest.IsZero() {
+			newest = pl.at
+		}
+		oldest = pl.at
+	}
+
+	if len(p.Items) > 0 {
+		p.Cursors = &spotify.Cursor{After: unixMillis(newest), Before: unixMillis(oldest)}
+		if older {
+			p.Next = href + "&before=" + p.Cursors.Before
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,432 @@
+package spotifytest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// playlist is the state of a playlist, every change makes a new version with a new snapshot ID
+type playlist struct {
+	id          spotify.ID
+	name        string
+	description string
+	owner       spotify.ID
+	public      bool
+	items       []saved
+	followers   map[spotify.ID]bool
+	version     int
+
+	// snapshots are all the snapshot IDs the playlist had
+	snapshots map[string]bool
+}
+
+func (p *playlist) snapshot() string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d,%s", p.version, p.id)))
+}
+
+// changed bumps the version after a change and returns the new snapshot ID
+func (p *playlist) changed() map[string]string {
+	p.version++
+	p.snapshots[p.snapshot()] = true
+	return map[string]string{"snapshot_id": p.snapshot()}
+}
+
+// checkSnapshot checks the snapshot ID a change is made against
+// Unlike the API, which applies positions to the version of the snapshot, the fake rejects outdated snapshots
+// so conflict handling can be tested.
+func (p *playlist) checkSnapshot(id string) error {
+	switch {
+	case id == "" || id == p.snapshot():
+		return nil
+	case p.snapshots[id]:
+		return errorf(http.StatusBadRequest, "Snapshot id is outdated")
+	}
+	return errorf(http.StatusBadRequest, "Invalid snapshot id")
+}
+
+func (s *Server) playlist(id spotify.ID) *playlist {
+	for _, p := range s.playlists {
+		if p.id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) simplePlaylist(p *playlist) *spotify.SimplePlaylist {
+	href := apiBase + "playlists/" + string(p.id)
+	return &spotify.SimplePlaylist{
+		Description: p.description,
+		Href:        href,
+		ID:          p.id,
+		Images:      []*spotify.Image{},
+		Name:        p.name,
+		Owner:       s.userObject(p.owner),
+		Public:      p.public,
+		SnapshotID:  p.snapshot(),
+		Tracks:      &spotify.Paging{Href: href + "/tracks", Total: len(p.items)},
+		Type:        "playlist",
+		URI:         p.id.URI(spotify.KindPlaylist),
+	}
+}
+
+func (s *Server) playlistTrack(p *playlist, i int) *spotify.PlaylistTrack {
+	item := p.items[i]
+	pt := &spotify.PlaylistTrack{AddedAt: timestamp(item.addedAt), AddedBy: s.userObject(p.owner)}
+	if t := s.track(item.id); t != nil {
+		pt.Track = &spotify.PlaylistItem{Track: t}
+	}
+	return pt
+}
+
+// playlistParam finds the playlist of the path, changing it needs to own it
+func (s *Server) playlistParam(args []string, change bool) (*playlist, error) {
+	p := s.playlist(spotify.ID(args[1]))
+	if p == nil || (!p.public && p.owner != s.user.ID) {
+		return nil, errorf(http.StatusNotFound, "Not found.")
+	}
+
+	if change && p.owner != s.user.ID {
+		return nil, errorf(http.StatusForbidden, "You cannot modify a playlist you don't own")
+	}
+	return p, nil
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf(http.StatusBadRequest, "Error parsing JSON.")
+	}
+	return nil
+}
+
+// trackURIs checks that the URIs are tracks of the catalog, the fake's playlists only hold tracks
+func (s *Server) trackURIs(uris []spotify.URI) ([]spotify.ID, error) {
+	if len(uris) > spotify.MaxPlaylistItems {
+		return nil, errorf(http.StatusBadRequest, "You can add a maximum of 100 tracks per request.")
+	}
+
+	ids := make([]spotify.ID, len(uris))
+	for i, u := range uris {
+		if u.Kind() != spotify.KindTrack || s.track(u.ID()) == nil {
+			return nil, errorf(http.StatusBadRequest, "Invalid track uri: %s", u)
+		}
+		ids[i] = u.ID()
+	}
+	return ids, nil
+}
+
+func (s *Server) getMyPlaylists(r *http.Request, args []string) (interface{}, error) {
+	var mine []*playlist
+	for _, p := range s.playlists {
+		if p.owner == s.user.ID || p.followers[s.user.ID] {
+			mine = append(mine, p)
+		}
+	}
+	return page(r, len(mine), 50, func(i int) interface{} { return s.simplePlaylist(mine[i]) })
+}
+
+func (s *Server) getUserPlaylists(r *http.Request, args []string) (interface{}, error) {
+	var owned []*playlist
+	for _, p := range s.playlists {
+		if p.owner == spotify.ID(args[0]) && (p.public || p.owner == s.user.ID) {
+			owned = append(owned, p)
+		}
+	}
+	return page(r, len(owned), 50, func(i int) interface{} { return s.simplePlaylist(owned[i]) })
+}
+
+func (s *Server) createPlaylist(r *http.Request, args []string) (interface{}, error) {
+	if spotify.ID(args[0]) != s.user.ID {
+		return nil, errorf(http.StatusForbidden, "You cannot create a playlist for another user")
+	}
+
+	var req struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      *bool  `json:"public"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, errorf(http.StatusBadRequest, "Missing required field: name")
+	}
+
+	s.created++
+	p := &playlist{
+		id:          spotify.ID(fmt.Sprintf("n%021d", s.created)),
+		name:        req.Name,
+		description: req.Description,
+		owner:       s.user.ID,
+		public:      req.Public == nil || *req.Public,
+		followers:   make(map[spotify.ID]bool),
+		snapshots:   make(map[string]bool),
+	}
+	p.snapshots[p.snapshot()] = true
+	s.playlists = append(s.playlists, p)
+
+	full, err := s.fullPlaylist(r, p)
+	if err != nil {
+		return nil, err
+	}
+	return created{full}, nil
+}
+
+func (s *Server) fullPlaylist(r *http.Request, p *playlist) (*spotify.FullPlaylist, error) {
+	full := &spotify.FullPlaylist{SimplePlaylist: *s.simplePlaylist(p), Followers: &spotify.Follower{Total: len(p.followers)}}
+
+	tracks, err := pageAt(r, len(p.items), spotify.MaxPlaylistItems, 0, func(i int) interface{} { return s.playlistTrack(p, i) })
+	if err != nil {
+		return nil, err
+	}
+	tracks.Href = full.Tracks.Href
+	full.Tracks = tracks
+	return full, nil
+}
+
+func (s *Server) getPlaylist(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.fullPlaylist(r, p)
+}
+
+func (s *Server) changePlaylist(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Public      *bool   `json:"public"`
+	}
+	if err = decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		p.name = *req.Name
+	}
+	if req.Description != nil {
+		p.description = *req.Description
+	}
+	if req.Public != nil {
+		p.public = *req.Public
+	}
+	p.changed()
+	return nil, nil
+}
+
+func (s *Server) getPlaylistTracks(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, false)
+	if err != nil {
+		return nil, err
+	}
+	return page(r, len(p.items), spotify.MaxPlaylistItems, func(i int) interface{} { return s.playlistTrack(p, i) })
+}
+
+func (s *Server) addPlaylistTracks(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req struct {
+		URIs     []spotify.URI `json:"uris"`
+		Position *int          `json:"position"`
+	}
+	if err = decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.trackURIs(req.URIs)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := len(p.items)
+	if req.Position != nil {
+		if *req.Position < 0 || *req.Position > len(p.items) {
+			return nil, errorf(http.StatusBadRequest, "Index out of bounds")
+		}
+		pos = *req.Position
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	added := make([]saved, len(ids))
+	for i, id := range ids {
+		added[i] = saved{id, now}
+	}
+
+	items := append(append(append([]saved(nil), p.items[:pos]...), added...), p.items[pos:]...)
+	p.items = items
+	return created{p.changed()}, nil
+}
+
+// putPlaylistTracks replaces the tracks, or reorders them when the body has a range_start
+func (s *Server) putPlaylistTracks(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req struct {
+		URIs         []spotify.URI `json:"uris"`
+		RangeStart   *int          `json:"range_start"`
+		InsertBefore int           `json:"insert_before"`
+		RangeLength  *int          `json:"range_length"`
+		SnapshotID   string        `json:"snapshot_id"`
+	}
+	if err = decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.RangeStart == nil {
+		ids, err := s.trackURIs(req.URIs)
+		if err != nil {
+			return nil, err
+		}
+
+		now := s.now().UTC().Truncate(time.Second)
+		p.items = make([]saved, len(ids))
+		for i, id := range ids {
+			p.items[i] = saved{id, now}
+		}
+		return p.changed(), nil
+	}
+
+	if err = p.checkSnapshot(req.SnapshotID); err != nil {
+		return nil, err
+	}
+
+	start, length, before := *req.RangeStart, 1, req.InsertBefore
+	if req.RangeLength != nil {
+		length = *req.RangeLength
+	}
+
+	n := len(p.items)
+	if start < 0 || length < 1 || start+length > n || before < 0 || before > n {
+		return nil, errorf(http.StatusBadRequest, "Index out of bounds")
+	}
+
+	if before >= start && before <= start+length {
+		// moving the range next to itself changes nothing, but still makes a new version
+		return p.changed(), nil
+	}
+
+	moved := append([]saved(nil), p.items[start:start+length]...)
+	rest := append(append([]saved(nil), p.items[:start]...), p.items[start+length:]...)
+	if before > start {
+		before -= length
+	}
+
+	p.items = append(append(append([]saved(nil), rest[:before]...), moved...), rest[before:]...)
+	return p.changed(), nil
+}
+
+func (s *Server) removePlaylistTracks(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req struct {
+		Tracks []struct {
+			URI       spotify.URI `json:"uri"`
+			Positions []int       `json:"positions"`
+		} `json:"tracks"`
+		SnapshotID string `json:"snapshot_id"`
+	}
+	if err = decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+
+	if len(req.Tracks) > spotify.MaxPlaylistItems {
+		return nil, errorf(http.StatusBadRequest, "You can remove a maximum of 100 tracks per request.")
+	}
+
+	if err = p.checkSnapshot(req.SnapshotID); err != nil {
+		return nil, err
+	}
+
+	remove := make(map[int]bool)
+	for _, t := range req.Tracks {
+		if len(t.Positions) == 0 {
+			for i, item := range p.items {
+				if item.id == t.URI.ID() {
+					remove[i] = true
+				}
+			}
+			continue
+		}
+
+		for _, pos := range t.Positions {
+			if pos < 0 || pos >= len(p.items) || p.items[pos].id != t.URI.ID() {
+				return nil, errorf(http.StatusBadRequest, "Could not remove tracks, please check parameters.")
+			}
+			remove[pos] = true
+		}
+	}
+
+	var items []saved
+	for i, item := range p.items {
+		if !remove[i] {
+			items = append(items, item)
+		}
+	}
+	p.items = items
+	return p.changed(), nil
+}
+
+func (s *Server) followPlaylist(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, false)
+	if err != nil {
+		return nil, err
+	}
+
+	p.followers[s.user.ID] = true
+	return nil, nil
+}
+
+func (s *Server) unfollowPlaylist(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, false)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(p.followers, s.user.ID)
+	return nil, nil
+}
+
+func (s *Server) containsPlaylistFollowers(r *http.Request, args []string) (interface{}, error) {
+	p, err := s.playlistParam(args, false)
+	if err != nil {
+		return nil, err
+	}
+
+	v := r.URL.Query().Get("ids")
+	if v == "" {
+		return nil, errorf(http.StatusBadRequest, "Missing parameter ids")
+	}
+
+	ids := strings.Split(v, ",")
+	if len(ids) > 5 {
+		return nil, errorf(http.StatusBadRequest, "Too many ids requested")
+	}
+
+	found := make([]bool, len(ids))
+	for i, id := range ids {
+		found[i] = p.followers[spotify.ID(id)]
+	}
+	return found, nil
+}
@@ -0,0 +1,218 @@
+package spotifytest
+
+import (
+	"net/http"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+func (s *Server) show(id spotify.ID) *spotify.Show {
+	sh, _ := s.byID[id].(*spotify.Show)
+	return sh
+}
+
+func (s *Server) episode(id spotify.ID) *spotify.Episode {
+	e, _ := s.byID[id].(*spotify.Episode)
+	return e
+}
+
+func (s *Server) audiobook(id spotify.ID) *spotify.Audiobook {
+	b, _ := s.byID[id].(*spotify.Audiobook)
+	return b
+}
+
+func (s *Server) chapter(id spotify.ID) *spotify.Chapter {
+	c, _ := s.byID[id].(*spotify.Chapter)
+	return c
+}
+
+// showEpisodes returns the episodes of a show as they are listed in it, without the show
+func (s *Server) showEpisodes(id spotify.ID) []*spotify.Episode {
+	var episodes []*spotify.Episode
+	for _, e := range s.episodes {
+		if e.Show != nil && e.Show.ID == id {
+			simple := *e
+			simple.Show = nil
+			episodes = append(episodes, &simple)
+		}
+	}
+	return episodes
+}
+
+// audiobookChapters returns the chapters of an audiobook in order, without the audiobook
+func (s *Server) audiobookChapters(id spotify.ID) []*spotify.Chapter {
+	var chapters []*spotify.Chapter
+	for _, c := range s.chapters {
+		if c.Audiobook != nil && c.Audiobook.ID == id {
+			simple := *c
+			simple.Audiobook = nil
+			chapters = append(chapters, &simple)
+		}
+	}
+	return chapters
+}
+
+// fullShow returns a copy of the show with the first page of its episodes
+func (s *Server) fullShow(r *http.Request, sh *spotify.Show) (*spotify.Show, error) {
+	episodes := s.showEpisodes(sh.ID)
+	p, err := pageAt(r, len(episodes), 50, 0, func(i int) interface{} { return episodes[i] })
+	if err != nil {
+		return nil, err
+	}
+
+	full := *sh
+	full.Episodes = p
+	full.Episodes.Href = apiBase + "shows/" + string(sh.ID) + "/episodes"
+	return &full, nil
+}
+
+// fullAudiobook returns a copy of the audiobook with the first page of its chapters
+func (s *Server) fullAudiobook(r *http.Request, b *spotify.Audiobook) (*spotify.Audiobook, error) {
+	chapters := s.audiobookChapters(b.ID)
+	p, err := pageAt(r, len(chapters), 50, 0, func(i int) interface{} { return chapters[i] })
+	if err != nil {
+		return nil, err
+	}
+
+	full := *b
+	full.Chapters = p
+	full.Chapters.Href = apiBase + "audiobooks/" + string(b.ID) + "/chapters"
+	return &full, nil
+}
+
+func (s *Server) getShow(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	sh := s.show(id)
+	if sh == nil {
+		return nil, notFound()
+	}
+	return s.fullShow(r, sh)
+}
+
+// getShows lists the shows without their episodes, like the API does
+func (s *Server) getShows(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	shows := make([]*spotify.Show, len(ids))
+	for i, id := range ids {
+		shows[i] = s.show(id)
+	}
+	return map[string]interface{}{"shows": shows}, nil
+}
+
+func (s *Server) getShowEpisodes(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if s.show(id) == nil {
+		return nil, notFound()
+	}
+
+	episodes := s.showEpisodes(id)
+	return page(r, len(episodes), 50, func(i int) interface{} { return episodes[i] })
+}
+
+func (s *Server) getEpisode(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	e := s.episode(id)
+	if e == nil {
+		return nil, notFound()
+	}
+	return e, nil
+}
+
+func (s *Server) getEpisodes(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := make([]*spotify.Episode, len(ids))
+	for i, id := range ids {
+		episodes[i] = s.episode(id)
+	}
+	return map[string]interface{}{"episodes": episodes}, nil
+}
+
+func (s *Server) getAudiobook(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	b := s.audiobook(id)
+	if b == nil {
+		return nil, notFound()
+	}
+	return s.fullAudiobook(r, b)
+}
+
+func (s *Server) getAudiobooks(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make([]*spotify.Audiobook, len(ids))
+	for i, id := range ids {
+		if b := s.audiobook(id); b != nil {
+			if books[i], err = s.fullAudiobook(r, b); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return map[string]interface{}{"audiobooks": books}, nil
+}
+
+func (s *Server) getAudiobookChapters(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if s.audiobook(id) == nil {
+		return nil, notFound()
+	}
+
+	chapters := s.audiobookChapters(id)
+	return page(r, len(chapters), 50, func(i int) interface{} { return chapters[i] })
+}
+
+func (s *Server) getChapter(r *http.Request, args []string) (interface{}, error) {
+	id, err := idParam(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	c := s.chapter(id)
+	if c == nil {
+		return nil, notFound()
+	}
+	return c, nil
+}
+
+func (s *Server) getChapters(r *http.Request, args []string) (interface{}, error) {
+	ids, err := idsParam(r, 50)
+	if err != nil {
+		return nil, err
+	}
+
+	chapters := make([]*spotify.Chapter, len(ids))
+	for i, id := range ids {
+		chapters[i] = s.chapter(id)
+	}
+	return map[string]interface{}{"chapters": chapters}, nil
+}
@@ -0,0 +1,686 @@
+// Package spotifytest runs an in-process fake of the Web API for hermetic tests of code using spotify.Client
+//
+// The fake serves a seeded Dataset over httptest: the client credentials token endpoint, the catalog,
+// podcasts, audiobooks, audio features, search, browse, recommendations, library, follow, playlist and player
+// endpoints, with the API's paging, error envelopes and snapshot IDs.
+//
+//	srv := spotifytest.NewServer(spotifytest.Seed())
+//	defer srv.Close()
+//
+//	c := srv.Client()
+//	tracks, err := c.AllSavedTracks("")
+package spotifytest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Krognol/go-spotify/spotify"
+)
+
+// Server is a fake Web API serving a Dataset
+// The library and playlists change as the clients modify them, the catalog stays as seeded.
+type Server struct {
+	// URL is the base URL of the server, clients from Client and AppClient are pointed at it
+	URL string
+
+	// ClientID and ClientSecret are the credentials the token endpoint accepts
+	ClientID     string
+	ClientSecret string
+
+	// UserToken is the access token of the dataset's user
+	UserToken string
+
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	now       func() time.Time
+	appTokens map[string]bool
+	requests  int
+	limited   int
+	retry     time.Duration
+
+	user       *spotify.User
+	artists    []*spotify.FullArtist
+	albums     []*spotify.FullAlbum
+	tracks     []*spotify.FullTrack
+	shows      []*spotify.Show
+	episodes   []*spotify.Episode
+	audiobooks []*spotify.Audiobook
+	chapters   []*spotify.Chapter
+	byID       map[spotify.ID]interface{}
+	related    map[spotify.ID][]spotify.ID
+	playlists  []*playlist
+	created    int
+
+	categories []*Category
+
+	savedTracks     []saved
+	savedAlbums     []saved
+	savedShows      []saved
+	savedEpisodes   []saved
+	savedAudiobooks []saved
+	followed        []spotify.ID
+
+	devices  []*spotify.Device
+	playback playback
+	played   []played
+}
+
+// saved is an item of the library
+type saved struct {
+	id      spotify.ID
+	addedAt time.Time
+}
+
+// NewServer starts a server with the dataset, Close it when done
+func NewServer(d *Dataset) *Server {
+	s := &Server{
+		ClientID:     "test-client-id",
+		ClientSecret: "test-client-secret",
+		UserToken:    "test-user-token",
+		now:          time.Now,
+		appTokens:    make(map[string]bool),
+		user:         d.User,
+		artists:      d.Artists,
+		albums:       d.Albums,
+		tracks:       d.Tracks,
+		shows:        d.Shows,
+		episodes:     d.Episodes,
+		audiobooks:   d.Audiobooks,
+		chapters:     d.Chapters,
+		byID:         make(map[spotify.ID]interface{}),
+		related:      d.Related,
+		categories:   d.Categories,
+	}
+
+	for _, a := range d.Artists {
+		s.byID[a.ID] = a
+	}
+	for _, a := range d.Albums {
+		s.byID[a.ID] = a
+	}
+	for _, t := range d.Tracks {
+		s.byID[t.ID] = t
+	}
+	for _, sh := range d.Shows {
+		s.byID[sh.ID] = sh
+	}
+	for _, e := range d.Episodes {
+		s.byID[e.ID] = e
+	}
+	for _, b := range d.Audiobooks {
+		s.byID[b.ID] = b
+	}
+	for _, c := range d.Chapters {
+		s.byID[c.ID] = c
+	}
+
+	// the seeded library is listed newest first, a second apart
+	added := time.Now().UTC().Truncate(time.Second)
+	library := func(ids []spotify.ID) []saved {
+		var items []saved
+		for i, id := range ids {
+			items = append(items, saved{id, added.Add(-time.Duration(i) * time.Second)})
+		}
+		return items
+	}
+	s.savedTracks = library(d.SavedTracks)
+	s.savedAlbums = library(d.SavedAlbums)
+	s.savedShows = library(d.SavedShows)
+	s.savedEpisodes = library(d.SavedEpisodes)
+	s.savedAudiobooks = library(d.SavedAudiobooks)
+	s.followed = append(s.followed, d.FollowedArtists...)
+
+	// the seeded plays are a track length apart, the newest a track length ago
+	for i, id := range d.RecentlyPlayed {
+		s.played = append(s.played, played{id: id, at: added.Add(-time.Duration(i+1) * 3 * time.Minute)})
+	}
+	for _, dev := range d.Devices {
+		copied := *dev
+		s.devices = append(s.devices, &copied)
+	}
+
+	for _, p := range d.Playlists {
+		pl := &playlist{
+			id:          p.ID,
+			name:        p.Name,
+			description: p.Description,
+			owner:       p.Owner,
+			public:      p.Public,
+			followers:   make(map[spotify.ID]bool),
+			snapshots:   make(map[string]bool),
+		}
+		for _, id := range p.Tracks {
+			pl.items = append(pl.items, saved{id, added})
+		}
+		for _, id := range p.Followers {
+			pl.followers[id] = true
+		}
+		pl.snapshots[pl.snapshot()] = true
+		s.playlists = append(s.playlists, pl)
+	}
+
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// HTTPClient returns an http.Client sending the requests for the API and accounts hosts to the server
+func (s *Server) HTTPClient() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &rewrite{target: target, base: s.srv.Client().Transport}}
+}
+
+// Client returns a client with the user's token
+func (s *Server) Client() *spotify.Client {
+	c := spotify.NewWithToken(s.UserToken)
+	c.HTTPClient = s.HTTPClient()
+	return c
+}
+
+// AppClient returns a client using the client credentials flow, it can only access the catalog
+func (s *Server) AppClient() *spotify.Client {
+	c := spotify.New(s.ClientID, s.ClientSecret)
+	c.HTTPClient = s.HTTPClient()
+	return c
+}
+
+// RateLimit makes the next n requests fail with 429 Too Many Requests and a Retry-After header
+func (s *Server) RateLimit(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limited, s.retry = n, retryAfter
+}
+
+// Requests is the number of requests the server has handled, the token endpoint included
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// SavedTracks returns the IDs of the saved tracks, newest first
+func (s *Server) SavedTracks() []spotify.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return savedIDs(s.savedTracks)
+}
+
+// SavedAlbums returns the IDs of the saved albums, newest first
+func (s *Server) SavedAlbums() []spotify.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return savedIDs(s.savedAlbums)
+}
+
+// SavedShows returns the IDs of the saved shows, newest first
+func (s *Server) SavedShows() []spotify.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return savedIDs(s.savedShows)
+}
+
+// SavedEpisodes returns the IDs of the saved episodes, newest first
+func (s *Server) SavedEpisodes() []spotify.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return savedIDs(s.savedEpisodes)
+}
+
+// SavedAudiobooks returns the IDs of the saved audiobooks, newest first
+func (s *Server) SavedAudiobooks() []spotify.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return savedIDs(s.savedAudiobooks)
+}
+
+// FollowedArtists returns the IDs of the followed artists
+func (s *Server) FollowedArtists() []spotify.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]spotify.ID(nil), s.followed...)
+}
+
+// PlaylistTracks returns the track IDs of a playlist in order and its current snapshot ID
+func (s *Server) PlaylistTracks(pid spotify.ID) ([]spotify.ID, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.playlist(pid)
+	if p == nil {
+		return nil, ""
+	}
+	return savedIDs(p.items), p.snapshot()
+}
+
+func savedIDs(items []saved) []spotify.ID {
+	ids := make([]spotify.ID, len(items))
+	for i, item := range items {
+		ids[i] = item.id
+	}
+	return ids
+}
+
+// rewrite sends requests for the real hosts to the server
+type rewrite struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *rewrite) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.URL.Host {
+	case "api.spotify.com", "accounts.spotify.com":
+		req = req.Clone(req.Context())
+		req.URL.Scheme, req.URL.Host, req.Host = t.target.Scheme, t.target.Host, t.target.Host
+	}
+	return t.base.RoundTrip(req)
+}
+
+// apiError is an error response in the API's envelope
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string { return e.message }
+
+func errorf(status int, format string, args ...interface{}) error {
+	return &apiError{status, fmt.Sprintf(format, args...)}
+}
+
+func notFound() error { return errorf(http.StatusNotFound, "Non existing id") }
+
+// created marks a handler result answered with 201 Created
+type created struct {
+	v interface{}
+}
+
+// noContent is a handler result answered with 204 No Content
+type noContent struct{}
+
+type handler func(r *http.Request, args []string) (interface{}, error)
+
+type route struct {
+	method string
+	path   []string
+	user   bool
+	h      handler
+}
+
+func (s *Server) routes() []route {
+	rs := []struct {
+		method, path string
+		user         bool
+		h            handler
+	}{
+		{"GET", "albums", false, s.getAlbums},
+		{"GET", "albums/:id", false, s.getAlbum},
+		{"GET", "albums/:id/tracks", false, s.getAlbumTracks},
+		{"GET", "artists", false, s.getArtists},
+		{"GET", "artists/:id", false, s.getArtist},
+		{"GET", "artists/:id/albums", false, s.getArtistAlbums},
+		{"GET", "artists/:id/top-tracks", false, s.getArtistTopTracks},
+		{"GET", "artists/:id/related-artists", false, s.getRelatedArtists},
+		{"GET", "tracks", false, s.getTracks},
+		{"GET", "tracks/:id", false, s.getTrack},
+		{"GET", "audio-features", false, s.getAudioFeatures},
+		{"GET", "audio-features/:id", false, s.getAudioFeature},
+		{"GET", "audio-analysis/:id", false, s.getAudioAnalysis},
+		{"GET", "shows", false, s.getShows},
+		{"GET", "shows/:id", false, s.getShow},
+		{"GET", "shows/:id/episodes", false, s.getShowEpisodes},
+		{"GET", "episodes", false, s.getEpisodes},
+		{"GET", "episodes/:id", false, s.getEpisode},
+		{"GET", "audiobooks", false, s.getAudiobooks},
+		{"GET", "audiobooks/:id", false, s.getAudiobook},
+		{"GET", "audiobooks/:id/chapters", false, s.getAudiobookChapters},
+		{"GET", "chapters", false, s.getChapters},
+		{"GET", "chapters/:id", false, s.getChapter},
+		{"GET", "search", false, s.search},
+		{"GET", "markets", false, s.getMarkets},
+
+		{"GET", "browse/featured-playlists", false, s.getFeaturedPlaylists},
+		{"GET", "browse/new-releases", false, s.getNewReleases},
+		{"GET", "browse/categories", false, s.getCategories},
+		{"GET", "browse/categories/:id", false, s.getCategory},
+		{"GET", "browse/categories/:id/playlists", false, s.getCategoryPlaylists},
+		{"GET", "recommendations", false, s.getRecommendations},
+		{"GET", "recommendations/available-genre-seeds", false, s.getGenreSeeds},
+
+		{"GET", "me", true, s.getMe},
+		{"GET", "me/playlists", true, s.getMyPlaylists},
+		{"GET", "me/tracks", true, s.getSavedTracks},
+		{"PUT", "me/tracks", true, s.saveTracks},
+		{"DELETE", "me/tracks", true, s.removeSavedTracks},
+		{"GET", "me/tracks/contains", true, s.containsSavedTracks},
+		{"GET", "me/albums", true, s.getSavedAlbums},
+		{"PUT", "me/albums", true, s.saveAlbums},
+		{"DELETE", "me/albums", true, s.removeSavedAlbums},
+		{"GET", "me/albums/contains", true, s.containsSavedAlbums},
+		{"GET", "me/shows", true, s.getSavedShows},
+		{"PUT", "me/shows", true, s.saveShows},
+		{"DELETE", "me/shows", true, s.removeSavedShows},
+		{"GET", "me/shows/contains", true, s.containsSavedShows},
+		{"GET", "me/episodes", true, s.getSavedEpisodes},
+		{"PUT", "me/episodes", true, s.saveEpisodes},
+		{"DELETE", "me/episodes", true, s.removeSavedEpisodes},
+		{"GET", "me/episodes/contains", true, s.containsSavedEpisodes},
+		{"GET", "me/audiobooks", true, s.getSavedAudiobooks},
+		{"PUT", "me/audiobooks", true, s.saveAudiobooks},
+		{"DELETE", "me/audiobooks", true, s.removeSavedAudiobooks},
+		{"GET", "me/audiobooks/contains", true, s.containsSavedAudiobooks},
+		{"GET", "me/following", true, s.getFollowing},
+		{"PUT", "me/following", true, s.follow},
+		{"DELETE", "me/following", true, s.unfollow},
+		{"GET", "me/following/contains", true, s.containsFollowing},
+		{"GET", "me/player", true, s.getPlaybackState},
+		{"PUT", "me/player", true, s.transferPlayback},
+		{"GET", "me/player/currently-playing", true, s.getCurrentlyPlaying},
+		{"GET", "me/player/devices", true, s.getDevices},
+		{"PUT", "me/player/play", true, s.play},
+		{"PUT", "me/player/pause", true, s.pause},
+		{"POST", "me/player/next", true, s.next},
+		{"POST", "me/player/previous", true, s.previous},
+		{"PUT", "me/player/seek", true, s.seek},
+		{"PUT", "me/player/repeat", true, s.setRepeat},
+		{"PUT", "me/player/volume", true, s.setVolume},
+		{"PUT", "me/player/shuffle", true, s.setShuffle},
+		{"GET", "me/player/queue", true, s.getQueue},
+		{"POST", "me/player/queue", true, s.addToQueue},
+		{"GET", "me/player/recently-played", true, s.getRecentlyPlayed},
+
+		{"GET", "users/:uid", false, s.getUser},
+		{"GET", "users/:uid/playlists", false, s.getUserPlaylists},
+		{"POST", "users/:uid/playlists", true, s.createPlaylist},
+		{"GET", "users/:uid/playlists/:pid", false, s.getPlaylist},
+		{"PUT", "users/:uid/playlists/:pid", true, s.changePlaylist},
+		{"GET", "users/:uid/playlists/:pid/tracks", false, s.getPlaylistTracks},
+		{"POST", "users/:uid/playlists/:pid/tracks", true, s.addPlaylistTracks},
+		{"PUT", "users/:uid/playlists/:pid/tracks", true, s.putPlaylistTracks},
+		{"DELETE", "users/:uid/playlists/:pid/tracks", true, s.removePlaylistTracks},
+		{"PUT", "users/:uid/playlists/:pid/followers", true, s.followPlaylist},
+		{"DELETE", "users/:uid/playlists/:pid/followers", true, s.unfollowPlaylist},
+		{"GET", "users/:uid/playlists/:pid/followers/contains", false, s.containsPlaylistFollowers},
+	}
+
+	routes := make([]route, len(rs))
+	for i, r := range rs {
+		routes[i] = route{r.method, strings.Split(r.path, "/"), r.user, r.h}
+	}
+	return routes
+}
+
+// match returns the values of the :params of the path if it matches the route
+func (rt *route) match(path []string) ([]string, bool) {
+	if len(path) != len(rt.path) {
+		return nil, false
+	}
+
+	var args []string
+	for i, p := range rt.path {
+		switch {
+		case strings.HasPrefix(p, ":"):
+			args = append(args, path[i])
+		case p != path[i]:
+			return nil, false
+		}
+	}
+	return args, true
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	if s.limited > 0 {
+		s.limited--
+		w.Header().Set("Retry-After", strconv.Itoa(int(s.retry.Seconds())))
+		writeError(w, errorf(http.StatusTooManyRequests, "API rate limit exceeded"))
+		return
+	}
+
+	if r.URL.Path == "/api/token" {
+		s.token(w, r)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, "/v1/") {
+		writeError(w, errorf(http.StatusNotFound, "Service not found"))
+		return
+	}
+
+	user, err := s.authenticate(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/"), "/"), "/")
+	matched := false
+	for _, rt := range s.routes() {
+		args, ok := rt.match(path)
+		if !ok {
+			continue
+		}
+
+		matched = true
+		if rt.method != r.Method {
+			continue
+		}
+
+		if rt.user && !user {
+			writeError(w, errorf(http.StatusUnauthorized, "Valid user authentication required"))
+			return
+		}
+
+		v, err := rt.h(r, args)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, v)
+		return
+	}
+
+	if matched {
+		writeError(w, errorf(http.StatusMethodNotAllowed, "Method not allowed"))
+		return
+	}
+	writeError(w, errorf(http.StatusNotFound, "Service not found"))
+}
+
+// authenticate checks the bearer token and reports whether it is the user's
+func (s *Server) authenticate(r *http.Request) (bool, error) {
+	h := r.Header.Get("Authorization")
+	if h == "" {
+		return false, errorf(http.StatusUnauthorized, "No token provided")
+	}
+
+	token := strings.TrimPrefix(h, "Bearer ")
+	switch {
+	case token == s.UserToken:
+		return true, nil
+	case s.appTokens[token]:
+		return false, nil
+	}
+	return false, errorf(http.StatusUnauthorized, "Invalid access token")
+}
+
+// token is the client credentials flow of the accounts service
+// Its errors are OAuth errors, not the API's envelope.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if r.Method != "POST" || !ok || id != s.ClientID || secret != s.ClientSecret {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client", "error_description": "Invalid client"})
+		return
+	}
+
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "unsupported_grant_type", "error_description": "grant_type must be client_credentials"})
+		return
+	}
+
+	token := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("app-%d", len(s.appTokens)+1)))
+	s.appTokens[token] = true
+	writeJSON(w, map[string]interface{}{"access_token": token, "token_type": "Bearer", "expires_in": 3600})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	status := http.StatusOK
+	if c, ok := v.(created); ok {
+		status, v = http.StatusCreated, c.v
+	}
+
+	if _, ok := v.(noContent); ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if v == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*apiError)
+	if !ok {
+		e = &apiError{http.StatusInternalServerError, err.Error()}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(e.status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{"status": e.status, "message": e.message},
+	})
+}
+
+// pageParams reads the limit and offset of a request, limit defaults to 20
+func pageParams(r *http.Request, maxLimit, maxOffset int) (int, int, error) {
+	limit, offset := 20, 0
+	q := r.URL.Query()
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLimit {
+			return 0, 0, errorf(http.StatusBadRequest, "Invalid limit")
+		}
+		limit = n
+	}
+
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || (maxOffset > 0 && n > maxOffset) {
+			return 0, 0, errorf(http.StatusBadRequest, "Invalid offset")
+		}
+		offset = n
+	}
+	return limit, offset, nil
+}
+
+// page makes the page of n items the request asks for, item returns the item at an index
+func page(r *http.Request, n, maxLimit int, item func(i int) interface{}) (*spotify.Paging, error) {
+	limit, offset, err := pageParams(r, maxLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+	return pageAt(r, n, limit, offset, item)
+}
+
+func pageAt(r *http.Request, n, limit, offset int, item func(i int) interface{}) (*spotify.Paging, error) {
+	items := []interface{}{}
+	for i := offset; i < n && i < offset+limit; i++ {
+		items = append(items, item(i))
+	}
+
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &spotify.Paging{
+		Href:   pageURL(r, limit, offset),
+		Items:  raw,
+		Limit:  limit,
+		Offset: offset,
+		Total:  n,
+	}
+
+	if offset+limit < n {
+		p.Next = pageURL(r, limit, offset+limit)
+	}
+
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		p.Previous = pageURL(r, limit, prev)
+	}
+	return p, nil
+}
+
+// pageURL is the URL of the request on the real host with another limit and offset
+func pageURL(r *http.Request, limit, offset int) string {
+	q := r.URL.Query()
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("offset", strconv.Itoa(offset))
+	return "https://api.spotify.com" + r.URL.Path + "?" + q.Encode()
+}
+
+// idsParam reads the comma separated ids parameter
+func idsParam(r *http.Request, max int) ([]spotify.ID, error) {
+	v := r.URL.Query().Get("ids")
+	if v == "" {
+		return nil, errorf(http.StatusBadRequest, "Missing parameter ids")
+	}
+
+	parts := strings.Split(v, ",")
+	if len(parts) > max {
+		return nil, errorf(http.StatusBadRequest, "Too many ids requested")
+	}
+
+	ids := make([]spotify.ID, len(parts))
+	for i, p := range parts {
+		ids[i] = spotify.ID(p)
+		if !ids[i].Valid() {
+			return nil, errorf(http.StatusBadRequest, "Invalid base62 id")
+		}
+	}
+	return ids, nil
+}
+
+// idParam checks the ID of a path
+func idParam(id string) (spotify.ID, error) {
+	if !spotify.ID(id).Valid() {
+		return "", errorf(http.StatusBadRequest, "Invalid base62 id")
+	}
+	return spotify.ID(id), nil
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,1007 @@
+package spotifytest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Krognol/go-spotify/spotify"
+	"github.com/Krognol/go-spotify/spotify/spotifytest"
+)
+
+// dataset is the seed with a show and an audiobook that aren't saved, and a followed playlist
+func dataset() *spotifytest.Dataset {
+	d := spotifytest.Seed()
+	d.AddShow("Another Podcast", "Test Publisher", "Only Episode")
+	d.AddAudiobook("Another Book", "Test Author", "Prologue")
+	d.Playlists[1].Followers = []spotify.ID{d.User.ID, "friend"}
+	return d
+}
+
+func expect(ok bool, format string, args ...interface{}) error {
+	if ok {
+		return nil
+	}
+	return fmt.Errorf("got "+format, args...)
+}
+
+func expectIDs(got, want []spotify.ID) error {
+	return expect(reflect.DeepEqual(got, want), "%v, want %v", got, want)
+}
+
+func items(p *spotify.Paging, v interface{}) error {
+	if p == nil {
+		return fmt.Errorf("got no page")
+	}
+	return json.Unmarshal(p.Items, v)
+}
+
+// play starts Discovery on the computer
+func play(c *spotify.Client, d *spotifytest.Dataset) error {
+	return c.Play(d.Devices[0].ID, &spotify.PlayOptions{ContextURI: d.Albums[0].URI})
+}
+
+func playing(c *spotify.Client) (string, error) {
+	state, err := c.GetPlaybackState("")
+	if err != nil {
+		return "", err
+	}
+	if state == nil || state.Item == nil {
+		return "", fmt.Errorf("got nothing playing")
+	}
+	if state.Item.Episode != nil {
+		return state.Item.Episode.Name, nil
+	}
+	return state.Item.Track.Name, nil
+}
+
+func status(err error) int {
+	if e, ok := err.(*spotify.SpotifyError); ok {
+		return e.Status
+	}
+	return 0
+}
+
+// clientCalls runs every method of the client against a server with the dataset, a call checks what it got
+var clientCalls = []struct {
+	method string
+	run    func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error
+}{
+	{"GetAlbum", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		a, err := c.GetAlbum(d.Albums[0].ID)
+		if err != nil {
+			return err
+		}
+		return expect(a.Name == "Discovery" && a.Tracks.Total == 4, "%s with %d tracks", a.Name, a.Tracks.Total)
+	}},
+	{"GetAlbums", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		albums, err := c.GetAlbums([]spotify.ID{d.Albums[0].ID, d.Albums[2].ID})
+		if err != nil {
+			return err
+		}
+		return expect(len(albums) == 2 && albums[1].Name == "Kind of Blue", "%d albums", len(albums))
+	}},
+	{"GetAlbumTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetAlbumTracks(d.Albums[0].ID, 2, 1)
+		if err != nil {
+			return err
+		}
+
+		var tracks []*spotify.SimpleTrack
+		if err = items(p, &tracks); err != nil {
+			return err
+		}
+		return expect(p.Total == 4 && len(tracks) == 2 && tracks[0].Name == "Aerodynamic", "%d of %d tracks", len(tracks), p.Total)
+	}},
+	{"GetArtist", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		a, err := c.GetArtist(d.Artists[0].ID)
+		if err != nil {
+			return err
+		}
+		return expect(a.Name == "Daft Punk", "%s", a.Name)
+	}},
+	{"GetArtists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		artists, err := c.GetArtists([]spotify.ID{d.Artists[0].ID, d.Artists[2].ID})
+		if err != nil {
+			return err
+		}
+		return expect(len(artists) == 2 && artists[1].Name == "Miles Davis", "%d artists", len(artists))
+	}},
+	{"GetArtistAlbums", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetArtistAlbums(d.Artists[1].ID, 20, 0)
+		if err != nil {
+			return err
+		}
+
+		var albums []*spotify.SimpleAlbum
+		if err = items(p, &albums); err != nil {
+			return err
+		}
+		return expect(len(albums) == 1 && albums[0].Name == "Cross", "%d albums", len(albums))
+	}},
+	{"GetArtistTopTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		tracks, err := c.GetArtistTopTracks(d.Artists[2].ID, "SE")
+		if err != nil {
+			return err
+		}
+		return expect(len(tracks) == 3, "%d tracks", len(tracks))
+	}},
+	{"GetRelatedArtists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		artists, err := c.GetRelatedArtists(d.Artists[0].ID)
+		if err != nil {
+			return err
+		}
+		return expect(len(artists) == 1 && artists[0].Name == "Justice", "%d artists", len(artists))
+	}},
+	{"GetTrack", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		t, err := c.GetTrack(d.Tracks[2].ID, "")
+		if err != nil {
+			return err
+		}
+		return expect(t.Name == "Digital Love", "%s", t.Name)
+	}},
+	{"GetTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		tracks, err := c.GetTracks([]spotify.ID{d.Tracks[0].ID, d.Tracks[9].ID}, "")
+		if err != nil {
+			return err
+		}
+		return expect(len(tracks) == 2 && tracks[1].Name == "Blue in Green", "%d tracks", len(tracks))
+	}},
+	{"GetAudioAnalysis", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		a, err := c.GetAudioAnalysis(d.Tracks[0].ID)
+		if err != nil {
+			return err
+		}
+		return expect(a.Track.Duration == 180 && len(a.Sections) == 1 && len(a.Bars)*4 >= len(a.Beats), "%v s, %d sections, %d bars, %d beats",
+			a.Track.Duration, len(a.Sections), len(a.Bars), len(a.Beats))
+	}},
+	{"GetAudioFeature", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		f, err := c.GetAudioFeature(d.Tracks[0].ID)
+		if err != nil {
+			return err
+		}
+		return expect(f.ID == d.Tracks[0].ID && f.Tempo > 0 && f.TimeSignature == 4, "%+v", f)
+	}},
+	{"GetAudioFeatures", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		features, err := c.GetAudioFeatures([]spotify.ID{d.Tracks[0].ID, d.Albums[0].ID})
+		if err != nil {
+			return err
+		}
+		return expect(len(features) == 2 && features[0] != nil && features[1] == nil, "%v", features)
+	}},
+
+	{"GetShow", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		s, err := c.GetShow(d.Shows[0].ID, "")
+		if err != nil {
+			return err
+		}
+		return expect(s.Name == "Test Podcast" && s.Episodes.Total == 3, "%s with %d episodes", s.Name, s.Episodes.Total)
+	}},
+	{"GetShows", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		shows, err := c.GetShows([]spotify.ID{d.Shows[0].ID, d.Shows[1].ID}, "")
+		if err != nil {
+			return err
+		}
+		return expect(len(shows) == 2 && shows[1].Name == "Another Podcast", "%d shows", len(shows))
+	}},
+	{"GetShowEpisodes", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetShowEpisodes(d.Shows[0].ID, "", 2, 0)
+		if err != nil {
+			return err
+		}
+
+		var episodes []*spotify.Episode
+		if err = items(p, &episodes); err != nil {
+			return err
+		}
+		return expect(p.Total == 3 && len(episodes) == 2 && episodes[0].Name == "Pilot", "%d of %d episodes", len(episodes), p.Total)
+	}},
+	{"GetEpisode", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		e, err := c.GetEpisode(d.Episodes[1].ID, "")
+		if err != nil {
+			return err
+		}
+		return expect(e.Name == "Second Episode" && e.Show.ID == d.Shows[0].ID && e.ReleaseDate.String() == "2020-01-02",
+			"%s released %s", e.Name, e.ReleaseDate)
+	}},
+	{"GetEpisodes", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		episodes, err := c.GetEpisodes([]spotify.ID{d.Episodes[0].ID, d.Episodes[2].ID}, "")
+		if err != nil {
+			return err
+		}
+		return expect(len(episodes) == 2 && episodes[1].Name == "Third Episode", "%d episodes", len(episodes))
+	}},
+	{"GetSavedShows", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetSavedShows(20, 0)
+		if err != nil {
+			return err
+		}
+
+		var shows []*spotify.SavedShow
+		if err = items(p, &shows); err != nil {
+			return err
+		}
+		return expect(len(shows) == 1 && shows[0].Show.ID == d.Shows[0].ID, "%d shows", len(shows))
+	}},
+	{"AllSavedShows", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		shows, err := c.AllSavedShows()
+		if err != nil {
+			return err
+		}
+		return expect(len(shows) == 1, "%d shows", len(shows))
+	}},
+	{"SaveShows", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.SaveShows([]spotify.ID{d.Shows[1].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedShows(), []spotify.ID{d.Shows[1].ID, d.Shows[0].ID})
+	}},
+	{"RemoveSavedShows", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.RemoveSavedShows([]spotify.ID{d.Shows[0].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedShows(), []spotify.ID{})
+	}},
+	{"HasShowsSaved", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		saved, err := c.HasShowsSaved([]spotify.ID{d.Shows[0].ID, d.Shows[1].ID})
+		if err != nil {
+			return err
+		}
+		return expect(reflect.DeepEqual(saved, []bool{true, false}), "%v", saved)
+	}},
+	{"GetSavedEpisodes", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetSavedEpisodes("", 20, 0)
+		if err != nil {
+			return err
+		}
+
+		var episodes []*spotify.SavedEpisode
+		if err = items(p, &episodes); err != nil {
+			return err
+		}
+		return expect(len(episodes) == 1 && episodes[0].Episode.ID == d.Episodes[0].ID, "%d episodes", len(episodes))
+	}},
+	{"SaveEpisodes", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.SaveEpisodes([]spotify.ID{d.Episodes[1].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedEpisodes(), []spotify.ID{d.Episodes[1].ID, d.Episodes[0].ID})
+	}},
+	{"RemoveSavedEpisodes", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.RemoveSavedEpisodes([]spotify.ID{d.Episodes[0].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedEpisodes(), []spotify.ID{})
+	}},
+	{"HasEpisodesSaved", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		saved, err := c.HasEpisodesSaved([]spotify.ID{d.Episodes[0].ID, d.Episodes[1].ID})
+		if err != nil {
+			return err
+		}
+		return expect(reflect.DeepEqual(saved, []bool{true, false}), "%v", saved)
+	}},
+
+	{"GetAudiobook", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		b, err := c.GetAudiobook(d.Audiobooks[0].ID, "")
+		if err != nil {
+			return err
+		}
+		return expect(b.Name == "Test Book" && b.Chapters.Total == 2, "%s with %d chapters", b.Name, b.Chapters.Total)
+	}},
+	{"GetAudiobooks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		books, err := c.GetAudiobooks([]spotify.ID{d.Audiobooks[0].ID, d.Audiobooks[1].ID}, "")
+		if err != nil {
+			return err
+		}
+		return expect(len(books) == 2 && books[1].Name == "Another Book", "%d audiobooks", len(books))
+	}},
+	{"GetAudiobookChapters", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetAudiobookChapters(d.Audiobooks[0].ID, "", 20, 0)
+		if err != nil {
+			return err
+		}
+
+		var chapters []*spotify.Chapter
+		if err = items(p, &chapters); err != nil {
+			return err
+		}
+		return expect(len(chapters) == 2 && chapters[0].ChapterNumber == 1, "%d chapters", len(chapters))
+	}},
+	{"GetChapter", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		ch, err := c.GetChapter(d.Chapters[1].ID, "")
+		if err != nil {
+			return err
+		}
+		return expect(ch.Name == "Chapter Two" && ch.Audiobook.ID == d.Audiobooks[0].ID, "%s", ch.Name)
+	}},
+	{"GetChapters", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		chapters, err := c.GetChapters([]spotify.ID{d.Chapters[0].ID, d.Chapters[1].ID}, "")
+		if err != nil {
+			return err
+		}
+		return expect(len(chapters) == 2 && chapters[0].Name == "Chapter One", "%d chapters", len(chapters))
+	}},
+	{"GetSavedAudiobooks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetSavedAudiobooks(20, 0)
+		if err != nil {
+			return err
+		}
+
+		var books []*spotify.SavedAudiobook
+		if err = items(p, &books); err != nil {
+			return err
+		}
+		return expect(len(books) == 1 && books[0].Audiobook.ID == d.Audiobooks[0].ID, "%d audiobooks", len(books))
+	}},
+	{"SaveAudiobooks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.SaveAudiobooks([]spotify.ID{d.Audiobooks[1].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedAudiobooks(), []spotify.ID{d.Audiobooks[1].ID, d.Audiobooks[0].ID})
+	}},
+	{"RemoveSavedAudiobooks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.RemoveSavedAudiobooks([]spotify.ID{d.Audiobooks[0].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedAudiobooks(), []spotify.ID{})
+	}},
+	{"HasAudiobooksSaved", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		saved, err := c.HasAudiobooksSaved([]spotify.ID{d.Audiobooks[0].ID, d.Audiobooks[1].ID})
+		if err != nil {
+			return err
+		}
+		return expect(reflect.DeepEqual(saved, []bool{true, false}), "%v", saved)
+	}},
+
+	{"GetSavedTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetSavedTracks("", 20, 0)
+		if err != nil {
+			return err
+		}
+
+		var tracks []*spotify.SavedTrack
+		if err = items(p, &tracks); err != nil {
+			return err
+		}
+		return expect(len(tracks) == 2 && tracks[0].Track.ID == d.Tracks[0].ID, "%d tracks", len(tracks))
+	}},
+	{"AllSavedTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		tracks, err := c.AllSavedTracks("")
+		if err != nil {
+			return err
+		}
+		return expect(len(tracks) == 2, "%d tracks", len(tracks))
+	}},
+	{"SaveTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.SaveTracks([]spotify.ID{d.Tracks[2].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedTracks(), []spotify.ID{d.Tracks[2].ID, d.Tracks[0].ID, d.Tracks[1].ID})
+	}},
+	{"RemoveSavedTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.RemoveSavedTracks([]spotify.ID{d.Tracks[0].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedTracks(), []spotify.ID{d.Tracks[1].ID})
+	}},
+	{"HasTracksSaved", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		saved, err := c.HasTracksSaved([]spotify.ID{d.Tracks[0].ID, d.Tracks[2].ID})
+		if err != nil {
+			return err
+		}
+		return expect(reflect.DeepEqual(saved, []bool{true, false}), "%v", saved)
+	}},
+	{"GetSavedAlbums", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetSavedAlbums("", 20, 0)
+		if err != nil {
+			return err
+		}
+
+		var albums []*spotify.SavedAlbum
+		if err = items(p, &albums); err != nil {
+			return err
+		}
+		return expect(len(albums) == 1 && albums[0].Album.Name == "Kind of Blue", "%d albums", len(albums))
+	}},
+	{"AllSavedAlbums", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		albums, err := c.AllSavedAlbums("")
+		if err != nil {
+			return err
+		}
+		return expect(len(albums) == 1, "%d albums", len(albums))
+	}},
+	{"SaveAlbums", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.SaveAlbums([]spotify.ID{d.Albums[0].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedAlbums(), []spotify.ID{d.Albums[0].ID, d.Albums[2].ID})
+	}},
+	{"RemoveSavedAlbums", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.RemoveSavedAlbums([]spotify.ID{d.Albums[2].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.SavedAlbums(), []spotify.ID{})
+	}},
+	{"HasAlbumsSaved", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		saved, err := c.HasAlbumsSaved([]spotify.ID{d.Albums[0].ID, d.Albums[2].ID})
+		if err != nil {
+			return err
+		}
+		return expect(reflect.DeepEqual(saved, []bool{false, true}), "%v", saved)
+	}},
+
+	{"GetCurrentUser", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		u, err := c.GetCurrentUser()
+		if err != nil {
+			return err
+		}
+		return expect(u.ID == d.User.ID, "%s", u.ID)
+	}},
+	{"GetMyPlaylists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetMyPlaylists(1, 1)
+		if err != nil {
+			return err
+		}
+
+		var playlists []*spotify.SimplePlaylist
+		if err = items(p, &playlists); err != nil {
+			return err
+		}
+		return expect(p.Total == 2 && len(playlists) == 1 && playlists[0].Name == "Late Night", "%d of %d playlists", len(playlists), p.Total)
+	}},
+	{"AllMyPlaylists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		playlists, err := c.AllMyPlaylists()
+		if err != nil {
+			return err
+		}
+		return expect(len(playlists) == 2, "%d playlists", len(playlists))
+	}},
+	{"GetFollowedArtists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetFollowedArtists(1, "")
+		if err != nil {
+			return err
+		}
+		return expect(len(p.Items) == 1 && p.Items[0].Name == "Daft Punk" && p.Cursors.After != "", "%d artists after %q", len(p.Items), p.Cursors.After)
+	}},
+	{"AllFollowedArtists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		artists, err := c.AllFollowedArtists()
+		if err != nil {
+			return err
+		}
+		return expect(len(artists) == 2, "%d artists", len(artists))
+	}},
+	{"FollowArtists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.FollowArtists([]spotify.ID{d.Artists[1].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.FollowedArtists(), []spotify.ID{d.Artists[0].ID, d.Artists[2].ID, d.Artists[1].ID})
+	}},
+	{"UnfollowArtists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.UnfollowArtists([]spotify.ID{d.Artists[0].ID}); err != nil {
+			return err
+		}
+		return expectIDs(srv.FollowedArtists(), []spotify.ID{d.Artists[2].ID})
+	}},
+
+	{"GetPlaylist", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetPlaylist(d.User.ID, d.Playlists[0].ID)
+		if err != nil {
+			return err
+		}
+		return expect(p.Name == "French Touch" && p.Tracks.Total == 7, "%s with %d tracks", p.Name, p.Tracks.Total)
+	}},
+	{"CreatePlaylist", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.CreatePlaylist(d.User.ID, "New", "Made in a test", false)
+		if err != nil {
+			return err
+		}
+
+		_, snapshot := srv.PlaylistTracks(p.ID)
+		return expect(p.Name == "New" && !p.Public && snapshot == p.SnapshotID, "%s, public %v, snapshot %q", p.Name, p.Public, p.SnapshotID)
+	}},
+	{"GetPlaylistTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetPlaylistTracks(d.User.ID, d.Playlists[1].ID, 2, 1)
+		if err != nil {
+			return err
+		}
+
+		var tracks []*spotify.PlaylistTrack
+		if err = items(p, &tracks); err != nil {
+			return err
+		}
+		return expect(p.Total == 3 && len(tracks) == 2 && tracks[0].Track.Track.ID == d.Tracks[8].ID, "%d of %d tracks", len(tracks), p.Total)
+	}},
+	{"GetPlaylistTracksForMarket", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetPlaylistTracksForMarket(d.User.ID, d.Playlists[1].ID, "SE", 2, 1)
+		if err != nil {
+			return err
+		}
+
+		var tracks []*spotify.PlaylistTrack
+		if err = items(p, &tracks); err != nil {
+			return err
+		}
+		return expect(p.Total == 3 && len(tracks) == 2 && tracks[0].Track.Track.ID == d.Tracks[8].ID, "%d of %d tracks", len(tracks), p.Total)
+	}},
+	{"AllPlaylistTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		tracks, err := c.AllPlaylistTracks(d.User.ID, d.Playlists[0].ID)
+		if err != nil {
+			return err
+		}
+		return expect(len(tracks) == 7, "%d tracks", len(tracks))
+	}},
+	{"AllPlaylistTracksForMarket", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		tracks, err := c.AllPlaylistTracksForMarket(d.User.ID, d.Playlists[0].ID, "SE")
+		if err != nil {
+			return err
+		}
+		return expect(len(tracks) == 7, "%d tracks", len(tracks))
+	}},
+	{"AddTracksToPlaylist", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		snapshot, err := c.AddTracksToPlaylist(d.User.ID, d.Playlists[1].ID, []spotify.URI{d.Tracks[0].URI}, 0)
+		if err != nil {
+			return err
+		}
+
+		ids, current := srv.PlaylistTracks(d.Playlists[1].ID)
+		if err = expectIDs(ids, []spotify.ID{d.Tracks[0].ID, d.Tracks[7].ID, d.Tracks[8].ID, d.Tracks[9].ID}); err != nil {
+			return err
+		}
+		return expect(snapshot == current, "snapshot %q, want %q", snapshot, current)
+	}},
+	{"ReplacePlaylistTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if _, err := c.ReplacePlaylistTracks(d.User.ID, d.Playlists[1].ID, []spotify.URI{d.Tracks[0].URI}); err != nil {
+			return err
+		}
+
+		ids, _ := srv.PlaylistTracks(d.Playlists[1].ID)
+		return expectIDs(ids, []spotify.ID{d.Tracks[0].ID})
+	}},
+	{"RemovePlaylistTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		_, snapshot := srv.PlaylistTracks(d.Playlists[1].ID)
+		remove := []spotify.TrackPositions{{URI: d.Tracks[7].URI, Positions: []int{0}}}
+		if _, err := c.RemovePlaylistTracks(d.User.ID, d.Playlists[1].ID, remove, snapshot); err != nil {
+			return err
+		}
+
+		ids, _ := srv.PlaylistTracks(d.Playlists[1].ID)
+		return expectIDs(ids, []spotify.ID{d.Tracks[8].ID, d.Tracks[9].ID})
+	}},
+	{"ReorderPlaylistTracks", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		_, snapshot := srv.PlaylistTracks(d.Playlists[1].ID)
+		if _, err := c.ReorderPlaylistTracks(d.User.ID, d.Playlists[1].ID, 0, 3, 1, snapshot); err != nil {
+			return err
+		}
+
+		ids, _ := srv.PlaylistTracks(d.Playlists[1].ID)
+		return expectIDs(ids, []spotify.ID{d.Tracks[8].ID, d.Tracks[9].ID, d.Tracks[7].ID})
+	}},
+	{"UserFollowPlaylist", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.UserFollowPlaylist(d.User.ID, d.Playlists[0].ID); err != nil {
+			return err
+		}
+
+		p, err := c.GetPlaylist(d.User.ID, d.Playlists[0].ID)
+		if err != nil {
+			return err
+		}
+		return expect(p.Followers.Total == 1, "%d followers", p.Followers.Total)
+	}},
+	{"UserUnfollowPlaylist", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.UserUnfollowPlaylist(d.User.ID, d.Playlists[1].ID); err != nil {
+			return err
+		}
+
+		p, err := c.GetPlaylist(d.User.ID, d.Playlists[1].ID)
+		if err != nil {
+			return err
+		}
+		return expect(p.Followers.Total == 1, "%d followers", p.Followers.Total)
+	}},
+	{"UsersFollowsPlaylist", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		follows, err := c.UsersFollowsPlaylist(d.User.ID, d.Playlists[1].ID, []spotify.ID{d.User.ID, "friend", "stranger"})
+		if err != nil {
+			return err
+		}
+		return expect(reflect.DeepEqual(follows, []bool{true, true, false}), "%v", follows)
+	}},
+
+	{"GetPlaybackState", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		state, err := c.GetPlaybackState("")
+		if err != nil || state != nil {
+			return expect(false, "%v, %v before playing", state, err)
+		}
+
+		if err = play(c, d); err != nil {
+			return err
+		}
+		if state, err = c.GetPlaybackState("SE"); err != nil {
+			return err
+		}
+		return expect(state.IsPlaying && state.Device.Name == "Test Computer" && state.Item.Track.Name == "One More Time" &&
+			state.Context.URI == d.Albums[0].URI && state.RepeatState == "off", "%+v", state)
+	}},
+	{"GetCurrentlyPlaying", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		cur, err := c.GetCurrentlyPlaying("")
+		if err != nil || cur != nil {
+			return expect(false, "%v, %v before playing", cur, err)
+		}
+
+		if err = c.Play(d.Devices[0].ID, &spotify.PlayOptions{URIs: []spotify.URI{d.Tracks[9].URI}}); err != nil {
+			return err
+		}
+		if cur, err = c.GetCurrentlyPlaying(""); err != nil {
+			return err
+		}
+		return expect(cur.Item.Track.Name == "Blue in Green" && cur.Context == nil && cur.CurrentlyPlayingType == "track", "%+v", cur)
+	}},
+	{"GetDevices", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		devices, err := c.GetDevices()
+		if err != nil {
+			return err
+		}
+		return expect(len(devices) == 2 && devices[0].Name == "Test Computer" && !devices[0].IsActive, "%d devices", len(devices))
+	}},
+	{"TransferPlayback", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := play(c, d); err != nil {
+			return err
+		}
+		if err := c.TransferPlayback(d.Devices[1].ID, false); err != nil {
+			return err
+		}
+
+		state, err := c.GetPlaybackState("")
+		if err != nil {
+			return err
+		}
+		devices := srv.Devices()
+		return expect(state.Device.ID == d.Devices[1].ID && devices[1].IsActive && !devices[0].IsActive &&
+			state.Item.Track.Name == "One More Time", "%+v", state)
+	}},
+	{"Play", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.Play("", nil); status(err) != 404 {
+			return expect(false, "%v without a device", err)
+		}
+
+		opts := &spotify.PlayOptions{ContextURI: d.Playlists[1].ID.URI(spotify.KindPlaylist), Offset: &spotify.PlayOffset{URI: d.Tracks[8].URI}}
+		if err := c.Play(d.Devices[0].ID, opts); err != nil {
+			return err
+		}
+		name, err := playing(c)
+		if err != nil {
+			return err
+		}
+		return expect(name == "Freddie Freeloader", "%s", name)
+	}},
+	{"Pause", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := play(c, d); err != nil {
+			return err
+		}
+		if err := c.Pause(""); err != nil {
+			return err
+		}
+		if err := c.Pause(""); status(err) != 403 {
+			return expect(false, "%v pausing twice", err)
+		}
+
+		state, err := c.GetPlaybackState("")
+		if err != nil {
+			return err
+		}
+		return expect(!state.IsPlaying, "%+v", state)
+	}},
+	{"Next", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := play(c, d); err != nil {
+			return err
+		}
+		if err := c.Next(""); err != nil {
+			return err
+		}
+
+		name, err := playing(c)
+		if err != nil {
+			return err
+		}
+		recent, err := c.GetRecentlyPlayed(1, "")
+		if err != nil {
+			return err
+		}
+		return expect(name == "Aerodynamic" && recent.Items[0].Track.Name == "One More Time", "%s after %s", name, recent.Items[0].Track.Name)
+	}},
+	{"Previous", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		opts := &spotify.PlayOptions{ContextURI: d.Albums[0].URI, Offset: &spotify.PlayOffset{Position: 2}}
+		if err := c.Play(d.Devices[0].ID, opts); err != nil {
+			return err
+		}
+		if err := c.Previous(""); err != nil {
+			return err
+		}
+
+		name, err := playing(c)
+		if err != nil {
+			return err
+		}
+		return expect(name == "Aerodynamic", "%s", name)
+	}},
+	{"Seek", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := play(c, d); err != nil {
+			return err
+		}
+		if err := c.Pause(""); err != nil {
+			return err
+		}
+		if err := c.Seek("", 60000); err != nil {
+			return err
+		}
+
+		state, err := c.GetPlaybackState("")
+		if err != nil {
+			return err
+		}
+		return expect(state.ProgressMs == 60000, "%d ms", state.ProgressMs)
+	}},
+	{"SetRepeat", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := play(c, d); err != nil {
+			return err
+		}
+		if err := c.SetRepeat("", "always"); err == nil {
+			return expect(false, "no error for an invalid state")
+		}
+		if err := c.SetRepeat("", "context"); err != nil {
+			return err
+		}
+
+		state, err := c.GetPlaybackState("")
+		if err != nil {
+			return err
+		}
+		return expect(state.RepeatState == "context", "%s", state.RepeatState)
+	}},
+	{"SetVolume", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.SetVolume(d.Devices[1].ID, 80); err != nil {
+			return err
+		}
+		devices := srv.Devices()
+		return expect(devices[1].VolumePercent == 80 && devices[0].VolumePercent == 50, "%d and %d%%", devices[0].VolumePercent, devices[1].VolumePercent)
+	}},
+	{"SetShuffle", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := play(c, d); err != nil {
+			return err
+		}
+		if err := c.SetShuffle("", true); err != nil {
+			return err
+		}
+
+		state, err := c.GetPlaybackState("")
+		if err != nil {
+			return err
+		}
+		return expect(state.ShuffleState, "shuffle off")
+	}},
+	{"GetQueue", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := play(c, d); err != nil {
+			return err
+		}
+		if err := c.AddToQueue("", d.Tracks[9].URI); err != nil {
+			return err
+		}
+
+		q, err := c.GetQueue()
+		if err != nil {
+			return err
+		}
+		return expect(q.CurrentlyPlaying.Track.Name == "One More Time" && len(q.Queue) == 4 &&
+			q.Queue[0].Track.Name == "Blue in Green" && q.Queue[1].Track.Name == "Aerodynamic", "%d queued", len(q.Queue))
+	}},
+	{"AddToQueue", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		if err := c.AddToQueue("", d.Tracks[9].URI); status(err) != 404 {
+			return expect(false, "%v without a device", err)
+		}
+
+		if err := play(c, d); err != nil {
+			return err
+		}
+		if err := c.AddToQueue("", d.Episodes[0].URI); err != nil {
+			return err
+		}
+		if err := c.Next(""); err != nil {
+			return err
+		}
+
+		name, err := playing(c)
+		if err != nil {
+			return err
+		}
+		return expect(name == "Pilot", "%s", name)
+	}},
+	{"GetRecentlyPlayed", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		first, err := c.GetRecentlyPlayed(2, "")
+		if err != nil {
+			return err
+		}
+		if len(first.Items) != 2 || first.Items[0].Track.Name != "So What" || first.Next == "" {
+			return expect(false, "%d plays on the first page", len(first.Items))
+		}
+
+		second, err := c.GetRecentlyPlayed(2, first.Cursors.Before)
+		if err != nil {
+			return err
+		}
+		return expect(len(second.Items) == 1 && second.Items[0].Track.Name == "Blue in Green" && second.Next == "", "%d plays on the second page", len(second.Items))
+	}},
+
+	{"GetFeaturedPlaylists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetFeaturedPlaylists("", "SE", "", 20, 0)
+		if err != nil {
+			return err
+		}
+
+		var playlists []*spotify.SimplePlaylist
+		if err = items(p, &playlists); err != nil {
+			return err
+		}
+		return expect(len(playlists) == 2, "%d playlists", len(playlists))
+	}},
+	{"GetNewReleases", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetNewReleases("SE", 2, 0)
+		if err != nil {
+			return err
+		}
+
+		var albums []*spotify.SimpleAlbum
+		if err = items(p, &albums); err != nil {
+			return err
+		}
+		return expect(p.Total == 3 && len(albums) == 2, "%d of %d albums", len(albums), p.Total)
+	}},
+	{"GetCategories", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetCategories("SE", "", 0, 20)
+		if err != nil {
+			return err
+		}
+
+		var categories []*spotify.Category
+		if err = items(p, &categories); err != nil {
+			return err
+		}
+		return expect(len(categories) == 2 && categories[0].Name == "Electronic", "%d categories", len(categories))
+	}},
+	{"GetCategory", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		cat, err := c.GetCategory(d.Categories[1].ID, "", "")
+		if err != nil {
+			return err
+		}
+		return expect(cat.Name == "Jazz", "%s", cat.Name)
+	}},
+	{"GetCategoryPlaylists", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		p, err := c.GetCategoryPlaylists(d.Categories[0].ID, "", 1, 0)
+		if err != nil {
+			return err
+		}
+
+		var playlists []*spotify.SimplePlaylist
+		if err = items(p, &playlists); err != nil {
+			return err
+		}
+		return expect(p.Limit == 1 && len(playlists) == 1 && playlists[0].Name == "French Touch", "%d playlists with limit %d", len(playlists), p.Limit)
+	}},
+	{"GetRecommendations", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		rec, err := c.GetRecommendations("seed_artists="+string(d.Artists[0].ID), "limit=2")
+		if err != nil {
+			return err
+		}
+		return expect(len(rec.Tracks) == 2 && len(rec.Seeds) == 1 && rec.Seeds[0].InitialPoolSize == 4, "%d tracks and %d seeds", len(rec.Tracks), len(rec.Seeds))
+	}},
+	{"GetRecommendationsWithOptions", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		rec, err := c.GetRecommendationsWithOptions(&spotify.RecommendationOptions{SeedGenres: []string{"cool jazz"}, Limit: 5})
+		if err != nil {
+			return err
+		}
+		return expect(len(rec.Tracks) == 3 && rec.Tracks[0].Album.ID == d.Albums[2].ID, "%d tracks", len(rec.Tracks))
+	}},
+	{"AvailableGenreSeeds", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		genres, err := c.AvailableGenreSeeds()
+		if err != nil {
+			return err
+		}
+		return expect(reflect.DeepEqual(genres, []string{"cool jazz", "electro", "french house", "jazz"}), "%v", genres)
+	}},
+	{"AvailableMarkets", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		markets, err := c.AvailableMarkets()
+		if err != nil {
+			return err
+		}
+		return expect(sort.SearchStrings(markets, "SE") < len(markets), "%v", markets)
+	}},
+
+	{"Search", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		types := []spotify.SearchType{spotify.SearchTypeTrack, spotify.SearchTypeShow, spotify.SearchTypeEpisode, spotify.SearchTypeAudiobook}
+		res, err := c.Search(context.Background(), "test", types, nil)
+		if err != nil {
+			return err
+		}
+		return expect(res.Tracks.Total == 0 && res.Shows.Total == 2 && res.Episodes.Total == 0 && res.Audiobooks.Total == 2,
+			"%d tracks, %d shows, %d episodes and %d audiobooks", res.Tracks.Total, res.Shows.Total, res.Episodes.Total, res.Audiobooks.Total)
+	}},
+	{"SearchTrack", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		tracks, err := c.SearchTrack("digital", 0)
+		if err != nil {
+			return err
+		}
+		return expect(len(tracks) == 1 && tracks[0].Name == "Digital Love", "%d tracks", len(tracks))
+	}},
+	{"SearchAlbum", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		albums, err := c.SearchAlbum("cross", 0)
+		if err != nil {
+			return err
+		}
+		return expect(len(albums) == 1 && albums[0].Name == "Cross", "%d albums", len(albums))
+	}},
+	{"SearchArtist", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		artists, err := c.SearchArtist("miles", 0)
+		if err != nil {
+			return err
+		}
+		return expect(len(artists) == 1 && artists[0].Name == "Miles Davis", "%d artists", len(artists))
+	}},
+	{"TrackByISRC", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		tracks, err := c.TrackByISRC(context.Background(), d.Tracks[0].ExternalIDs.ISRC())
+		if err != nil {
+			return err
+		}
+		return expect(len(tracks) == 1 && tracks[0].ID == d.Tracks[0].ID, "%d tracks", len(tracks))
+	}},
+	{"TracksByISRC", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		res, err := c.TracksByISRC(context.Background(), []string{d.Tracks[0].ExternalIDs.ISRC(), "TEST99999999"})
+		if err != nil {
+			return err
+		}
+		return expect(len(res.Matches) == 1 && reflect.DeepEqual(res.Unmatched, []string{"TEST99999999"}), "%d matches, unmatched %v", len(res.Matches), res.Unmatched)
+	}},
+	{"AlbumByUPC", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		albums, err := c.AlbumByUPC(context.Background(), d.Albums[0].ExternalIDs.UPC())
+		if err != nil {
+			return err
+		}
+		return expect(len(albums) == 1 && albums[0].ID == d.Albums[0].ID, "%d albums", len(albums))
+	}},
+	{"AlbumsByUPC", func(c *spotify.Client, srv *spotifytest.Server, d *spotifytest.Dataset) error {
+		res, err := c.AlbumsByUPC(context.Background(), []string{d.Albums[0].ExternalIDs.UPC(), "999999999999"})
+		if err != nil {
+			return err
+		}
+		return expect(len(res.Matches) == 1 && reflect.DeepEqual(res.Unmatched, []string{"999999999999"}), "%d matches, unmatched %v", len(res.Matches), res.Unmatched)
+	}},
+}
+
+func TestClientMethods(t *testing.T) {
+	for _, tt := range clientCalls {
+		t.Run(tt.method, func(t *testing.T) {
+			d := dataset()
+			srv := spotifytest.NewServer(d)
+			defer srv.Close()
+
+			if err := tt.run(srv.Client(), srv, d); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestEveryClientMethod makes sure a method added to the client is also served by the fake
+func TestEveryClientMethod(t *testing.T) {
+	tested := make(map[string]bool)
+	for _, tt := range clientCalls {
+		tested[tt.method] = true
+	}
+
+	typ := reflect.TypeOf(&spotify.Client{})
+	for i := 0; i < typ.NumMethod(); i++ {
+		if name := typ.Method(i).Name; !tested[name] {
+			t.Errorf("%s isn't run against the server", name)
+		}
+	}
+}
@@ -1,39 +1,55 @@
 package spotify
 
-import "encoding/json"
-
-type Album struct {
-	AlbumType            string        `json:"album_type"`
-	Artists              []*Artist     `json:"artists"`
-	AvailableMarkest     []string      `json:"available_markets"`
-	Copyrights           []*Copyright  `json:"copyrights"`
-	ExternalIDs          *ExternalIDs  `json:"external_ids"`
-	ExternalURLs         *ExternalURLs `json:"external_urls"`
-	Genres               []string      `json:"genres"`
-	Href                 string        `json:"href"`
-	ID                   string        `json:"id"`
-	Images               []*Image      `json:"images"`
-	Label                string        `json:"label"`
-	Name                 string        `json:"name"`
-	Popularity           int           `json:"popularity"`
-	ReleaseDate          string        `json:"release_date"`
-	ReleaseDatePrecision string        `json:"release_date_precision"`
-	Tracks               *Paging       `json:"tracks"`
-	Type                 string        `json:"type"`
-	URI                  string        `json:"uri"`
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SimpleAlbum is the simplified album object, as found in tracks, artist albums and search results
+type SimpleAlbum struct {
+	AlbumGroup           string          `json:"album_group,omitempty"`
+	AlbumType            string          `json:"album_type"`
+	Artists              []*SimpleArtist `json:"artists"`
+	AvailableMarkets     []string        `json:"available_markets"`
+	ExternalURLs         *ExternalURLs   `json:"external_urls"`
+	Href                 string          `json:"href"`
+	ID                   ID              `json:"id"`
+	Images               []*Image        `json:"images"`
+	Name                 string          `json:"name"`
+	ReleaseDate          ReleaseDate     `json:"release_date"`
+	ReleaseDatePrecision DatePrecision   `json:"release_date_precision"`
+	TotalTracks          int             `json:"total_tracks"`
+	Type                 string          `json:"type"`
+	URI                  URI             `json:"uri"`
 }
 
-type Artist struct {
-	ExternalURLs *ExternalURLs `json:"external_url"`
-	Followers    *Follower     `json:"followers"`
-	Genres       []string      `json:"genres"`
+// FullAlbum is the full album object, the items of its Tracks page are SimpleTracks
+type FullAlbum struct {
+	SimpleAlbum
+	Copyrights  []*Copyright `json:"copyrights"`
+	ExternalIDs *ExternalIDs `json:"external_ids"`
+	Genres      []string     `json:"genres"`
+	Label       string       `json:"label"`
+	Popularity  int          `json:"popularity"`
+	Tracks      *Paging      `json:"tracks"`
+}
+
+// SimpleArtist is the simplified artist object, as found in albums and tracks
+type SimpleArtist struct {
+	ExternalURLs *ExternalURLs `json:"external_urls"`
 	Href         string        `json:"href"`
-	ID           string        `json:"id"`
-	Images       []*Image      `json:"images"`
+	ID           ID            `json:"id"`
 	Name         string        `json:"name"`
-	Popularity   int           `json:"popularity"`
 	Type         string        `json:"type"`
-	URI          string        `json:"uri"`
+	URI          URI           `json:"uri"`
+}
+
+type FullArtist struct {
+	SimpleArtist
+	Followers  *Follower `json:"followers"`
+	Genres     []string  `json:"genres"`
+	Images     []*Image  `json:"images"`
+	Popularity int       `json:"popularity"`
 }
 
 type AudioFeatures struct {
@@ -42,7 +58,7 @@ type AudioFeatures struct {
 	Danceabilitu     float32 `json:"danceability"`
 	DurationMs       int     `json:"duration_ms"`
 	Energy           float32 `json:"energy"`
-	ID               string  `json:"id"`
+	ID               ID      `json:"id"`
 	Instrumentalness float32 `json:"instrumentalness"`
 	Key              int     `json:"key"`
 	Liveness         float32 `json:"liveness"`
@@ -53,67 +69,119 @@ type AudioFeatures struct {
 	TimeSignature    int     `json:"time_signature"`
 	TrackHref        string  `json:"track_href"`
 	Type             string  `json:"type"`
-	URI              string  `json:"uri"`
+	URI              URI     `json:"uri"`
 	Valence          float32 `json:"valence"`
 }
 
 type AudioAnalysis struct {
-	Bars []*struct {
-		Start      float32 `json:"start"`
-		Duration   float32 `json:"duration"`
-		Confidence float32 `json:"confidence"`
-	} `json:"bars"`
-
-	Beats []*struct {
-		Start      float32 `json:"start"`
-		Duration   float32 `json:"duration"`
-		Confidence float32 `json:"confidence"`
-	} `json:"beats"`
-
-	Meta *struct {
-		AnalyzerVersion string  `json:"analyzer_version"`
-		Platform        string  `json:"platform"`
-		DetailedStatus  string  `json:"detailed_status"`
-		StatusCode      int     `json:"status_code"`
-		Timestamp       int     `json:"timestamp"`
-		AnalysisTime    float32 `json:"analysis_time"`
-		InputProcess    string  `json:"input_process"`
-	} `json:"meta"`
-
-	Sections []*struct {
-		Start                   float32 `json:"start"`
-		Duration                float32 `json:"duration"`
-		Confidence              float32 `json:"float32"`
-		Loudness                float32 `json:"loudness"`
-		Tempo                   float32 `json:"tempo"`
-		TempoConfidence         float32 `json:"tempo_confidence"`
-		Key                     int     `json:"key"`
-		KeyConfidence           float32 `json:"key_confidence"`
-		Mode                    int     `json:"mode"`
-		ModeConfidence          float32 `json:"mode_confidence"`
-		TimeSignature           int     `json:"time_signature"`
-		TimeSignatureConfidence float32 `json:"time_signature_confidence"`
-	} `json:"sections"`
-
-	Segments []*struct {
-		Start           float32   `json:"start"`
-		Duration        float32   `json:"duration"`
-		Confidence      float32   `json:"confidence"`
-		LoudnessStart   float32   `json:"loudenss_start"`
-		LoudnessMaxTime float32   `json:"loudness_max_time"`
-		LoudnessMax     float32   `json:"loudness_max"`
-		LoudnessEnd     float32   `json:"loudness_end"`
-		Pitches         []float32 `json:"pitches"`
-		Timbre          []float32 `json:"timbre"`
-	} `json:"segments"`
-
-	Tatums []*struct {
-		Start      float32 `json:"start"`
-		Duration   float32 `json:"duration"`
-		Confidence float32 `json:"confidence"`
-	} `json:"tatums"`
-
-	Track *Track `json:"track"`
+	Bars     []*Interval    `json:"bars"`
+	Beats    []*Interval    `json:"beats"`
+	Meta     *AnalysisMeta  `json:"meta"`
+	Sections []*Section     `json:"sections"`
+	Segments []*Segment     `json:"segments"`
+	Tatums   []*Interval    `json:"tatums"`
+	Track    *TrackAnalysis `json:"track"`
+}
+
+// Interval is a bar, beat or tatum of an AudioAnalysis, times are in seconds
+type Interval struct {
+	Start      float32 `json:"start"`
+	Duration   float32 `json:"duration"`
+	Confidence float32 `json:"confidence"`
+}
+
+type AnalysisMeta struct {
+	AnalyzerVersion string  `json:"analyzer_version"`
+	Platform        string  `json:"platform"`
+	DetailedStatus  string  `json:"detailed_status"`
+	StatusCode      int     `json:"status_code"`
+	Timestamp       int     `json:"timestamp"`
+	AnalysisTime    float32 `json:"analysis_time"`
+	InputProcess    string  `json:"input_process"`
+}
+
+type Section struct {
+	Start                   float32 `json:"start"`
+	Duration                float32 `json:"duration"`
+	Confidence              float32 `json:"confidence"`
+	Loudness                float32 `json:"loudness"`
+	Tempo                   float32 `json:"tempo"`
+	TempoConfidence         float32 `json:"tempo_confidence"`
+	Key                     int     `json:"key"`
+	KeyConfidence           float32 `json:"key_confidence"`
+	Mode                    int     `json:"mode"`
+	ModeConfidence          float32 `json:"mode_confidence"`
+	TimeSignature           int     `json:"time_signature"`
+	TimeSignatureConfidence float32 `json:"time_signature_confidence"`
+}
+
+type Segment struct {
+	Start           float32   `json:"start"`
+	Duration        float32   `json:"duration"`
+	Confidence      float32   `json:"confidence"`
+	LoudnessStart   float32   `json:"loudness_start"`
+	LoudnessMaxTime float32   `json:"loudness_max_time"`
+	LoudnessMax     float32   `json:"loudness_max"`
+	LoudnessEnd     float32   `json:"loudness_end"`
+	Pitches         []float32 `json:"pitches"`
+	Timbre          []float32 `json:"timbre"`
+}
+
+// TrackAnalysis is the track level summary of an AudioAnalysis, not a catalog track
+type TrackAnalysis struct {
+	NumSamples              int     `json:"num_samples"`
+	Duration                float32 `json:"duration"`
+	SampleMD5               string  `json:"sample_md5"`
+	OffsetSeconds           float32 `json:"offset_seconds"`
+	WindowSeconds           float32 `json:"window_seconds"`
+	AnalysisSampleRate      int     `json:"analysis_sample_rate"`
+	AnalysisChannels        int     `json:"analysis_channels"`
+	EndOfFadeIn             float32 `json:"end_of_fade_in"`
+	StartOfFadeOut          float32 `json:"start_of_fade_out"`
+	Loudness                float32 `json:"loudness"`
+	Tempo                   float32 `json:"tempo"`
+	TempoConfidence         float32 `json:"tempo_confidence"`
+	TimeSignature           int     `json:"time_signature"`
+	TimeSignatureConfidence float32 `json:"time_signature_confidence"`
+	Key                     int     `json:"key"`
+	KeyConfidence           float32 `json:"key_confidence"`
+	Mode                    int     `json:"mode"`
+	ModeConfidence          float32 `json:"mode_confidence"`
+	Codestring              string  `json:"codestring"`
+	CodeVersion             float32 `json:"code_version"`
+	Echoprintstring         string  `json:"echoprintstring"`
+	EchoprintVersion        float32 `json:"echoprint_version"`
+	Synchstring             string  `json:"synchstring"`
+	SynchVersion            float32 `json:"synch_version"`
+	Rhythmstring            string  `json:"rhythmstring"`
+	RhythmVersion           float32 `json:"rhythm_version"`
+}
+
+type Audiobook struct {
+	Authors          []*Author     `json:"authors"`
+	AvailableMarkets []string      `json:"available_markets"`
+	Chapters         *Paging       `json:"chapters"`
+	Copyrights       []*Copyright  `json:"copyrights"`
+	Description      string        `json:"description"`
+	Edition          string        `json:"edition"`
+	Explicit         bool          `json:"explicit"`
+	ExternalURLs     *ExternalURLs `json:"external_urls"`
+	Href             string        `json:"href"`
+	HTMLDescription  string        `json:"html_description"`
+	ID               ID            `json:"id"`
+	Images           []*Image      `json:"images"`
+	Languages        []string      `json:"languages"`
+	MediaType        string        `json:"media_type"`
+	Name             string        `json:"name"`
+	Narrators        []*Narrator   `json:"narrators"`
+	Publisher        string        `json:"publisher"`
+	TotalChapters    int           `json:"total_chapters"`
+	Type             string        `json:"type"`
+	URI              URI           `json:"uri"`
+}
+
+type Author struct {
+	Name string `json:"name"`
 }
 
 type Category struct {
@@ -123,13 +191,60 @@ type Category struct {
 	Name  string   `json:"name"`
 }
 
+type Chapter struct {
+	AudioPreviewURL      string        `json:"audio_preview_url"`
+	Audiobook            *Audiobook    `json:"audiobook"`
+	AvailableMarkets     []string      `json:"available_markets"`
+	ChapterNumber        int           `json:"chapter_number"`
+	Description          string        `json:"description"`
+	DurationMs           int           `json:"duration_ms"`
+	Explicit             bool          `json:"explicit"`
+	ExternalURLs         *ExternalURLs `json:"external_urls"`
+	Href                 string        `json:"href"`
+	HTMLDescription      string        `json:"html_description"`
+	ID                   ID            `json:"id"`
+	Images               []*Image      `json:"images"`
+	IsPlayable           bool          `json:"is_playable"`
+	Languages            []string      `json:"languages"`
+	Name                 string        `json:"name"`
+	ReleaseDate          ReleaseDate   `json:"release_date"`
+	ReleaseDatePrecision DatePrecision `json:"release_date_precision"`
+	ResumePoint          *ResumePoint  `json:"resume_point"`
+	Type                 string        `json:"type"`
+	URI                  URI           `json:"uri"`
+}
+
 type Copyright struct {
 	Text string `json:"text"`
 	Type string `json:"type"`
 }
 
+// CurrentlyPlaying is what plays on the user's active device
+// Item is nil when nothing plays or an ad does.
+type CurrentlyPlaying struct {
+	Context              *PlaybackContext `json:"context"`
+	Timestamp            int64            `json:"timestamp"`
+	ProgressMs           int              `json:"progress_ms"`
+	IsPlaying            bool             `json:"is_playing"`
+	Item                 *PlaylistItem    `json:"item"`
+	CurrentlyPlayingType string           `json:"currently_playing_type"`
+}
+
 type Cursor struct {
-	After string `json:"after"`
+	After  string `json:"after"`
+	Before string `json:"before,omitempty"`
+}
+
+// Device is a device of the user that can play, its ID is not a base62 ID
+type Device struct {
+	ID               ID     `json:"id"`
+	IsActive         bool   `json:"is_active"`
+	IsPrivateSession bool   `json:"is_private_session"`
+	IsRestricted     bool   `json:"is_restricted"`
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	VolumePercent    int    `json:"volume_percent"`
+	SupportsVolume   bool   `json:"supports_volume"`
 }
 
 type SpotifyError struct {
@@ -137,25 +252,109 @@ type SpotifyError struct {
 	Message string `json:"message"`
 }
 
+func (e *SpotifyError) Error() string {
+	return fmt.Sprintf("spotify: %d %s", e.Status, e.Message)
+}
+
+// ExternalIDs are the known external identifiers of an object, keyed by type, e.g: "isrc", "ean", "upc"
 type ExternalIDs struct {
 	IDs map[string]string `json:"-"`
 }
 
+func (e *ExternalIDs) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &e.IDs)
+}
+
+func (e ExternalIDs) MarshalJSON() ([]byte, error) {
+	if e.IDs == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(e.IDs)
+}
+
+// Get returns the identifier of the type, or "" if there is none
+// It's safe to call on a nil *ExternalIDs
+func (e *ExternalIDs) Get(typ string) string {
+	if e == nil {
+		return ""
+	}
+	return e.IDs[typ]
+}
+
+// ISRC returns the International Standard Recording Code
+func (e *ExternalIDs) ISRC() string { return e.Get("isrc") }
+
+// EAN returns the International Article Number
+func (e *ExternalIDs) EAN() string { return e.Get("ean") }
+
+// UPC returns the Universal Product Code
+func (e *ExternalIDs) UPC() string { return e.Get("upc") }
+
+// ExternalURLs are the known external links of an object, keyed by type, e.g: "spotify"
 type ExternalURLs struct {
 	URLs map[string]string `json:"-"`
 }
 
+func (e *ExternalURLs) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &e.URLs)
+}
+
+func (e ExternalURLs) MarshalJSON() ([]byte, error) {
+	if e.URLs == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(e.URLs)
+}
+
+// Get returns the link of the type, or "" if there is none
+// It's safe to call on a nil *ExternalURLs
+func (e *ExternalURLs) Get(typ string) string {
+	if e == nil {
+		return ""
+	}
+	return e.URLs[typ]
+}
+
+// Spotify returns the open.spotify.com link
+func (e *ExternalURLs) Spotify() string { return e.Get("spotify") }
+
 type Follower struct {
 	Href  string `json:"href"`
 	Total int    `json:"total"`
 }
 
+type Episode struct {
+	AudioPreviewURL      string        `json:"audio_preview_url"`
+	Description          string        `json:"description"`
+	DurationMs           int           `json:"duration_ms"`
+	Explicit             bool          `json:"explicit"`
+	ExternalURLs         *ExternalURLs `json:"external_urls"`
+	Href                 string        `json:"href"`
+	HTMLDescription      string        `json:"html_description"`
+	ID                   ID            `json:"id"`
+	Images               []*Image      `json:"images"`
+	IsExternallyHosted   bool          `json:"is_externally_hosted"`
+	IsPlayable           bool          `json:"is_playable"`
+	Languages            []string      `json:"languages"`
+	Name                 string        `json:"name"`
+	ReleaseDate          ReleaseDate   `json:"release_date"`
+	ReleaseDatePrecision DatePrecision `json:"release_date_precision"`
+	ResumePoint          *ResumePoint  `json:"resume_point"`
+	Show                 *Show         `json:"show"`
+	Type                 string        `json:"type"`
+	URI                  URI           `json:"uri"`
+}
+
 type Image struct {
 	Height int    `json:"height"`
 	Width  int    `json:"width"`
 	URL    string `json:"url"`
 }
 
+type Narrator struct {
+	Name string `json:"name"`
+}
+
 type Paging struct {
 	Href     string          `json:"href"`
 	Items    json.RawMessage `json:"items"`
@@ -166,6 +365,53 @@ type Paging struct {
 	Total    int             `json:"total"`
 }
 
+// The typed pages decode the items of a Paging into a slice of the item type
+
+type AlbumPage struct {
+	Paging
+	Items []*SimpleAlbum `json:"items"`
+}
+
+type ArtistPage struct {
+	Paging
+	Items []*FullArtist `json:"items"`
+}
+
+type AudiobookPage struct {
+	Paging
+	Items []*Audiobook `json:"items"`
+}
+
+type EpisodePage struct {
+	Paging
+	Items []*Episode `json:"items"`
+}
+
+type PlaylistPage struct {
+	Paging
+	Items []*SimplePlaylist `json:"items"`
+}
+
+type ShowPage struct {
+	Paging
+	Items []*Show `json:"items"`
+}
+
+type TrackPage struct {
+	Paging
+	Items []*FullTrack `json:"items"`
+}
+
+// ArtistCursorPage is a cursor based page of artists, as returned for the followed artists
+type ArtistCursorPage struct {
+	Href    string        `json:"href"`
+	Items   []*FullArtist `json:"items"`
+	Limit   int           `json:"limit"`
+	Next    string        `json:"next"`
+	Cursors *Cursor       `json:"cursors"`
+	Total   int           `json:"total"`
+}
+
 type CursorBasedPaging struct {
 	Href   string        `json:"href"`
 	Items  []interface{} `json:"items"`
@@ -175,13 +421,46 @@ type CursorBasedPaging struct {
 	Total  int           `json:"total"`
 }
 
-type Playlist struct {
+// PlaybackContext is the album, artist, playlist or show the playback was started from
+type PlaybackContext struct {
+	Type         string        `json:"type"`
+	Href         string        `json:"href"`
+	ExternalURLs *ExternalURLs `json:"external_urls"`
+	URI          URI           `json:"uri"`
+}
+
+// PlaybackState is the playback on the user's active device
+type PlaybackState struct {
+	CurrentlyPlaying
+	Device       *Device `json:"device"`
+	RepeatState  string  `json:"repeat_state"`
+	ShuffleState bool    `json:"shuffle_state"`
+}
+
+// PlayHistory is a track the user played
+type PlayHistory struct {
+	Track    *FullTrack       `json:"track"`
+	PlayedAt string           `json:"played_at"`
+	Context  *PlaybackContext `json:"context"`
+}
+
+// PlayHistoryCursorPage is a cursor based page of the recently played tracks, newest first
+type PlayHistoryCursorPage struct {
+	Href    string         `json:"href"`
+	Items   []*PlayHistory `json:"items"`
+	Limit   int            `json:"limit"`
+	Next    string         `json:"next"`
+	Cursors *Cursor        `json:"cursors"`
+}
+
+// SimplePlaylist is the simplified playlist object, as found in browse and search results
+// Its Tracks page only has the Href and Total set
+type SimplePlaylist struct {
 	Collaborative bool          `json:"collaborative"`
 	Description   string        `json:"description"`
 	ExternalURLs  *ExternalURLs `json:"external_urls"`
-	Followers     *Follower     `json:"followers"`
 	Href          string        `json:"href"`
-	ID            string        `json:"id"`
+	ID            ID            `json:"id"`
 	Images        []*Image      `json:"images"`
 	Name          string        `json:"name"`
 	Owner         *User         `json:"owner"`
@@ -189,19 +468,67 @@ type Playlist struct {
 	SnapshotID    string        `json:"snapshot_id"`
 	Tracks        *Paging       `json:"tracks"`
 	Type          string        `json:"type"`
-	URI           string        `json:"uri"`
+	URI           URI           `json:"uri"`
+}
+
+// FullPlaylist is the full playlist object, the items of its Tracks page are PlaylistTracks
+type FullPlaylist struct {
+	SimplePlaylist
+	Followers *Follower `json:"followers"`
 }
 
 type PlaylistTrack struct {
-	AddedAt string `json:"added_at"`
-	AddedBy *User  `json:"added_by"`
-	IsLocal bool   `json:"is_local"`
-	Track   *Track `json:"track"`
+	AddedAt string        `json:"added_at"`
+	AddedBy *User         `json:"added_by"`
+	IsLocal bool          `json:"is_local"`
+	Track   *PlaylistItem `json:"track"`
+}
+
+// PlaylistItem is the item of a playlist entry, which is either a track or a podcast episode.
+// Exactly one of Track and Episode is set.
+type PlaylistItem struct {
+	Track   *FullTrack
+	Episode *Episode
+}
+
+func (p *PlaylistItem) UnmarshalJSON(data []byte) error {
+	var typ struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &typ); err != nil {
+		return err
+	}
+
+	if typ.Type == "episode" {
+		p.Track, p.Episode = nil, &Episode{}
+		return json.Unmarshal(data, p.Episode)
+	}
+
+	p.Track, p.Episode = &FullTrack{}, nil
+	return json.Unmarshal(data, p.Track)
+}
+
+func (p PlaylistItem) MarshalJSON() ([]byte, error) {
+	if p.Episode != nil {
+		return json.Marshal(p.Episode)
+	}
+	return json.Marshal(p.Track)
+}
+
+// Queue is the item playing and the items that play after it
+type Queue struct {
+	CurrentlyPlaying *PlaylistItem   `json:"currently_playing"`
+	Queue            []*PlaylistItem `json:"queue"`
 }
 
 type Recommendations struct {
 	Seeds  []*RecommendationSeed `json:"seeds"`
-	Tracks []*Track              `json:"tracks"`
+	Tracks []*FullTrack          `json:"tracks"`
+}
+
+type ResumePoint struct {
+	FullyPlayed      bool `json:"fully_played"`
+	ResumePositionMs int  `json:"resume_position_ms"`
 }
 
 type RecommendationSeed struct {
@@ -214,42 +541,84 @@ type RecommendationSeed struct {
 }
 
 type SavedTrack struct {
-	AddedAt string `json:"added_at"`
-	Track   *Track `json:"track"`
+	AddedAt string     `json:"added_at"`
+	Track   *FullTrack `json:"track"`
 }
 
 type SavedAlbum struct {
+	AddedAt string     `json:"added_at"`
+	Album   *FullAlbum `json:"album"`
+}
+
+type SavedAudiobook struct {
+	AddedAt   string     `json:"added_at"`
+	Audiobook *Audiobook `json:"audiobook"`
+}
+
+type SavedShow struct {
 	AddedAt string `json:"added_at"`
-	Album   *Album `json:"album"`
+	Show    *Show  `json:"show"`
 }
 
-type Track struct {
-	Album            *Album        `json:"album"`
-	Artists          []*Artist     `json:"artists"`
-	AvailableMarkets []string      `json:"available_markets"`
-	DiscNumber       int           `json:"disc_number"`
-	DurationMs       int           `json:"duration_ms"`
-	Explicit         bool          `json:"explicit"`
-	ExternalIDs      *ExternalIDs  `json:"external_ids"`
-	ExternalURLs     *ExternalURLs `json:"external_urls"`
-	Href             string        `json:"href"`
-	ID               string        `json:"id"`
-	IsPlayable       bool          `json:"is_playable"`
-	LinkedFrom       *LinkedTrack  `json:"linked_from"`
-	Name             string        `json:"name"`
-	Popularity       int           `json:"popularity"`
-	PreviewURL       string        `json:"preview_url"`
-	TrackNumber      int           `json:"track_number"`
-	Type             string        `json:"type"`
-	URI              string        `json:"uri"`
+type SavedEpisode struct {
+	AddedAt string   `json:"added_at"`
+	Episode *Episode `json:"episode"`
+}
+
+type Show struct {
+	AvailableMarkets   []string      `json:"available_markets"`
+	Copyrights         []*Copyright  `json:"copyrights"`
+	Description        string        `json:"description"`
+	Episodes           *Paging       `json:"episodes"`
+	Explicit           bool          `json:"explicit"`
+	ExternalURLs       *ExternalURLs `json:"external_urls"`
+	Href               string        `json:"href"`
+	HTMLDescription    string        `json:"html_description"`
+	ID                 ID            `json:"id"`
+	Images             []*Image      `json:"images"`
+	IsExternallyHosted bool          `json:"is_externally_hosted"`
+	Languages          []string      `json:"languages"`
+	MediaType          string        `json:"media_type"`
+	Name               string        `json:"name"`
+	Publisher          string        `json:"publisher"`
+	TotalEpisodes      int           `json:"total_episodes"`
+	Type               string        `json:"type"`
+	URI                URI           `json:"uri"`
+}
+
+// SimpleTrack is the simplified track object, as found in albums
+type SimpleTrack struct {
+	Artists          []*SimpleArtist `json:"artists"`
+	AvailableMarkets []string        `json:"available_markets"`
+	DiscNumber       int             `json:"disc_number"`
+	DurationMs       int             `json:"duration_ms"`
+	Explicit         bool            `json:"explicit"`
+	ExternalURLs     *ExternalURLs   `json:"external_urls"`
+	Href             string          `json:"href"`
+	ID               ID              `json:"id"`
+	IsLocal          bool            `json:"is_local"`
+	IsPlayable       bool            `json:"is_playable"`
+	LinkedFrom       *LinkedTrack    `json:"linked_from"`
+	Name             string          `json:"name"`
+	PreviewURL       string          `json:"preview_url"`
+	TrackNumber      int             `json:"track_number"`
+	Type             string          `json:"type"`
+	URI              URI             `json:"uri"`
+}
+
+type FullTrack struct {
+	SimpleTrack
+	Album       *SimpleAlbum `json:"album"`
+	ExternalIDs *ExternalIDs `json:"external_ids"`
+	Popularity  int          `json:"popularity"`
 }
 
 type LinkedTrack struct {
 	ExternalURLs *ExternalURLs `json:"external_urls"`
 	Href         string        `json:"href"`
-	ID           string        `json:"id"`
+	ID           ID            `json:"id"`
 	Type         string        `json:"type"`
-	URI          string        `json:"uri"`
+	URI          URI           `json:"uri"`
 }
 
 type User struct {
@@ -260,11 +629,11 @@ type User struct {
 	ExternalURLs *ExternalURLs `json:"external_urls"`
 	Followers    *Follower     `json:"followers"`
 	Href         string        `json:"href"`
-	ID           string        `json:"id"`
+	ID           ID            `json:"id"`
 	Images       []*Image      `json:"images"`
 	Product      string        `json:"product"`
 	Type         string        `json:"type"`
-	URI          string        `json:"uri"`
+	URI          URI           `json:"uri"`
 }
 
 type auth struct {
@@ -0,0 +1,159 @@
+package spotify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestModelRoundTrip(t *testing.T) {
+	tests := []struct {
+		fixture string
+		model   func() interface{}
+	}{
+		{"simple_album", func() interface{} { return &SimpleAlbum{} }},
+		{"full_album", func() interface{} { return &FullAlbum{} }},
+		{"simple_artist", func() interface{} { return &SimpleArtist{} }},
+		{"full_artist", func() interface{} { return &FullArtist{} }},
+		{"audio_features", func() interface{} { return &AudioFeatures{} }},
+		{"audio_analysis", func() interface{} { return &AudioAnalysis{} }},
+		{"audiobook", func() interface{} { return &Audiobook{} }},
+		{"category", func() interface{} { return &Category{} }},
+		{"chapter", func() interface{} { return &Chapter{} }},
+		{"episode", func() interface{} { return &Episode{} }},
+		{"show", func() interface{} { return &Show{} }},
+		{"simple_track", func() interface{} { return &SimpleTrack{} }},
+		{"full_track", func() interface{} { return &FullTrack{} }},
+		{"simple_playlist", func() interface{} { return &SimplePlaylist{} }},
+		{"full_playlist", func() interface{} { return &FullPlaylist{} }},
+		{"playlist_track_track", func() interface{} { return &PlaylistTrack{} }},
+		{"playlist_track_episode", func() interface{} { return &PlaylistTrack{} }},
+		{"recommendations", func() interface{} { return &Recommendations{} }},
+		{"saved_track", func() interface{} { return &SavedTrack{} }},
+		{"saved_album", func() interface{} { return &SavedAlbum{} }},
+		{"saved_audiobook", func() interface{} { return &SavedAudiobook{} }},
+		{"saved_show", func() interface{} { return &SavedShow{} }},
+		{"saved_episode", func() interface{} { return &SavedEpisode{} }},
+		{"user", func() interface{} { return &User{} }},
+		{"album_page", func() interface{} { return &AlbumPage{} }},
+		{"artist_page", func() interface{} { return &ArtistPage{} }},
+		{"audiobook_page", func() interface{} { return &AudiobookPage{} }},
+		{"episode_page", func() interface{} { return &EpisodePage{} }},
+		{"playlist_page", func() interface{} { return &PlaylistPage{} }},
+		{"show_page", func() interface{} { return &ShowPage{} }},
+		{"track_page", func() interface{} { return &TrackPage{} }},
+		{"artist_cursor_page", func() interface{} { return &ArtistCursorPage{} }},
+		{"error", func() interface{} { return &SpotifyError{} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.fixture+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			first := tt.model()
+			if err := json.Unmarshal(data, first); err != nil {
+				t.Fatalf("unmarshal fixture: %v", err)
+			}
+
+			out, err := json.Marshal(first)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+
+			// every field of the fixture has to survive, models may add zero values for fields it leaves out
+			var want, got interface{}
+			if err := json.Unmarshal(data, &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := contains(got, want, "$"); err != nil {
+				t.Error(err)
+			}
+
+			// and marshaling again has to be stable
+			second := tt.model()
+			if err := json.Unmarshal(out, second); err != nil {
+				t.Fatalf("unmarshal marshaled: %v", err)
+			}
+			again, err := json.Marshal(second)
+			if err != nil {
+				t.Fatalf("marshal again: %v", err)
+			}
+			if string(again) != string(out) {
+				t.Errorf("round-trip changed the model:\n%s\n%s", out, again)
+			}
+		})
+	}
+}
+
+func TestExternalAccessors(t *testing.T) {
+	track := &FullTrack{}
+	data, err := os.ReadFile(filepath.Join("testdata", "full_track.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, track); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := track.ExternalIDs.ISRC(); got != "USSM15900113" {
+		t.Errorf("ISRC() = %q", got)
+	}
+	if got := track.ExternalURLs.Spotify(); got != "https://open.spotify.com/track/4vLYewWIvqHfKtJDk8c8tq" {
+		t.Errorf("Spotify() = %q", got)
+	}
+	if got := track.Artists[0].ExternalURLs.Spotify(); got != "https://open.spotify.com/artist/0kbYTNQb4Pb1rPbbaF0pT4" {
+		t.Errorf("artist Spotify() = %q", got)
+	}
+
+	var ids *ExternalIDs
+	var urls *ExternalURLs
+	if ids.UPC() != "" || urls.Spotify() != "" {
+		t.Error("accessors on nil should be empty")
+	}
+}
+
+// contains reports the first value of want that is missing or different in got
+func contains(got, want interface{}, path string) error {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: got %v, want an object", path, got)
+		}
+		for k, v := range w {
+			gv, ok := g[k]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", path, k)
+			}
+			if err := contains(gv, v, path+"."+k); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return fmt.Errorf("%s: got %v, want %v", path, got, want)
+		}
+		for i := range w {
+			if err := contains(g[i], w[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("%s: got %v, want %v", path, got, want)
+		}
+		return nil
+	}
+}